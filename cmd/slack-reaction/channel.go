@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/Tattsum/slack-reaction/internal/chart"
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/store"
+	"github.com/slack-go/slack"
+)
+
+// runChannel は channel コマンドを実行する
+func runChannel(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("channel", "[オプション] <チャンネル名>", stderr)
+	flags := addAnalysisFlags(fs)
+	flags.allowFormats(report.FormatJSON, report.FormatCSV, report.FormatMarkdown, report.FormatHTML)
+	flags.allowTemplate()
+	flags.allowImages()
+	flags.allowOutput()
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	opts, err := flags.resolve(report.DefaultChannelLimits)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		positional = opts.channels
+	}
+	if len(positional) != 1 {
+		return newUsageError("チャンネル名を1つ指定してください（複数チャンネルは channels コマンドを使用してください）")
+	}
+
+	return analyzeChannel(ctx, trimChannelName(positional[0]), opts, stdout, stderr)
+}
+
+// analyzeChannel はチャンネルを分析して結果を出力する
+func analyzeChannel(ctx context.Context, name string, opts *analysisOptions, stdout, stderr io.Writer) error {
+	a, err := newAnalysisApp(ctx, opts, stderr)
+	if err != nil {
+		return err
+	}
+	rep, err := channelReport(ctx, a, name, opts)
+	if err != nil {
+		return err
+	}
+	return writeReport(ctx, a, opts, rep, stdout, stderr)
+}
+
+// channelReport はチャンネルを分析する
+func channelReport(ctx context.Context, a *app, name string, opts *analysisOptions) (*analysisReport, error) {
+	channel, err := a.channelRepo.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.analyzer.AnalyzeChannel(ctx, channel.ID, opts.dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	title := "#" + channel.Name + " の分析結果"
+	return &analysisReport{
+		title: title,
+		json: func(w io.Writer, meta report.Metadata) error {
+			meta.Channel = &report.Target{ID: channel.ID, Name: channel.Name}
+			return report.WriteChannelJSON(w, meta, result, opts.limits)
+		},
+		csv: func() []report.CSVSection {
+			return report.ChannelCSVSections(result, opts.limits)
+		},
+		markdown: func(w io.Writer, md report.MarkdownOptions) error {
+			return report.WriteChannelMarkdown(w, title, opts.dateRange, result, opts.limits, md)
+		},
+		html: func(w io.Writer, meta report.Metadata, link report.MessageLinker) error {
+			meta.Channel = &report.Target{ID: channel.ID, Name: channel.Name}
+			return report.WriteChannelHTML(w, title, meta, result, link)
+		},
+		template: func(meta report.Metadata) *report.TemplateData {
+			meta.Channel = &report.Target{ID: channel.ID, Name: channel.Name}
+			return report.NewChannelTemplateData(meta, title, result, opts.limits)
+		},
+		charts: func() []*chart.Chart {
+			return chart.FromAnalysisResult(result, opts.dateRange, opts.limits)
+		},
+		dataset: func() *store.Dataset {
+			return &store.Dataset{Channels: []*domain.Channel{channel}, Messages: result.Messages}
+		},
+		blocks: func(link report.MessageLinker) []slack.Block {
+			return report.ChannelBlocks(title, opts.dateRange, result, opts.limits, link)
+		},
+		warnings: result.Warnings,
+	}, nil
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Tattsum/slack-reaction/internal/chart"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/Tattsum/slack-reaction/internal/store"
+	"github.com/slack-go/slack"
+)
+
+// runChannels は channels コマンドを実行する
+func runChannels(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("channels", "[オプション] <チャンネル名またはパターン>...", stderr)
+	flags := addAnalysisFlags(fs)
+	flags.allowFormats(report.FormatJSON, report.FormatCSV, report.FormatMarkdown, report.FormatHTML)
+	flags.allowExcludeChannels()
+	flags.allowTemplate()
+	flags.allowImages()
+	flags.allowOutput()
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	opts, err := flags.resolve(report.DefaultChannelLimits)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 {
+		positional = opts.channels
+	}
+	if len(positional) == 0 {
+		return newUsageError("チャンネル名またはパターン（例: 'team-*'）を1つ以上指定してください")
+	}
+
+	patterns := make([]string, 0, len(positional))
+	for _, p := range positional {
+		patterns = append(patterns, trimChannelName(p))
+	}
+
+	a, err := newAnalysisApp(ctx, opts, stderr)
+	if err != nil {
+		return err
+	}
+	rep, err := channelsReport(ctx, a, patterns, opts)
+	if err != nil {
+		return err
+	}
+	return writeReport(ctx, a, opts, rep, stdout, stderr)
+}
+
+// channelsReport はパターンに一致する複数チャンネルをまとめて分析する
+func channelsReport(ctx context.Context, a *app, patterns []string, opts *analysisOptions) (*analysisReport, error) {
+	channels, err := service.SelectChannels(ctx, a.channelRepo, patterns)
+	if err != nil {
+		return nil, err
+	}
+	channels = service.ExcludeChannels(channels, opts.excludeChannels)
+	if len(channels) == 0 {
+		return nil, newUsageError("除外設定により分析対象のチャンネルがなくなりました")
+	}
+
+	result, err := a.analyzer.AnalyzeChannels(ctx, channels, opts.dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	title := fmt.Sprintf("%dチャンネルの分析結果", len(result.Channels))
+	return &analysisReport{
+		title: title,
+		json: func(w io.Writer, meta report.Metadata) error {
+			return report.WriteMultiChannelJSON(w, meta, result, opts.limits)
+		},
+		csv: func() []report.CSVSection {
+			return report.MultiChannelCSVSections(result, opts.limits)
+		},
+		markdown: func(w io.Writer, md report.MarkdownOptions) error {
+			return report.WriteMultiChannelMarkdown(w, title, opts.dateRange, result, opts.limits, md)
+		},
+		html: func(w io.Writer, meta report.Metadata, link report.MessageLinker) error {
+			return report.WriteMultiChannelHTML(w, title, meta, result, link)
+		},
+		template: func(meta report.Metadata) *report.TemplateData {
+			return report.NewMultiChannelTemplateData(meta, title, result, opts.limits)
+		},
+		charts: func() []*chart.Chart {
+			return chart.FromAnalysisResult(result.Merged, opts.dateRange, opts.limits)
+		},
+		dataset: func() *store.Dataset {
+			return multiChannelDataset(result)
+		},
+		blocks: func(link report.MessageLinker) []slack.Block {
+			return report.MultiChannelBlocks(title, opts.dateRange, result, opts.limits, link)
+		},
+		warnings: result.Merged.Warnings,
+	}, nil
+}
+
+// multiChannelDataset は複数チャンネルの分析に使用したチャンネルとメッセージを -output で書き出すデータにする
+func multiChannelDataset(result *service.MultiChannelResult) *store.Dataset {
+	data := &store.Dataset{Messages: result.Merged.Messages}
+	for _, ch := range result.Channels {
+		data.Channels = append(data.Channels, ch.Channel)
+	}
+	return data
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	slackinfra "github.com/Tattsum/slack-reaction/internal/infrastructure/slack"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/slack-go/slack"
+)
+
+// defaultTokenEnv はSlackトークンを設定するデフォルトの環境変数名
+const defaultTokenEnv = "SLACK_USER_TOKEN"
+
+// app はコマンドが使用するリポジトリとサービスをまとめたもの
+type app struct {
+	client      *slack.Client
+	channelRepo *slackinfra.ChannelRepository
+	messageRepo *slackinfra.MessageRepository
+	userRepo    *slackinfra.UserRepository
+	publisher   *slackinfra.Publisher
+	analyzer    *service.Analyzer
+}
+
+// newApp は環境変数のトークンからSlackクライアントを作成し、依存関係を組み立てる
+// tokenEnv が空の場合は SLACK_USER_TOKEN を使用する
+func newApp(tokenEnv string) (*app, error) {
+	if tokenEnv == "" {
+		tokenEnv = defaultTokenEnv
+	}
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return nil, fmt.Errorf("%w（環境変数 %s）", errNoToken, tokenEnv)
+	}
+
+	client := slack.New(token)
+	messageRepo := slackinfra.NewMessageRepository(client)
+	userRepo := slackinfra.NewUserRepository(client)
+	return &app{
+		client:      client,
+		channelRepo: slackinfra.NewChannelRepository(client),
+		messageRepo: messageRepo,
+		userRepo:    userRepo,
+		publisher:   slackinfra.NewPublisher(client),
+		analyzer:    service.NewAnalyzer(messageRepo, userRepo),
+	}, nil
+}
+
+// newFlagSet はサブコマンド用のFlagSetを作成する
+func newFlagSet(name, usage string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "使い方: slack-reaction %s %s\n\nオプション:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parseFlags はフラグと位置引数が混在した引数を解析し、位置引数を返す
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, &usageError{msg: err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// printWarnings は警告を表示し、警告がある場合はerrPartialを返す
+func printWarnings(stderr io.Writer, warnings []string) error {
+	if len(warnings) == 0 {
+		return nil
+	}
+	for _, warning := range warnings {
+		fmt.Fprintf(stderr, "警告: %s\n", warning)
+	}
+	return fmt.Errorf("%w（警告 %d件）", errPartial, len(warnings))
+}
+
+// trimChannelName はチャンネル名の先頭の # を取り除く
+func trimChannelName(name string) string {
+	return strings.TrimPrefix(name, "#")
+}
+
+// selectTarget はチャンネルとユーザーのどちらか一方を分析対象とするコマンドの対象を決定する
+// 引数で指定されていない場合はプロファイルのチャンネル（1つのみ）またはユーザーを使用する
+func selectTarget(positional []string, userName string, opts *analysisOptions) (channelName, user string, err error) {
+	if userName == "" && len(positional) == 0 {
+		if len(opts.channels) == 1 {
+			positional = opts.channels
+		} else if len(opts.channels) == 0 {
+			userName = opts.user
+		}
+	}
+	switch {
+	case userName != "" && len(positional) > 0:
+		return "", "", newUsageError("チャンネル名と -user は同時に指定できません")
+	case userName != "":
+		return "", userName, nil
+	case len(positional) != 1:
+		return "", "", newUsageError("チャンネル名を1つ、または -user でユーザー名を指定してください")
+	}
+	return trimChannelName(positional[0]), "", nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// runCompare は compare コマンドを実行する
+func runCompare(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("compare", "[オプション] <チャンネル名> | -user <ユーザー名>", stderr)
+	flags := addAnalysisFlags(fs)
+	var userName, baseStart, baseEnd string
+	fs.StringVar(&userName, "user", "", "チャンネルの代わりにユーザーを比較する")
+	fs.StringVar(&baseStart, "base-start", "", "比較元の開始日（YYYY-MM-DD形式、省略時は直前の同じ長さの期間）")
+	fs.StringVar(&baseEnd, "base-end", "", "比較元の終了日（YYYY-MM-DD形式、その日を含む）")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	opts, err := flags.resolve(report.DefaultCompareLimits)
+	if err != nil {
+		return err
+	}
+	if opts.post != "" {
+		return newUsageError("compare コマンドでは -post は使用できません")
+	}
+	channelName, userName, err := selectTarget(positional, userName, opts)
+	if err != nil {
+		return err
+	}
+
+	currentRange, baseRange, err := compareRanges(opts.dateRange, baseStart, baseEnd)
+	if err != nil {
+		return err
+	}
+
+	a, err := newAnalysisApp(ctx, opts, stderr)
+	if err != nil {
+		return err
+	}
+
+	if userName != "" {
+		base, err := a.analyzer.AnalyzeUser(ctx, userName, baseRange)
+		if err != nil {
+			return err
+		}
+		current, err := a.analyzer.AnalyzeUser(ctx, userName, currentRange)
+		if err != nil {
+			return err
+		}
+		cmp := service.CompareUserResults(base, current, baseRange, currentRange)
+		if err := report.WriteComparisonText(stdout, current.UserName, cmp, opts.limits); err != nil {
+			return err
+		}
+		return printWarnings(stderr, append(base.Warnings, current.Warnings...))
+	}
+
+	channel, err := a.channelRepo.FindByName(ctx, channelName)
+	if err != nil {
+		return err
+	}
+	base, err := a.analyzer.AnalyzeChannel(ctx, channel.ID, baseRange)
+	if err != nil {
+		return err
+	}
+	current, err := a.analyzer.AnalyzeChannel(ctx, channel.ID, currentRange)
+	if err != nil {
+		return err
+	}
+	cmp := service.CompareChannelResults(base, current, baseRange, currentRange)
+	if err := report.WriteComparisonText(stdout, "#"+channel.Name, cmp, opts.limits); err != nil {
+		return err
+	}
+	return printWarnings(stderr, append(base.Warnings, current.Warnings...))
+}
+
+// compareRanges は比較先と比較元の期間を決定する
+// 比較元が指定されていない場合は、比較先の直前の期間（暦月の場合は前月）を使用する
+func compareRanges(current *domain.DateRange, baseStart, baseEnd string) (*domain.DateRange, *domain.DateRange, error) {
+	if current.Start.IsZero() || current.End.IsZero() {
+		return nil, nil, newUsageError("比較先の期間を -start と -end、または -period で指定してください")
+	}
+	if baseStart == "" && baseEnd == "" {
+		base, err := current.Previous()
+		if err != nil {
+			return nil, nil, &usageError{msg: err.Error()}
+		}
+		return current, base, nil
+	}
+	if baseStart == "" || baseEnd == "" {
+		return nil, nil, newUsageError("-base-start と -base-end は両方指定してください")
+	}
+	base, err := domain.ParseDateRange(baseStart, baseEnd, time.Local)
+	if err != nil {
+		return nil, nil, &usageError{msg: err.Error()}
+	}
+	return current, base, nil
+}
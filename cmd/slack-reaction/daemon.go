@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/config"
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/schedule"
+)
+
+// defaultStateFile は daemon コマンドがジョブの実行記録を保存するデフォルトのファイル
+const defaultStateFile = ".slack-reaction-state.json"
+
+// runDaemon は daemon コマンドを実行する
+func runDaemon(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("daemon", "[オプション]", stderr)
+	var (
+		configPath string
+		statePath  string
+		jobNames   stringList
+	)
+	fs.StringVar(&configPath, "config", "", "設定ファイルのパス（省略時は ./"+config.FileName+" など）")
+	fs.StringVar(&statePath, "state", defaultStateFile, "ジョブの実行記録を保存するファイルのパス")
+	fs.Var(&jobNames, "job", "実行するジョブ名（カンマ区切り、省略時は全ジョブ）")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return newUsageError("daemon コマンドは位置引数を受け付けません: %v", positional)
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+	jobs, err := scheduledJobs(cfg, jobNames)
+	if err != nil {
+		return err
+	}
+	// トークンが設定されていない場合は、最初の実行予定を待たずにエラーにする
+	for _, job := range jobs {
+		if _, err := newApp(cfg.Profiles[cfg.Jobs[job.Name].Profile].TokenEnv); err != nil {
+			return fmt.Errorf("ジョブ '%s': %w", job.Name, err)
+		}
+	}
+
+	state, err := schedule.LoadState(statePath)
+	if err != nil {
+		return err
+	}
+	d := &daemon{cfg: cfg, log: stderr}
+	return schedule.New(jobs, state, d.runJob, stderr).Run(ctx)
+}
+
+// scheduledJobs は設定ファイルのジョブを名前順に返す（names が指定された場合はそのジョブのみ）
+// cron式・期間とプロファイルの出力形式は、最初の実行予定を待たずにここで検証する
+func scheduledJobs(cfg *config.Config, names []string) ([]schedule.Job, error) {
+	if len(cfg.Jobs) == 0 {
+		return nil, newUsageError("設定ファイルに jobs が定義されていません")
+	}
+	for _, name := range names {
+		if _, ok := cfg.Jobs[name]; !ok {
+			return nil, newUsageError("ジョブ '%s' が設定ファイルにありません", name)
+		}
+	}
+
+	var jobs []schedule.Job
+	for name, spec := range cfg.Jobs {
+		if len(names) > 0 && !slices.Contains(names, name) {
+			continue
+		}
+		cron, err := schedule.ParseCron(spec.Schedule)
+		if err != nil {
+			return nil, newUsageError("ジョブ '%s': %v", name, err)
+		}
+		if _, err := schedule.RollingRange(spec.Period, time.Now()); err != nil {
+			return nil, newUsageError("ジョブ '%s': %v", name, err)
+		}
+		// ジョブで実行できるコマンドはいずれも全ての出力形式に対応しているため、形式のみを検証する
+		if format := cfg.Profiles[spec.Profile].Format; format != "" && !report.IsSupportedFormat(format) {
+			return nil, newUsageError("ジョブ '%s': %s コマンドのプロファイルの format には %s のいずれかを指定してください", name, spec.Command, strings.Join(report.Formats, ", "))
+		}
+		jobs = append(jobs, schedule.Job{Name: name, Cron: cron, Period: spec.Period})
+	}
+	slices.SortFunc(jobs, func(a, b schedule.Job) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return jobs, nil
+}
+
+// daemon はスケジュールに従ってジョブを実行する
+type daemon struct {
+	cfg *config.Config
+	log io.Writer
+}
+
+// runJob はジョブの分析を実行し、設定された全ての出力先に書き出す
+// 一部の出力先への書き出しに失敗した場合も、残りの出力先には書き出す
+func (d *daemon) runJob(ctx context.Context, job schedule.Job, at time.Time, dateRange *domain.DateRange) error {
+	spec := d.cfg.Jobs[job.Name]
+	opts := profileOptions(d.cfg.Profiles[spec.Profile], jobLimits(spec.Command))
+	opts.command = spec.Command
+	opts.dateRange = dateRange
+
+	a, err := newAnalysisApp(ctx, opts, d.log)
+	if err != nil {
+		return err
+	}
+	a.analyzer.SetProgressOutput(io.Discard)
+	a.messageRepo.SetProgressOutput(io.Discard)
+
+	var rep *analysisReport
+	switch spec.Command {
+	case "channel":
+		rep, err = channelReport(ctx, a, trimChannelName(opts.channels[0]), opts)
+	case "channels":
+		patterns := make([]string, 0, len(opts.channels))
+		for _, p := range opts.channels {
+			patterns = append(patterns, trimChannelName(p))
+		}
+		rep, err = channelsReport(ctx, a, patterns, opts)
+	case "workspace":
+		rep, err = workspaceReport(ctx, a, opts, d.log)
+	case "user":
+		rep, err = userReport(ctx, a, opts.user, opts)
+	default:
+		err = fmt.Errorf("未対応のコマンドです: %s", spec.Command)
+	}
+	if err != nil {
+		return err
+	}
+
+	link, warning := messageLinker(ctx, a, opts)
+	if warning != "" {
+		rep.warnings = append(rep.warnings, warning)
+	}
+
+	var errs []error
+	for _, output := range spec.Outputs {
+		switch {
+		case output.Post != "":
+			postOpts := *opts
+			postOpts.post = trimChannelName(output.Post)
+			errs = append(errs, publish(ctx, a, &postOpts, rep.title, rep.blocks, nil, d.log))
+		case output.File != "":
+			errs = append(errs, writeReportFile(outputPath(output.File, at), rep, opts, link))
+		}
+	}
+	for _, warning := range rep.warnings {
+		fmt.Fprintf(d.log, "警告: ジョブ %s: %s\n", job.Name, warning)
+	}
+	return errors.Join(errs...)
+}
+
+// jobLimits はジョブのコマンドに対応するランキングのデフォルトの表示件数を返す
+func jobLimits(command string) report.Limits {
+	switch command {
+	case "workspace":
+		return report.DefaultWorkspaceLimits
+	case "user":
+		return report.DefaultUserLimits
+	default:
+		return report.DefaultChannelLimits
+	}
+}
+
+// outputPath は出力先のパスの {date} を実行予定日（YYYY-MM-DD）に置き換える
+func outputPath(path string, at time.Time) string {
+	return strings.ReplaceAll(path, "{date}", at.Format("2006-01-02"))
+}
+
+// writeReportFile は分析結果をプロファイルの出力形式でファイルに書き出す
+func writeReportFile(path string, rep *analysisReport, opts *analysisOptions, link report.MessageLinker) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("出力先のディレクトリ作成エラー: %w", err)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("出力ファイル作成エラー: %w", err)
+	}
+	if err := rep.render(file, opts, link); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
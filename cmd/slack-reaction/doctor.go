@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Tattsum/slack-reaction/internal/config"
+	"github.com/Tattsum/slack-reaction/internal/doctor"
+	slackinfra "github.com/Tattsum/slack-reaction/internal/infrastructure/slack"
+)
+
+// runDoctor は doctor コマンドを実行する
+func runDoctor(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("doctor", "[オプション] [チャンネル名またはパターン]...", stderr)
+	var (
+		configPath  string
+		profileName string
+		tokenEnv    string
+	)
+	fs.StringVar(&configPath, "config", "", "設定ファイルのパス（省略時は ./"+config.FileName+" など）")
+	fs.StringVar(&profileName, "profile", "", "診断するチャンネルとトークンを読み込むプロファイル名")
+	fs.StringVar(&tokenEnv, "token-env", "", "Slackトークンを読み込む環境変数名（省略時は "+defaultTokenEnv+"）")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	channels := positional
+	if profileName != "" {
+		cfg, err := loadConfig(configPath)
+		if err != nil {
+			return &usageError{msg: err.Error()}
+		}
+		profile, err := cfg.Profile(profileName)
+		if err != nil {
+			return &usageError{msg: err.Error()}
+		}
+		if len(channels) == 0 {
+			channels = profile.Channels
+		}
+		if tokenEnv == "" {
+			tokenEnv = profile.TokenEnv
+		}
+	}
+	if tokenEnv == "" {
+		tokenEnv = defaultTokenEnv
+	}
+	for i, name := range channels {
+		channels[i] = trimChannelName(name)
+	}
+
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		printDiagnosis(stdout, []doctor.Result{doctor.TokenMissing(tokenEnv)})
+		return fmt.Errorf("%w（環境変数 %s）", errNoToken, tokenEnv)
+	}
+
+	inspector := slackinfra.NewInspector(token)
+	d := doctor.New(inspector, slackinfra.NewChannelRepository(inspector.Client()))
+	results, err := d.Run(ctx, channels)
+	printDiagnosis(stdout, results)
+	if err != nil {
+		return err
+	}
+
+	problems := 0
+	for _, result := range results {
+		if result.Status == doctor.StatusError {
+			problems++
+		}
+	}
+	if problems > 0 {
+		return fmt.Errorf("診断で%d件の問題が見つかりました", problems)
+	}
+	return nil
+}
+
+// printDiagnosis は診断結果と対処方法を表示する
+func printDiagnosis(w io.Writer, results []doctor.Result) {
+	warnings, problems := 0, 0
+	for _, result := range results {
+		label := "[OK]  "
+		switch result.Status {
+		case doctor.StatusWarning:
+			label = "[警告]"
+			warnings++
+		case doctor.StatusError:
+			label = "[NG]  "
+			problems++
+		}
+		fmt.Fprintf(w, "%s %s: %s\n", label, result.Name, result.Message)
+		if result.Fix != "" {
+			fmt.Fprintf(w, "       対処: %s\n", result.Fix)
+		}
+	}
+	fmt.Fprintf(w, "\n問題: %d件 / 警告: %d件\n", problems, warnings)
+}
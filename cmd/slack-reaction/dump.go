@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// runDump は dump コマンドを実行する
+// 分析に使用するメッセージを集計せずに、1行に1つのJSON（NDJSON）として標準出力に出力する
+func runDump(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("dump", "[オプション] <チャンネル名またはパターン>... | -user <ユーザー名> | -workspace", stderr)
+	var (
+		userName        string
+		workspace       bool
+		start           string
+		end             string
+		period          string
+		threads         bool
+		tokenEnv        string
+		excludeChannels stringList
+	)
+	fs.StringVar(&userName, "user", "", "ユーザーのメッセージ（全チャンネル横断）を出力する")
+	fs.BoolVar(&workspace, "workspace", false, "参加している全チャンネルのメッセージを出力する")
+	fs.StringVar(&start, "start", "", "開始日（YYYY-MM-DD形式、省略可）")
+	fs.StringVar(&end, "end", "", "終了日（YYYY-MM-DD形式、省略可、その日を含む）")
+	fs.StringVar(&period, "period", "", "直近の期間（例: 7d, 2w, 1m）。-start/-end とは併用不可")
+	fs.BoolVar(&threads, "threads", true, "チャンネルのスレッドの返信も出力する（-threads=false で親メッセージのみ）")
+	fs.StringVar(&tokenEnv, "token-env", defaultTokenEnv, "Slackトークンを読み込む環境変数名")
+	fs.Var(&excludeChannels, "exclude-channels", "出力から除外するチャンネル（名前・ID・globパターン、カンマ区切り）")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+
+	targets := 0
+	for _, set := range []bool{len(positional) > 0, userName != "", workspace} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return newUsageError("チャンネル名またはパターン、-user、-workspace のいずれか1つを指定してください")
+	}
+	if userName != "" && len(excludeChannels) > 0 {
+		return newUsageError("-exclude-channels は -user と併せて指定できません")
+	}
+
+	var dateRange *domain.DateRange
+	switch {
+	case period != "" && (start != "" || end != ""):
+		return newUsageError("-period と -start/-end は同時に指定できません")
+	case period != "":
+		dateRange, err = domain.ParseRelativeDateRange(period, time.Now())
+	case start != "" || end != "":
+		dateRange, err = domain.ParseDateRange(start, end, time.Local)
+	}
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+
+	a, err := newApp(tokenEnv)
+	if err != nil {
+		return err
+	}
+	// 標準出力はNDJSONのみにするため、進捗は標準エラー出力に表示する
+	a.messageRepo.SetProgressOutput(stderr)
+	dumper := service.NewDumper(a.messageRepo)
+	dumper.SetProgressOutput(stderr)
+	dumper.SetThreads(threads)
+
+	w := report.NewMessageWriter(stdout)
+	var result *service.DumpResult
+	if userName != "" {
+		user, err := a.userRepo.FindByName(ctx, userName)
+		if err != nil {
+			return err
+		}
+		result, err = dumper.DumpUser(ctx, user.ID, dateRange, w.Write)
+		if err != nil {
+			return err
+		}
+	} else {
+		channels, err := selectChannels(ctx, a, positional, excludeChannels, stderr)
+		if err != nil {
+			return err
+		}
+		result, err = dumper.DumpChannels(ctx, channels, dateRange, w.Write)
+		if err != nil {
+			return err
+		}
+	}
+	return printWarnings(stderr, result.Warnings)
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/slack-go/slack"
+)
+
+// runEmoji は emoji コマンドを実行する
+func runEmoji(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("emoji", "[オプション] <スタンプ名> [チャンネル名またはパターン]...", stderr)
+	flags := addAnalysisFlags(fs)
+	flags.allowExcludeChannels()
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	opts, err := flags.resolve(report.DefaultEmojiLimits)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 || domain.NormalizeEmojiName(positional[0]) == "" {
+		return newUsageError("スタンプ名（例: :tada:）を指定してください")
+	}
+	emoji := positional[0]
+
+	// チャンネルの指定がない場合は、参加している全チャンネルを対象にする
+	patterns := positional[1:]
+	if len(patterns) == 0 {
+		patterns = opts.channels
+	}
+	for i, p := range patterns {
+		patterns[i] = trimChannelName(p)
+	}
+
+	a, err := newAnalysisApp(ctx, opts, stderr)
+	if err != nil {
+		return err
+	}
+	rep, err := emojiReport(ctx, a, emoji, patterns, opts, stderr)
+	if err != nil {
+		return err
+	}
+	return writeReport(ctx, a, opts, rep, stdout, stderr)
+}
+
+// emojiReport はパターンに一致するチャンネル（指定がない場合は参加している全チャンネル）で1つのスタンプの使われ方を分析する
+// 全チャンネルを対象にした場合、参加していないため対象外としたチャンネル数は stderr に表示する
+func emojiReport(ctx context.Context, a *app, emoji string, patterns []string, opts *analysisOptions, stderr io.Writer) (*analysisReport, error) {
+	var channels []*domain.Channel
+	var err error
+	if len(patterns) > 0 {
+		channels, err = service.SelectChannels(ctx, a.channelRepo, patterns)
+	} else {
+		var skipped int
+		channels, skipped, err = service.WorkspaceChannels(ctx, a.channelRepo)
+		if skipped > 0 {
+			fmt.Fprintf(stderr, "参加していない%dチャンネルは対象外です\n", skipped)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	channels = service.ExcludeChannels(channels, opts.excludeChannels)
+	if len(channels) == 0 {
+		return nil, newUsageError("除外設定により分析対象のチャンネルがなくなりました")
+	}
+
+	result, err := a.analyzer.AnalyzeEmoji(ctx, channels, emoji, opts.dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	title := fmt.Sprintf("スタンプ :%s: の分析結果", result.Emoji)
+	return &analysisReport{
+		title: title,
+		write: func(w io.Writer, text report.TextOptions) error {
+			return report.WriteEmojiText(w, opts.dateRange, result, opts.limits, text)
+		},
+		blocks: func(link report.MessageLinker) []slack.Block {
+			return report.EmojiBlocks(title, opts.dateRange, result, opts.limits, link)
+		},
+		warnings: result.Warnings,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// runEstimate は estimate コマンドを実行する
+func runEstimate(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("estimate", "[オプション] <チャンネル名またはパターン>... | -user <ユーザー名> | -workspace", stderr)
+	flags := addAnalysisFlags(fs)
+	flags.allowExcludeChannels()
+	var (
+		userName  string
+		workspace bool
+		samples   int
+	)
+	fs.StringVar(&userName, "user", "", "ユーザー分析の見積もりを作成する")
+	fs.BoolVar(&workspace, "workspace", false, "ワークスペース分析の見積もりを作成する")
+	fs.IntVar(&samples, "samples", service.DefaultSampleChannels, "履歴をサンプリングするチャンネル数の上限")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	opts, err := flags.resolve(report.DefaultChannelLimits)
+	if err != nil {
+		return err
+	}
+	if opts.post != "" {
+		return newUsageError("estimate コマンドでは -post は使用できません")
+	}
+	if samples < 1 {
+		return newUsageError("-samples には1以上の値を指定してください")
+	}
+	if len(positional) == 0 && userName == "" && !workspace {
+		positional = opts.channels
+		if len(positional) == 0 {
+			userName = opts.user
+		}
+	}
+	targets := 0
+	for _, set := range []bool{len(positional) > 0, userName != "", workspace} {
+		if set {
+			targets++
+		}
+	}
+	if targets != 1 {
+		return newUsageError("チャンネル名またはパターン、-user、-workspace のいずれか1つを指定してください")
+	}
+
+	a, err := newAnalysisApp(ctx, opts, stderr)
+	if err != nil {
+		return err
+	}
+	estimator := service.NewEstimator(a.messageRepo, samples)
+	fmt.Fprintf(stderr, "チャンネルの履歴をサンプリングしています...\n")
+
+	var (
+		title    string
+		estimate *service.Estimate
+	)
+	switch {
+	case userName != "":
+		user, err := a.userRepo.FindByName(ctx, userName)
+		if err != nil {
+			return err
+		}
+		channels, err := a.channelRepo.FindAll(ctx)
+		if err != nil {
+			return err
+		}
+		title = user.GetDisplayName()
+		estimate, err = estimator.EstimateUser(ctx, user.ID, channels, opts.dateRange)
+		if err != nil {
+			return err
+		}
+	case workspace:
+		channels, skipped, err := service.WorkspaceChannels(ctx, a.channelRepo)
+		if err != nil {
+			return err
+		}
+		if skipped > 0 {
+			fmt.Fprintf(stderr, "参加していない%dチャンネルは対象外です\n", skipped)
+		}
+		channels = service.ExcludeChannels(channels, opts.excludeChannels)
+		title = "ワークスペース全体"
+		estimate, err = estimator.EstimateChannels(ctx, channels, opts.dateRange)
+		if err != nil {
+			return err
+		}
+	default:
+		patterns := make([]string, 0, len(positional))
+		for _, p := range positional {
+			patterns = append(patterns, trimChannelName(p))
+		}
+		channels, err := service.SelectChannels(ctx, a.channelRepo, patterns)
+		if err != nil {
+			return err
+		}
+		channels = service.ExcludeChannels(channels, opts.excludeChannels)
+		title = fmt.Sprintf("%dチャンネル", len(channels))
+		if len(channels) == 1 {
+			title = "#" + channels[0].Name
+		}
+		estimate, err = estimator.EstimateChannels(ctx, channels, opts.dateRange)
+		if err != nil {
+			return err
+		}
+	}
+
+	return report.WriteEstimateText(stdout, title, opts.dateRange, estimate)
+}
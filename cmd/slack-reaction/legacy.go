@@ -0,0 +1,43 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/Tattsum/slack-reaction/internal/report"
+)
+
+// runLegacy はサブコマンドを使わない旧形式（-channel / -user）の呼び出しを実行する
+func runLegacy(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("", "-channel <チャンネル名> | -user <ユーザー名> [オプション]", stderr)
+	channelName := fs.String("channel", "", "分析対象のチャンネル名（channel コマンドと同じ）")
+	userName := fs.String("user", "", "分析対象のユーザー名（user コマンドと同じ）")
+	flags := addAnalysisFlags(fs)
+	flags.allowFormats(report.FormatJSON, report.FormatCSV, report.FormatMarkdown, report.FormatHTML)
+	flags.allowTemplate()
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return newUsageError("不明な引数です: %v", positional)
+	}
+	if (*channelName == "") == (*userName == "") {
+		return newUsageError("-channel と -user のいずれか一方を指定してください")
+	}
+
+	if *channelName != "" {
+		opts, err := flags.resolve(report.DefaultChannelLimits)
+		if err != nil {
+			return err
+		}
+		opts.command = "channel"
+		return analyzeChannel(ctx, trimChannelName(*channelName), opts, stdout, stderr)
+	}
+	opts, err := flags.resolve(report.DefaultUserLimits)
+	if err != nil {
+		return err
+	}
+	opts.command = "user"
+	return analyzeUser(ctx, *userName, opts, stdout, stderr)
+}
@@ -0,0 +1,154 @@
+// slack-reaction はSlackチャンネルやユーザーのメッセージとリアクションを分析するCLIツール
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	slackinfra "github.com/Tattsum/slack-reaction/internal/infrastructure/slack"
+)
+
+// 終了コード
+const (
+	exitOK      = 0 // 正常終了
+	exitError   = 1 // その他のエラー
+	exitUsage   = 2 // 引数・入力値の誤り
+	exitAuth    = 3 // トークン未設定・認証エラー
+	exitPartial = 4 // 一部のデータを取得できなかった（結果は出力済み）
+)
+
+// errPartial は結果を出力したが一部のデータを取得できなかったことを表す
+var errPartial = errors.New("一部のデータを取得できませんでした")
+
+// errNoToken はトークンが設定されていないことを表す
+var errNoToken = errors.New("Slackトークンが設定されていません")
+
+// usageError は引数・入力値の誤りを表す
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+// newUsageError は書式指定でusageErrorを作成する
+func newUsageError(format string, args ...any) error {
+	return &usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// command はサブコマンドの定義
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string, stdout, stderr io.Writer) error
+}
+
+// commands はサブコマンドの一覧
+var commands = []command{
+	{name: "channel", summary: "チャンネルのメッセージとリアクションを分析する", run: runChannel},
+	{name: "channels", summary: "複数チャンネル（名前またはglobパターン）をまとめて分析する", run: runChannels},
+	{name: "workspace", summary: "参加している全チャンネルを横断したワークスペース全体のランキングを表示する", run: runWorkspace},
+	{name: "user", summary: "ユーザーのメッセージを全チャンネル横断で分析する", run: runUser},
+	{name: "emoji", summary: "1つのスタンプの推移と、よく使われるチャンネル・投稿者・メッセージを分析する", run: runEmoji},
+	{name: "thread", summary: "メッセージのパーマリンクからスレッドの参加者・スタンプ・返信の推移を分析する", run: runThread},
+	{name: "search", summary: "Slackの検索クエリに一致するメッセージとリアクションを分析する", run: runSearch},
+	{name: "compare", summary: "チャンネルまたはユーザーの2つの期間の分析結果を比較する", run: runCompare},
+	{name: "dump", summary: "分析に使用するメッセージをリアクション・スレッドの情報付きでNDJSON形式で出力する", run: runDump},
+	{name: "estimate", summary: "分析に必要なAPI呼び出し回数と所要時間を実行前に見積もる", run: runEstimate},
+	{name: "serve", summary: "分析機能をJSON形式のREST APIとして提供するHTTPサーバーを起動する", run: runServe},
+	{name: "metrics", summary: "チャンネルの活動をPrometheus/OpenMetrics形式のメトリクスとして公開する", run: runMetrics},
+	{name: "daemon", summary: "設定ファイルのジョブをcron式のスケジュールに従って定期的に実行する", run: runDaemon},
+	{name: "doctor", summary: "トークン・スコープ・チャンネルへの参加状況を診断し、問題の対処方法を表示する", run: runDoctor},
+	{name: "tui", summary: "分析結果を対話的に閲覧する全画面のターミナルUIを起動する", run: runTUI},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	code := run(ctx, os.Args[1:], os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run はサブコマンドを実行し、終了コードを返す
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	name, rest := args[0], args[1:]
+	switch {
+	case name == "help" || name == "-h" || name == "-help" || name == "--help":
+		printUsage(stdout)
+		return exitOK
+	case strings.HasPrefix(name, "-"):
+		// 旧形式（-channel / -user）の呼び出し
+		return finish(stderr, runLegacy(ctx, args, stdout, stderr))
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return finish(stderr, cmd.run(ctx, rest, stdout, stderr))
+		}
+	}
+
+	fmt.Fprintf(stderr, "不明なコマンドです: %s\n\n", name)
+	printUsage(stderr)
+	return exitUsage
+}
+
+// finish はエラーを表示し、対応する終了コードを返す
+func finish(stderr io.Writer, err error) int {
+	code := exitCode(err)
+	switch code {
+	case exitOK:
+	case exitPartial:
+		fmt.Fprintf(stderr, "警告: %v\n", err)
+	case exitAuth:
+		fmt.Fprintf(stderr, "認証エラー: %v\n", err)
+		fmt.Fprintf(stderr, "トークンとスコープを確認してください（slack-reaction doctor で診断できます。docs/TROUBLESHOOTING.md を参照）\n")
+	default:
+		fmt.Fprintf(stderr, "エラー: %v\n", err)
+	}
+	return code
+}
+
+// exitCode はエラーの種類に応じた終了コードを返す
+func exitCode(err error) int {
+	var usageErr *usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.Is(err, errNoToken), slackinfra.IsAuthError(err):
+		return exitAuth
+	case errors.Is(err, errPartial):
+		return exitPartial
+	default:
+		return exitError
+	}
+}
+
+// printUsage はコマンド全体の使い方を表示する
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "使い方: slack-reaction <コマンド> [オプション]\n\n")
+	fmt.Fprintf(w, "コマンド:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\n各コマンドのオプションは `slack-reaction <コマンド> -h` で確認できます\n")
+	fmt.Fprintf(w, "\n終了コード:\n")
+	fmt.Fprintf(w, "  %d  正常終了\n", exitOK)
+	fmt.Fprintf(w, "  %d  エラー\n", exitError)
+	fmt.Fprintf(w, "  %d  引数・入力値の誤り\n", exitUsage)
+	fmt.Fprintf(w, "  %d  トークン未設定・認証エラー\n", exitAuth)
+	fmt.Fprintf(w, "  %d  一部のデータを取得できなかった（結果は出力済み）\n", exitPartial)
+}
@@ -0,0 +1,373 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"reflect"
+	"testing"
+)
+
+func TestRun_ExitCode(t *testing.T) {
+	t.Setenv(defaultTokenEnv, "")
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected int
+	}{
+		{
+			name:     "引数なし",
+			args:     nil,
+			expected: exitUsage,
+		},
+		{
+			name:     "ヘルプ",
+			args:     []string{"help"},
+			expected: exitOK,
+		},
+		{
+			name:     "不明なコマンド",
+			args:     []string{"unknown"},
+			expected: exitUsage,
+		},
+		{
+			name:     "チャンネル名なし",
+			args:     []string{"channel"},
+			expected: exitUsage,
+		},
+		{
+			name:     "日付形式が不正",
+			args:     []string{"channel", "general", "-start", "2023/01/01"},
+			expected: exitUsage,
+		},
+		{
+			name:     "開始日が終了日より後",
+			args:     []string{"user", "taro", "-start", "2023-02-01", "-end", "2023-01-01"},
+			expected: exitUsage,
+		},
+		{
+			name:     "旧形式で-channelと-userを併用",
+			args:     []string{"-channel", "general", "-user", "taro"},
+			expected: exitUsage,
+		},
+		{
+			name:     "-periodと-startを併用",
+			args:     []string{"channel", "general", "-period", "7d", "-start", "2023-01-01"},
+			expected: exitUsage,
+		},
+		{
+			name:     "未対応の出力形式",
+			args:     []string{"channel", "general", "-format", "xml"},
+			expected: exitUsage,
+		},
+		{
+			name:     "JSON形式に対応していないコマンド",
+			args:     []string{"emoji", "tada", "-format", "json"},
+			expected: exitUsage,
+		},
+		{
+			name:     "-exclude-channelsに対応していないコマンド",
+			args:     []string{"channel", "general", "-exclude-channels", "random"},
+			expected: exitUsage,
+		},
+		{
+			name:     "CSV形式以外で-bom",
+			args:     []string{"channel", "general", "-bom"},
+			expected: exitUsage,
+		},
+		{
+			name:     "Markdown形式以外で-mermaid",
+			args:     []string{"user", "taro", "-format", "json", "-mermaid"},
+			expected: exitUsage,
+		},
+		{
+			name:     "テンプレートに対応していないコマンド",
+			args:     []string{"emoji", "tada", "-template", "testdata/report.tmpl"},
+			expected: exitUsage,
+		},
+		{
+			name:     "テキスト形式以外で-template",
+			args:     []string{"channel", "general", "-format", "json", "-template", "testdata/report.tmpl"},
+			expected: exitUsage,
+		},
+		{
+			name:     "存在しないテンプレート",
+			args:     []string{"channel", "general", "-template", "testdata/missing.tmpl"},
+			expected: exitUsage,
+		},
+		{
+			name:     "構文が誤っているテンプレート",
+			args:     []string{"channel", "general", "-template", "testdata/invalid.tmpl"},
+			expected: exitUsage,
+		},
+		{
+			name:     "不正な-chart",
+			args:     []string{"channel", "general", "-chart", "sometimes"},
+			expected: exitUsage,
+		},
+		{
+			name:     "テキスト形式以外で-chart",
+			args:     []string{"channel", "general", "-format", "json", "-chart", "always"},
+			expected: exitUsage,
+		},
+		{
+			name:     "-imagesに対応していないコマンド",
+			args:     []string{"emoji", "tada", "-images", "out"},
+			expected: exitUsage,
+		},
+		{
+			name:     "-imagesなしで-image-format",
+			args:     []string{"channel", "general", "-image-format", "svg"},
+			expected: exitUsage,
+		},
+		{
+			name:     "未対応の画像の形式",
+			args:     []string{"channel", "general", "-images", "out", "-image-format", "gif"},
+			expected: exitUsage,
+		},
+		{
+			name:     "存在しないフォント",
+			args:     []string{"search", "release", "-images", "out", "-image-font", "testdata/missing.ttf"},
+			expected: exitUsage,
+		},
+		{
+			name:     "-outputに対応していないコマンド",
+			args:     []string{"emoji", "tada", "-output", "sqlite:analysis.db"},
+			expected: exitUsage,
+		},
+		{
+			name:     "未対応の-outputの書き出し先",
+			args:     []string{"channel", "general", "-output", "analysis.db"},
+			expected: exitUsage,
+		},
+		{
+			name:     "-outputにファイル名なし",
+			args:     []string{"user", "taro", "-output", "sqlite:"},
+			expected: exitUsage,
+		},
+		{
+			name:     "存在しない設定ファイル",
+			args:     []string{"channel", "general", "-config", "testdata/missing.yaml", "-profile", "weekly"},
+			expected: exitUsage,
+		},
+		{
+			name:     "比較で期間の指定なし",
+			args:     []string{"compare", "general"},
+			expected: exitUsage,
+		},
+		{
+			name:     "比較で比較元の終了日なし",
+			args:     []string{"compare", "general", "-period", "1m", "-base-start", "2023-01-01"},
+			expected: exitUsage,
+		},
+		{
+			name:     "比較でチャンネルと-userを併用",
+			args:     []string{"compare", "general", "-user", "taro", "-period", "1m"},
+			expected: exitUsage,
+		},
+		{
+			name:     "-postなしで-dry-run",
+			args:     []string{"channel", "general", "-dry-run"},
+			expected: exitUsage,
+		},
+		{
+			name:     "tuiで-post",
+			args:     []string{"tui", "general", "-post", "reports"},
+			expected: exitUsage,
+		},
+		{
+			name:     "見積もりの対象なし",
+			args:     []string{"estimate"},
+			expected: exitUsage,
+		},
+		{
+			name:     "見積もりで-userと-workspaceを併用",
+			args:     []string{"estimate", "-user", "taro", "-workspace"},
+			expected: exitUsage,
+		},
+		{
+			name:     "スタンプ名なし",
+			args:     []string{"emoji", "-period", "7d"},
+			expected: exitUsage,
+		},
+		{
+			name:     "スタンプ名がコロンのみ",
+			args:     []string{"emoji", "::"},
+			expected: exitUsage,
+		},
+		{
+			name:     "パーマリンクなし",
+			args:     []string{"thread"},
+			expected: exitUsage,
+		},
+		{
+			name:     "パーマリンクの形式が不正",
+			args:     []string{"thread", "https://example.slack.com/archives/C0123"},
+			expected: exitUsage,
+		},
+		{
+			name:     "検索クエリなし",
+			args:     []string{"search", "-period", "7d"},
+			expected: exitUsage,
+		},
+		{
+			name:     "serveに位置引数",
+			args:     []string{"serve", "general"},
+			expected: exitUsage,
+		},
+		{
+			name:     "metricsで出力先なし",
+			args:     []string{"metrics", "general"},
+			expected: exitUsage,
+		},
+		{
+			name:     "metricsで短すぎる更新間隔",
+			args:     []string{"metrics", "-listen", "localhost:0", "-interval", "10s"},
+			expected: exitUsage,
+		},
+		{
+			name:     "metricsで不正な期間",
+			args:     []string{"metrics", "-textfile", "out.prom", "-period", "7x"},
+			expected: exitUsage,
+		},
+		{
+			name:     "dumpで対象なし",
+			args:     []string{"dump"},
+			expected: exitUsage,
+		},
+		{
+			name:     "dumpでチャンネルと-userを同時指定",
+			args:     []string{"dump", "general", "-user", "taro"},
+			expected: exitUsage,
+		},
+		{
+			name:     "dumpで-periodと-startを同時指定",
+			args:     []string{"dump", "general", "-period", "7d", "-start", "2024-01-01"},
+			expected: exitUsage,
+		},
+		{
+			name:     "dumpで-userと-exclude-channelsを同時指定",
+			args:     []string{"dump", "-user", "taro", "-exclude-channels", "random"},
+			expected: exitUsage,
+		},
+		{
+			name:     "daemonで設定ファイルがない",
+			args:     []string{"daemon", "-config", "testdata/missing.yaml"},
+			expected: exitUsage,
+		},
+		{
+			name:     "daemonで存在しないジョブ",
+			args:     []string{"daemon", "-config", "testdata/jobs.yaml", "-job", "unknown"},
+			expected: exitUsage,
+		},
+		{
+			name:     "daemonでジョブのcron式が不正",
+			args:     []string{"daemon", "-config", "testdata/jobs-invalid.yaml", "-job", "invalid-schedule"},
+			expected: exitUsage,
+		},
+		{
+			name:     "daemonでジョブのプロファイルのformatが未対応",
+			args:     []string{"daemon", "-config", "testdata/jobs-invalid.yaml", "-job", "invalid-format"},
+			expected: exitUsage,
+		},
+		{
+			name:     "daemonでトークン未設定",
+			args:     []string{"daemon", "-config", "testdata/jobs.yaml", "-state", "testdata/missing-state.json"},
+			expected: exitAuth,
+		},
+		{
+			name:     "doctorでトークン未設定",
+			args:     []string{"doctor", "general"},
+			expected: exitAuth,
+		},
+		{
+			name:     "トークン未設定",
+			args:     []string{"channel", "general"},
+			expected: exitAuth,
+		},
+		{
+			name:     "-outputでトークン未設定",
+			args:     []string{"workspace", "--output", "sqlite:analysis.db"},
+			expected: exitAuth,
+		},
+		{
+			name:     "userで-exclude-channelsとトークン未設定",
+			args:     []string{"user", "taro", "-exclude-channels", "random"},
+			expected: exitAuth,
+		},
+		{
+			name:     "serveでトークン未設定",
+			args:     []string{"serve", "-addr", "localhost:0"},
+			expected: exitAuth,
+		},
+		{
+			name:     "metricsでトークン未設定",
+			args:     []string{"metrics", "-listen", "localhost:0"},
+			expected: exitAuth,
+		},
+		{
+			name:     "dumpでトークン未設定",
+			args:     []string{"dump", "-workspace", "-period", "7d"},
+			expected: exitAuth,
+		},
+		{
+			name:     "比較でトークン未設定",
+			args:     []string{"compare", "general", "-start", "2023-02-01", "-end", "2023-02-28"},
+			expected: exitAuth,
+		},
+		{
+			name:     "旧形式でトークン未設定",
+			args:     []string{"-user", "taro"},
+			expected: exitAuth,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(context.Background(), tt.args, &stdout, &stderr); got != tt.expected {
+				t.Errorf("run(%v) = %d, want %d (stderr: %s)", tt.args, got, tt.expected, stderr.String())
+			}
+		})
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	var stderr bytes.Buffer
+	fs := newFlagSet("test", "", &stderr)
+	flags := addAnalysisFlags(fs)
+
+	positional, err := parseFlags(fs, []string{"general", "-start", "2023-01-01", "random", "-end", "2023-12-31"})
+	if err != nil {
+		t.Fatalf("parseFlags() error = %v", err)
+	}
+	if want := []string{"general", "random"}; !reflect.DeepEqual(positional, want) {
+		t.Errorf("positional = %v, want %v", positional, want)
+	}
+	if flags.start != "2023-01-01" || flags.end != "2023-12-31" {
+		t.Errorf("start = %q, end = %q, want 2023-01-01 and 2023-12-31", flags.start, flags.end)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected int
+	}{
+		{name: "nil", err: nil, expected: exitOK},
+		{name: "ヘルプ", err: flag.ErrHelp, expected: exitOK},
+		{name: "引数エラー", err: newUsageError("bad"), expected: exitUsage},
+		{name: "トークン未設定", err: errNoToken, expected: exitAuth},
+		{name: "部分的な結果", err: printWarnings(&bytes.Buffer{}, []string{"w"}), expected: exitPartial},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.expected {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
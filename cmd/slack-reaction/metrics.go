@@ -0,0 +1,213 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/metrics"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// metricsPath はメトリクスを公開するパス
+const metricsPath = "/metrics"
+
+// runMetrics は metrics コマンドを実行する
+// -listen を指定した場合は一定間隔で分析して /metrics で公開し、-textfile だけを指定した場合は1回分析してファイルに書き込む
+func runMetrics(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("metrics", "[オプション] [チャンネル名またはパターン]...", stderr)
+	var (
+		listen          string
+		textfile        string
+		interval        time.Duration
+		period          string
+		maxEmoji        int
+		maxChannels     int
+		tokenEnv        string
+		excludeUsers    stringList
+		excludeChannels stringList
+	)
+	fs.StringVar(&listen, "listen", "", "メトリクスを公開するアドレス（例: :9464）")
+	fs.StringVar(&textfile, "textfile", "", "node_exporterのtextfile collector用に書き込むファイル（例: /var/lib/node_exporter/slack_reaction.prom）")
+	fs.DurationVar(&interval, "interval", 15*time.Minute, "-listen 指定時に分析を再実行する間隔")
+	fs.StringVar(&period, "period", "7d", "分析する直近の期間（例: 7d, 2w, 1m）。分析のたびに現在時刻から計算する")
+	fs.IntVar(&maxEmoji, "max-emoji", 10, "チャンネルごとにラベルとして出力するスタンプの種類の上限（残りは "+metrics.OtherEmoji+" にまとめる）")
+	fs.IntVar(&maxChannels, "max-channels", 50, "分析対象とするチャンネル数の上限")
+	fs.StringVar(&tokenEnv, "token-env", defaultTokenEnv, "Slackトークンを読み込む環境変数名")
+	fs.Var(&excludeUsers, "exclude-users", "集計から除外するユーザー（名前またはID、カンマ区切り）")
+	fs.Var(&excludeChannels, "exclude-channels", "分析から除外するチャンネル（名前・ID・globパターン、カンマ区切り）")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if listen == "" && textfile == "" {
+		return newUsageError("-listen または -textfile を指定してください")
+	}
+	if interval < time.Minute {
+		return newUsageError("-interval には1分以上の値を指定してください")
+	}
+	if _, err := domain.ParseRelativeDateRange(period, time.Now()); err != nil {
+		return newUsageError("%v", err)
+	}
+	if maxEmoji < 0 {
+		return newUsageError("-max-emoji には0以上の値を指定してください")
+	}
+	if maxChannels < 1 {
+		return newUsageError("-max-channels には1以上の値を指定してください")
+	}
+
+	a, err := newApp(tokenEnv)
+	if err != nil {
+		return err
+	}
+	if len(excludeUsers) > 0 {
+		userIDs, err := service.ResolveUserIDs(ctx, a.userRepo, excludeUsers)
+		if err != nil {
+			return err
+		}
+		a.analyzer.ExcludeUsers(userIDs)
+	}
+	a.analyzer.SetProgressOutput(io.Discard)
+	a.messageRepo.SetProgressOutput(io.Discard)
+
+	channels, err := selectChannels(ctx, a, positional, excludeChannels, stderr)
+	if err != nil {
+		return err
+	}
+	if len(channels) > maxChannels {
+		return newUsageError("分析対象のチャンネルが%d件あり、-max-channels（%d）を超えています。パターンを絞り込むか -max-channels を指定してください", len(channels), maxChannels)
+	}
+
+	exporter := metrics.NewExporter(func(ctx context.Context) (*metrics.Snapshot, error) {
+		dateRange, err := domain.ParseRelativeDateRange(period, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		result, err := a.analyzer.AnalyzeChannels(ctx, channels, dateRange)
+		if err != nil {
+			return nil, err
+		}
+		return metrics.NewSnapshot(result, dateRange, maxEmoji), nil
+	})
+
+	if listen == "" {
+		if err := exporter.Refresh(ctx); err != nil {
+			return err
+		}
+		if err := exporter.WriteTextfile(textfile); err != nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "メトリクスを書き込みました: %s\n", textfile)
+		return nil
+	}
+	return serveMetrics(ctx, exporter, listen, textfile, interval, stderr)
+}
+
+// selectChannels はパターンに一致するチャンネルから除外するチャンネルを除いて返す
+// パターンを指定しない場合は参加している全チャンネルを対象にする
+func selectChannels(ctx context.Context, a *app, patterns, excludeChannels []string, stderr io.Writer) ([]*domain.Channel, error) {
+	var channels []*domain.Channel
+	if len(patterns) > 0 {
+		trimmed := make([]string, 0, len(patterns))
+		for _, p := range patterns {
+			trimmed = append(trimmed, trimChannelName(p))
+		}
+		var err error
+		if channels, err = service.SelectChannels(ctx, a.channelRepo, trimmed); err != nil {
+			return nil, err
+		}
+	} else {
+		all, skipped, err := service.WorkspaceChannels(ctx, a.channelRepo)
+		if err != nil {
+			return nil, err
+		}
+		if skipped > 0 {
+			fmt.Fprintf(stderr, "参加していない%dチャンネルは対象外です\n", skipped)
+		}
+		channels = all
+	}
+	channels = service.ExcludeChannels(channels, excludeChannels)
+	if len(channels) == 0 {
+		return nil, newUsageError("除外設定により分析対象のチャンネルがなくなりました")
+	}
+	return channels, nil
+}
+
+// serveMetrics は interval ごとに分析を実行し、/metrics でメトリクスを公開する
+// textfile を指定した場合は、分析のたびにファイルにも書き込む
+func serveMetrics(ctx context.Context, exporter *metrics.Exporter, addr, textfile string, interval time.Duration, stderr io.Writer) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("待ち受けエラー: %w", err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET "+metricsPath, exporter)
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+	fmt.Fprintf(stderr, "メトリクスを公開しました: http://%s%s（%v ごとに更新）\n", listener.Addr(), metricsPath, interval)
+
+	// 分析はサーバーの停止時にキャンセルする
+	refreshCtx, cancelRefresh := context.WithCancel(context.Background())
+	refreshDone := make(chan struct{})
+	go func() {
+		defer close(refreshDone)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			refreshMetrics(refreshCtx, exporter, textfile, stderr)
+			select {
+			case <-refreshCtx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var serveErr error
+	select {
+	case err := <-errCh:
+		serveErr = fmt.Errorf("サーバーエラー: %w", err)
+	case <-ctx.Done():
+	}
+
+	fmt.Fprintf(stderr, "メトリクスの公開を停止しています...\n")
+	cancelRefresh()
+	<-refreshDone
+	if serveErr != nil {
+		return serveErr
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("サーバー停止エラー: %w", err)
+	}
+	return nil
+}
+
+// refreshMetrics は分析を1回実行してメトリクスを更新する
+// 失敗した場合は直前の値を公開し続け、エラーを stderr に表示する
+func refreshMetrics(ctx context.Context, exporter *metrics.Exporter, textfile string, stderr io.Writer) {
+	if err := exporter.Refresh(ctx); err != nil {
+		if ctx.Err() == nil {
+			fmt.Fprintf(stderr, "メトリクスの更新エラー: %v\n", err)
+		}
+	}
+	if textfile == "" || ctx.Err() != nil {
+		return
+	}
+	if err := exporter.WriteTextfile(textfile); err != nil {
+		fmt.Fprintf(stderr, "%v\n", err)
+	}
+}
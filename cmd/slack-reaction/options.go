@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/chart"
+	"github.com/Tattsum/slack-reaction/internal/config"
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// stringList はカンマ区切り、または複数回の指定で値を受け取るフラグ
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+// analysisFlags は分析コマンド共通のフラグ
+// 明示的に指定されたフラグはプロファイルの値を上書きする
+type analysisFlags struct {
+	fs              *flag.FlagSet
+	configPath      string
+	profileName     string
+	start           string
+	end             string
+	period          string
+	excludeUsers    stringList
+	excludeChannels stringList
+	channelExcludes bool // コマンドが -exclude-channels に対応している
+	format          string
+	formats         []string // コマンドが対応している出力形式
+	csvDir          string
+	bom             bool
+	mermaid         bool
+	template        string
+	templates       bool // コマンドが -template に対応している
+	chart           string
+	images          string
+	imageFormat     string
+	imageFont       string
+	imageCharts     bool // コマンドが -images に対応している
+	output          string
+	outputs         bool // コマンドが -output に対応している
+	limits          report.Limits
+	post            string
+	dryRun          bool
+}
+
+// outputSQLite は -output でSQLiteのデータベースに書き出す場合の接頭辞
+const outputSQLite = "sqlite:"
+
+// addAnalysisFlags は分析コマンド共通のフラグを登録する
+func addAnalysisFlags(fs *flag.FlagSet) *analysisFlags {
+	f := &analysisFlags{fs: fs, formats: []string{report.FormatText}}
+	fs.StringVar(&f.configPath, "config", "", "設定ファイルのパス（省略時は ./"+config.FileName+" など）")
+	fs.StringVar(&f.profileName, "profile", "", "設定ファイルのプロファイル名")
+	fs.StringVar(&f.start, "start", "", "開始日（YYYY-MM-DD形式、省略可）")
+	fs.StringVar(&f.end, "end", "", "終了日（YYYY-MM-DD形式、省略可、その日を含む）")
+	fs.StringVar(&f.period, "period", "", "直近の期間（例: 7d, 2w, 1m）。-start/-end とは併用不可")
+	fs.Var(&f.excludeUsers, "exclude-users", "集計から除外するユーザー（名前またはID、カンマ区切り）")
+	fs.Var(&f.excludeChannels, "exclude-channels", "分析から除外するチャンネル（名前・ID・globパターン、カンマ区切り）")
+	fs.StringVar(&f.format, "format", report.FormatText, "出力形式（"+strings.Join(report.Formats, ", ")+"）")
+	fs.StringVar(&f.csvDir, "csv-dir", "", "-format csv でランキングごとのCSVファイルを書き出すディレクトリ（省略時は1つのCSVを標準出力に出力）")
+	fs.BoolVar(&f.bom, "bom", false, "-format csv の出力の先頭にUTF-8のBOMを付ける（Excelで開く場合）")
+	fs.BoolVar(&f.mermaid, "mermaid", false, "-format markdown でスタンプの内訳をMermaidの円グラフで表示する")
+	fs.StringVar(&f.template, "template", "", "テキスト形式の代わりに使用するテンプレートファイル（Goのtext/template。拡張子が .html の場合はhtml/template）")
+	fs.StringVar(&f.chart, "chart", chartAuto, "テキスト形式でランキングを横棒グラフ、推移をスパークラインで表示するか（auto: 端末に出力する場合のみ, always, never）")
+	fs.StringVar(&f.images, "images", "", "グラフの画像（スタンプのランキング・投稿者の割合・投稿数の推移）を書き出すディレクトリ。-post と併用した場合は投稿のスレッドに添付する")
+	fs.StringVar(&f.imageFormat, "image-format", chart.FormatPNG, "-images で書き出す画像の形式（"+strings.Join(chart.Formats, ", ")+"）")
+	fs.StringVar(&f.imageFont, "image-font", "", "PNGの画像の文字に使用するフォントファイル（.ttf / .otf）。省略時は英数字のみの組み込みのフォント")
+	fs.StringVar(&f.output, "output", "", "分析に使用したデータ（メッセージ・リアクション・ユーザー・チャンネル・スレッド）の書き出し先（sqlite:<ファイル>）。同じファイルには既存の行を更新して書き出す")
+	fs.StringVar(&f.post, "post", "", "分析結果をBlock Kitのメッセージとして投稿するチャンネル名")
+	fs.BoolVar(&f.dryRun, "dry-run", false, "-post の代わりに投稿するメッセージのJSONを出力する")
+	fs.IntVar(&f.limits.Emoji, "limit-emoji", 0, "スタンプランキングの表示件数（0の場合は表示しない）")
+	fs.IntVar(&f.limits.Message, "limit-message", 0, "メッセージランキングの表示件数（0の場合は表示しない）")
+	fs.IntVar(&f.limits.User, "limit-user", 0, "投稿者ランキングの表示件数（0の場合は表示しない）")
+	fs.IntVar(&f.limits.Thread, "limit-thread", 0, "スレッドランキングの表示件数（0の場合は表示しない）")
+	fs.IntVar(&f.limits.Channel, "limit-channel", 0, "チャンネルランキングの表示件数（0の場合は表示しない。channels / workspace / emoji コマンド）")
+	return f
+}
+
+// allowFormats はテキスト形式以外にコマンドが対応している出力形式を追加する
+func (f *analysisFlags) allowFormats(formats ...string) {
+	f.formats = append(f.formats, formats...)
+}
+
+// allowExcludeChannels はコマンドが -exclude-channels（プロファイルの exclude_channels）に対応していることを設定する
+func (f *analysisFlags) allowExcludeChannels() {
+	f.channelExcludes = true
+}
+
+// allowTemplate はコマンドが -template に対応していることを設定する
+func (f *analysisFlags) allowTemplate() {
+	f.templates = true
+}
+
+// allowImages はコマンドが -images に対応していることを設定する
+func (f *analysisFlags) allowImages() {
+	f.imageCharts = true
+}
+
+// allowOutput はコマンドが -output に対応していることを設定する
+func (f *analysisFlags) allowOutput() {
+	f.outputs = true
+}
+
+// analysisOptions はプロファイルとフラグを解決した分析オプション
+type analysisOptions struct {
+	command         string   // JSON出力のメタデータに含めるコマンド名
+	channels        []string // プロファイルで指定されたチャンネル
+	user            string   // プロファイルで指定されたユーザー
+	dateRange       *domain.DateRange
+	excludeUsers    []string
+	excludeChannels []string
+	limits          report.Limits
+	format          string
+	csvDir          string           // ランキングごとのCSVファイルを書き出すディレクトリ
+	bom             bool             // CSVの先頭にUTF-8のBOMを付ける
+	mermaid         bool             // Markdownにスタンプの内訳の円グラフを含める
+	template        *report.Template // テキスト形式の代わりに使用するテンプレート
+	chart           string           // テキスト形式のグラフの表示（auto / always / never）
+	images          string           // グラフの画像を書き出すディレクトリ
+	imageFormat     string           // グラフの画像の形式
+	imageFont       *chart.Font      // PNGの画像の文字に使用するフォント（nilの場合は組み込みのフォント）
+	sqlite          string           // 分析に使用したデータを書き出すSQLiteのデータベースのパス
+	tokenEnv        string
+	post            string // 分析結果を投稿するチャンネル名
+	dryRun          bool   // 投稿せずにメッセージのJSONを出力する
+}
+
+// resolve はプロファイルとフラグから分析オプションを作成する
+// 優先順位: フラグ > プロファイル > コマンドのデフォルト値
+func (f *analysisFlags) resolve(defaults report.Limits) (*analysisOptions, error) {
+	profile := &config.Profile{}
+	if f.profileName != "" {
+		p, err := f.loadProfile()
+		if err != nil {
+			return nil, &usageError{msg: err.Error()}
+		}
+		profile = p
+	}
+
+	opts := profileOptions(profile, defaults)
+	opts.command = f.fs.Name()
+	if f.isSet("format") {
+		opts.format = f.format
+	}
+	if !report.IsSupportedFormat(opts.format) {
+		return nil, newUsageError("出力形式 '%s' には対応していません（%s）", opts.format, strings.Join(report.Formats, ", "))
+	}
+	if !slices.Contains(f.formats, opts.format) {
+		return nil, newUsageError("このコマンドは出力形式 '%s' に対応していません（%s）", opts.format, strings.Join(f.formats, ", "))
+	}
+	if (f.csvDir != "" || f.bom) && opts.format != report.FormatCSV {
+		return nil, newUsageError("-csv-dir と -bom は -format csv と併せて指定してください")
+	}
+	opts.csvDir = f.csvDir
+	opts.bom = f.bom
+	if f.mermaid && opts.format != report.FormatMarkdown {
+		return nil, newUsageError("-mermaid は -format markdown と併せて指定してください")
+	}
+	opts.mermaid = f.mermaid
+	if f.dryRun && f.post == "" {
+		return nil, newUsageError("-dry-run は -post と併せて指定してください")
+	}
+	opts.post = trimChannelName(f.post)
+	if f.template != "" {
+		switch {
+		case !f.templates:
+			return nil, newUsageError("このコマンドは -template に対応していません")
+		case opts.format != report.FormatText:
+			return nil, newUsageError("-template は -format text（デフォルト）でのみ指定できます")
+		case opts.post != "":
+			return nil, newUsageError("-template と -post は同時に指定できません")
+		}
+		tmpl, err := report.LoadTemplate(f.template)
+		if err != nil {
+			return nil, &usageError{msg: err.Error()}
+		}
+		opts.template = tmpl
+	}
+	if !slices.Contains(chartModes, f.chart) {
+		return nil, newUsageError("-chart には %s のいずれかを指定してください", strings.Join(chartModes, ", "))
+	}
+	if f.isSet("chart") && opts.format != report.FormatText {
+		return nil, newUsageError("-chart は -format text（デフォルト）でのみ指定できます")
+	}
+	opts.chart = f.chart
+	if f.images == "" && (f.isSet("image-format") || f.imageFont != "") {
+		return nil, newUsageError("-image-format と -image-font は -images と併せて指定してください")
+	}
+	if f.images != "" {
+		if !f.imageCharts {
+			return nil, newUsageError("このコマンドは -images に対応していません")
+		}
+		if !slices.Contains(chart.Formats, f.imageFormat) {
+			return nil, newUsageError("-image-format には %s のいずれかを指定してください", strings.Join(chart.Formats, ", "))
+		}
+		if f.imageFont != "" {
+			font, err := chart.LoadFont(f.imageFont)
+			if err != nil {
+				return nil, &usageError{msg: err.Error()}
+			}
+			opts.imageFont = font
+		}
+	}
+	opts.images = f.images
+	if f.output != "" {
+		if !f.outputs {
+			return nil, newUsageError("このコマンドは -output に対応していません")
+		}
+		path, ok := strings.CutPrefix(f.output, outputSQLite)
+		if !ok || path == "" {
+			return nil, newUsageError("-output には %s<ファイル> の形式で書き出し先を指定してください（例: %sanalysis.db）", outputSQLite, outputSQLite)
+		}
+		opts.sqlite = path
+	}
+	opts.imageFormat = f.imageFormat
+	opts.dryRun = f.dryRun
+	if f.isSet("exclude-users") {
+		opts.excludeUsers = f.excludeUsers
+	}
+	if f.isSet("exclude-channels") {
+		opts.excludeChannels = f.excludeChannels
+	}
+	if len(opts.excludeChannels) > 0 && !f.channelExcludes {
+		return nil, newUsageError("このコマンドは -exclude-channels（プロファイルの exclude_channels）に対応していません")
+	}
+
+	// 期間はフラグで指定された場合、プロファイルの期間指定全体を置き換える
+	now := time.Now()
+	var err error
+	switch {
+	case f.period != "" && (f.start != "" || f.end != ""):
+		return nil, newUsageError("-period と -start/-end は同時に指定できません")
+	case f.period != "":
+		opts.dateRange, err = domain.ParseRelativeDateRange(f.period, now)
+	case f.start != "" || f.end != "":
+		opts.dateRange, err = domain.ParseDateRange(f.start, f.end, time.Local)
+	default:
+		opts.dateRange, err = profile.DateRange(now)
+	}
+	if err != nil {
+		return nil, &usageError{msg: err.Error()}
+	}
+
+	for name, dst := range map[string]*int{
+		"limit-emoji":   &opts.limits.Emoji,
+		"limit-message": &opts.limits.Message,
+		"limit-user":    &opts.limits.User,
+		"limit-thread":  &opts.limits.Thread,
+		"limit-channel": &opts.limits.Channel,
+	} {
+		if !f.isSet(name) {
+			continue
+		}
+		value := f.fs.Lookup(name).Value.(flag.Getter).Get().(int)
+		if value < 0 {
+			return nil, newUsageError("-%s には0以上の値を指定してください", name)
+		}
+		*dst = value
+	}
+
+	return opts, nil
+}
+
+// profileOptions はプロファイルの値とコマンドのデフォルト値から分析オプションを作成する（期間は含まない）
+func profileOptions(profile *config.Profile, defaults report.Limits) *analysisOptions {
+	opts := &analysisOptions{
+		channels:        profile.Channels,
+		user:            profile.User,
+		excludeUsers:    profile.ExcludeUsers,
+		excludeChannels: profile.ExcludeChannels,
+		limits:          mergeLimits(defaults, profile.Limits),
+		format:          report.FormatText,
+		tokenEnv:        profile.TokenEnv,
+	}
+	if profile.Format != "" {
+		opts.format = profile.Format
+	}
+	return opts
+}
+
+// loadProfile は設定ファイルから指定されたプロファイルを読み込む
+func (f *analysisFlags) loadProfile() (*config.Profile, error) {
+	cfg, err := loadConfig(f.configPath)
+	if err != nil {
+		return nil, err
+	}
+	return cfg.Profile(f.profileName)
+}
+
+// loadConfig は設定ファイルを読み込む（path が空の場合はデフォルトのパスを探索する）
+func loadConfig(path string) (*config.Config, error) {
+	if path == "" {
+		p, err := config.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = p
+	}
+	return config.Load(path)
+}
+
+// isSet はフラグが明示的に指定されたかどうかを返す
+func (f *analysisFlags) isSet(name string) bool {
+	set := false
+	f.fs.Visit(func(fl *flag.Flag) {
+		if fl.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// mergeLimits はデフォルトの表示件数をプロファイルの値（0以外）で上書きする
+func mergeLimits(defaults report.Limits, overrides config.Limits) report.Limits {
+	limits := defaults
+	if overrides.Emoji > 0 {
+		limits.Emoji = overrides.Emoji
+	}
+	if overrides.Message > 0 {
+		limits.Message = overrides.Message
+	}
+	if overrides.User > 0 {
+		limits.User = overrides.User
+	}
+	if overrides.Thread > 0 {
+		limits.Thread = overrides.Thread
+	}
+	if overrides.Channel > 0 {
+		limits.Channel = overrides.Channel
+	}
+	return limits
+}
+
+// excludeChannels はチャンネルを横断する分析（ユーザー分析・検索結果の分析）から除外するチャンネルを設定する
+func excludeChannels(ctx context.Context, a *app, patterns []string) error {
+	if len(patterns) == 0 {
+		return nil
+	}
+	channels, err := a.channelRepo.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	a.analyzer.ExcludeChannels(service.ExcludedChannelIDs(channels, patterns))
+	return nil
+}
+
+// newAnalysisApp は分析オプションに従って依存関係を組み立て、除外ユーザーを設定する
+func newAnalysisApp(ctx context.Context, opts *analysisOptions, stderr io.Writer) (*app, error) {
+	a, err := newApp(opts.tokenEnv)
+	if err != nil {
+		return nil, err
+	}
+	// 標準出力を分析結果のみにするため、進捗は標準エラー出力に表示する
+	a.analyzer.SetProgressOutput(stderr)
+	a.messageRepo.SetProgressOutput(stderr)
+	if len(opts.excludeUsers) > 0 {
+		userIDs, err := service.ResolveUserIDs(ctx, a.userRepo, opts.excludeUsers)
+		if err != nil {
+			return nil, err
+		}
+		a.analyzer.ExcludeUsers(userIDs)
+	}
+	return a, nil
+}
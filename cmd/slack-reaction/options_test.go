@@ -0,0 +1,120 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/report"
+)
+
+const testConfig = `
+profiles:
+  weekly-eng:
+    channels: ["team-*"]
+    start: 2024-01-01
+    end: 2024-01-07
+    exclude_users: [deploy-bot]
+    limits:
+      emoji: 5
+      user: 20
+    token_env: ENG_SLACK_TOKEN
+  without-random:
+    exclude_channels: [random]
+`
+
+func TestAnalysisFlags_Resolve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		args         []string
+		wantLimits   report.Limits
+		wantStart    time.Time
+		wantExclude  []string
+		wantChannels []string
+		wantTokenEnv string
+		wantErr      bool
+	}{
+		{
+			name:       "プロファイルなし",
+			args:       nil,
+			wantLimits: report.DefaultChannelLimits,
+		},
+		{
+			name:         "プロファイルの値を使用",
+			args:         []string{"-config", path, "-profile", "weekly-eng"},
+			wantLimits:   report.Limits{Emoji: 5, Message: 3, User: 20, Thread: 3, Channel: 10},
+			wantStart:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local),
+			wantExclude:  []string{"deploy-bot"},
+			wantChannels: []string{"team-*"},
+			wantTokenEnv: "ENG_SLACK_TOKEN",
+		},
+		{
+			name:         "フラグでプロファイルを上書き",
+			args:         []string{"-config", path, "-profile", "weekly-eng", "-limit-emoji", "1", "-start", "2024-02-01", "-exclude-users", "a,b"},
+			wantLimits:   report.Limits{Emoji: 1, Message: 3, User: 20, Thread: 3, Channel: 10},
+			wantStart:    time.Date(2024, 2, 1, 0, 0, 0, 0, time.Local),
+			wantExclude:  []string{"a", "b"},
+			wantChannels: []string{"team-*"},
+			wantTokenEnv: "ENG_SLACK_TOKEN",
+		},
+		{
+			name:    "存在しないプロファイル",
+			args:    []string{"-config", path, "-profile", "unknown"},
+			wantErr: true,
+		},
+		{
+			name:    "-exclude-channelsに対応していないコマンド",
+			args:    []string{"-config", path, "-profile", "without-random"},
+			wantErr: true,
+		},
+		{
+			name:    "負の表示件数",
+			args:    []string{"-limit-user", "-1"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := newFlagSet("test", "", &bytes.Buffer{})
+			flags := addAnalysisFlags(fs)
+			if _, err := parseFlags(fs, tt.args); err != nil {
+				t.Fatalf("parseFlags() error = %v", err)
+			}
+
+			opts, err := flags.resolve(report.DefaultChannelLimits)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if opts.limits != tt.wantLimits {
+				t.Errorf("limits = %+v, want %+v", opts.limits, tt.wantLimits)
+			}
+			if !opts.dateRange.Start.Equal(tt.wantStart) {
+				t.Errorf("dateRange.Start = %v, want %v", opts.dateRange.Start, tt.wantStart)
+			}
+			if !reflect.DeepEqual(opts.excludeUsers, tt.wantExclude) {
+				t.Errorf("excludeUsers = %v, want %v", opts.excludeUsers, tt.wantExclude)
+			}
+			if !reflect.DeepEqual(opts.channels, tt.wantChannels) {
+				t.Errorf("channels = %v, want %v", opts.channels, tt.wantChannels)
+			}
+			if opts.tokenEnv != tt.wantTokenEnv {
+				t.Errorf("tokenEnv = %q, want %q", opts.tokenEnv, tt.wantTokenEnv)
+			}
+			if opts.format != report.FormatText {
+				t.Errorf("format = %q, want %q", opts.format, report.FormatText)
+			}
+		})
+	}
+}
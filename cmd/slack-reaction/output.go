@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/chart"
+	slackinfra "github.com/Tattsum/slack-reaction/internal/infrastructure/slack"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/store"
+	"golang.org/x/term"
+)
+
+// version はツールのバージョン（リリース時に -ldflags "-X main.version=v1.2.3" で設定する）
+var version = ""
+
+// toolVersion はツールのバージョンを返す
+// ビルド時に設定されていない場合は go install で記録されたモジュールのバージョン、それも不明な場合は "dev"
+func toolVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
+
+// analysisReport は分析結果を出力先に応じた形式で書き出すための情報
+type analysisReport struct {
+	title string                                           // Slackに投稿するメッセージのタイトル
+	write func(w io.Writer, text report.TextOptions) error // テキスト形式で書き出す（template がある場合は使用しない）
+
+	// テキスト形式以外の書き出し（対応していないコマンドはnil）
+	json     func(w io.Writer, meta report.Metadata) error
+	csv      func() []report.CSVSection
+	markdown func(w io.Writer, md report.MarkdownOptions) error
+	html     func(w io.Writer, meta report.Metadata, link report.MessageLinker) error
+	template func(meta report.Metadata) *report.TemplateData // テキスト形式・-template のテンプレートに渡すデータ
+	charts   func() []*chart.Chart                           // -images で書き出すグラフ
+	dataset  func() *store.Dataset                           // -output で書き出すデータ（ユーザーは書き出す際に取得する）
+
+	blocks   blockBuilder // Block Kitのブロックを作成する
+	warnings []string     // 一部のデータを取得できなかった場合の警告
+}
+
+// render は分析結果を opts.format の形式で書き出す
+// テキスト形式で opts.template が指定されている場合は、そのテンプレートで書き出す
+// テキスト形式のグラフの表示と表示幅は opts.chart と w が端末かどうかで決める
+// link はMarkdown・HTML形式でメッセージへのリンクを作成するために使用する（nilの場合はリンクにしない）
+func (rep *analysisReport) render(w io.Writer, opts *analysisOptions, link report.MessageLinker) error {
+	switch opts.format {
+	case report.FormatJSON:
+		if rep.json == nil {
+			return fmt.Errorf("出力形式 '%s' には対応していません", opts.format)
+		}
+		return rep.json(w, report.NewMetadata(opts.command, toolVersion(), opts.dateRange, time.Now()))
+	case report.FormatCSV:
+		if rep.csv == nil {
+			return fmt.Errorf("出力形式 '%s' には対応していません", opts.format)
+		}
+		if opts.csvDir != "" {
+			return writeCSVFiles(w, opts.csvDir, rep.csv(), opts.bom)
+		}
+		return report.WriteLongCSV(w, rep.csv(), opts.bom)
+	case report.FormatMarkdown:
+		if rep.markdown == nil {
+			return fmt.Errorf("出力形式 '%s' には対応していません", opts.format)
+		}
+		return rep.markdown(w, report.MarkdownOptions{Link: link, Mermaid: opts.mermaid})
+	case report.FormatHTML:
+		if rep.html == nil {
+			return fmt.Errorf("出力形式 '%s' には対応していません", opts.format)
+		}
+		return rep.html(w, report.NewMetadata(opts.command, toolVersion(), opts.dateRange, time.Now()), link)
+	default:
+		text := textOptions(w, opts.chart)
+		if rep.template == nil {
+			if opts.template != nil {
+				return fmt.Errorf("-template には対応していません")
+			}
+			return rep.write(w, text)
+		}
+		data := rep.template(report.NewMetadata(opts.command, toolVersion(), opts.dateRange, time.Now()))
+		data.TextOptions = text
+		if opts.template != nil {
+			return opts.template.Execute(w, data)
+		}
+		return report.WriteText(w, data)
+	}
+}
+
+// -chart の値
+const (
+	chartAuto   = "auto"   // 端末に出力する場合のみグラフを表示する
+	chartAlways = "always" // 常にグラフを表示する
+	chartNever  = "never"  // グラフを表示しない
+)
+
+// chartModes は -chart に指定できる値
+var chartModes = []string{chartAuto, chartAlways, chartNever}
+
+// textOptions はテキスト形式の出力のオプションを決める
+// グラフを表示する場合、w が端末であればその幅に収め、それ以外は report.DefaultTextWidth に収める
+func textOptions(w io.Writer, chart string) report.TextOptions {
+	var text report.TextOptions
+	file, ok := w.(*os.File)
+	terminal := ok && term.IsTerminal(int(file.Fd()))
+	switch chart {
+	case chartAlways:
+		text.Charts = true
+	case chartNever:
+		return text
+	default:
+		text.Charts = terminal
+	}
+	if terminal {
+		if width, _, err := term.GetSize(int(file.Fd())); err == nil && width > 0 {
+			text.Width = width
+		}
+	}
+	return text
+}
+
+// writeCSVFiles はランキングごとのCSVファイル（<セクション名>.csv）をディレクトリに書き出し、書き出したパスを w に表示する
+func writeCSVFiles(w io.Writer, dir string, sections []report.CSVSection, bom bool) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("出力先のディレクトリ作成エラー: %w", err)
+	}
+	for _, section := range sections {
+		path := filepath.Join(dir, section.Name+".csv")
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("出力ファイル作成エラー: %w", err)
+		}
+		if err := report.WriteCSV(file, section, bom); err != nil {
+			file.Close()
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		fmt.Fprintln(w, path)
+	}
+	return nil
+}
+
+// writeChartImages はグラフの画像（<グラフ名>.<形式>）を opts.images のディレクトリに書き出し、書き出したパスを w に表示する
+// Slackの投稿に添付できるよう、書き出した画像を返す
+func writeChartImages(w io.Writer, opts *analysisOptions, charts []*chart.Chart) ([]slackinfra.File, error) {
+	if err := os.MkdirAll(opts.images, 0o755); err != nil {
+		return nil, fmt.Errorf("画像の出力先のディレクトリ作成エラー: %w", err)
+	}
+	var files []slackinfra.File
+	for _, c := range charts {
+		var buf bytes.Buffer
+		if err := c.Render(&buf, opts.imageFormat, opts.imageFont); err != nil {
+			return nil, err
+		}
+		name := c.FileName(opts.imageFormat)
+		path := filepath.Join(opts.images, name)
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			return nil, fmt.Errorf("画像の書き出しエラー: %w", err)
+		}
+		fmt.Fprintln(w, path)
+		files = append(files, slackinfra.File{Name: name, Title: c.Title, Alt: c.Alt, Data: buf.Bytes()})
+	}
+	return files, nil
+}
+
+// writeReport は分析結果を出力する
+// opts.post が指定されている場合はSlackに投稿し、それ以外は stdout に opts.format の形式で出力する
+// opts.images が指定されている場合はグラフの画像を書き出し（パスは stderr に表示する）、投稿する場合はスレッドに添付する
+// opts.sqlite が指定されている場合は分析に使用したデータをSQLiteのデータベースに書き出す
+func writeReport(ctx context.Context, a *app, opts *analysisOptions, rep *analysisReport, stdout, stderr io.Writer) error {
+	if opts.sqlite != "" && rep.dataset != nil {
+		warnings, err := writeSQLite(ctx, a, opts.sqlite, rep.dataset(), stderr)
+		if err != nil {
+			return err
+		}
+		rep.warnings = append(rep.warnings, warnings...)
+	}
+
+	var images []slackinfra.File
+	if opts.images != "" && rep.charts != nil {
+		files, err := writeChartImages(stderr, opts, rep.charts())
+		if err != nil {
+			return err
+		}
+		images = files
+	}
+
+	var err error
+	if opts.post != "" {
+		err = publish(ctx, a, opts, rep.title, rep.blocks, images, stdout)
+	} else {
+		link, warning := messageLinker(ctx, a, opts)
+		if warning != "" {
+			rep.warnings = append(rep.warnings, warning)
+		}
+		err = rep.render(stdout, opts, link)
+	}
+	if err != nil {
+		return err
+	}
+	return printWarnings(stderr, rep.warnings)
+}
+
+// writeSQLite はデータセットに投稿者のユーザー情報と、名前の分からないチャンネルの情報を加えてSQLiteのデータベースに書き出す
+// ユーザー・チャンネルの情報を取得できない場合は、それらを除いて書き出し、警告を返す
+func writeSQLite(ctx context.Context, a *app, path string, data *store.Dataset, w io.Writer) ([]string, error) {
+	var warnings []string
+
+	userIDs := make([]string, 0)
+	seenUsers := make(map[string]bool)
+	knownChannels := make(map[string]bool, len(data.Channels))
+	for _, channel := range data.Channels {
+		knownChannels[channel.ID] = true
+	}
+	missingChannels := make(map[string]bool)
+	for _, msg := range data.Messages {
+		if msg.UserID != "" && !seenUsers[msg.UserID] {
+			seenUsers[msg.UserID] = true
+			userIDs = append(userIDs, msg.UserID)
+		}
+		if !knownChannels[msg.ChannelID] {
+			missingChannels[msg.ChannelID] = true
+		}
+	}
+
+	users, err := a.userRepo.FindByIDs(ctx, userIDs)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("ユーザー情報を取得できなかったため、users テーブルには書き出しません: %v", err))
+	}
+	for _, userID := range userIDs {
+		if user, ok := users[userID]; ok {
+			data.Users = append(data.Users, user)
+		}
+	}
+
+	if len(missingChannels) > 0 {
+		channels, err := a.channelRepo.FindAll(ctx)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("チャンネル一覧を取得できなかったため、一部のチャンネルを channels テーブルに書き出しません: %v", err))
+		}
+		for _, channel := range channels {
+			if missingChannels[channel.ID] {
+				data.Channels = append(data.Channels, channel)
+			}
+		}
+	}
+
+	summary, err := store.WriteSQLite(ctx, path, data)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(w, "%s に書き出しました（メッセージ %d件、リアクション %d件、スレッド %d件、ユーザー %d人、チャンネル %d件）\n",
+		path, summary.Messages, summary.Reactions, summary.Threads, summary.Users, summary.Channels)
+	return warnings, nil
+}
+
+// messageLinker は出力形式がメッセージへのリンクを含む場合に、リンクの作成方法を返す
+// ワークスペースのURLを取得できない場合はリンクにせず、警告を返す
+func messageLinker(ctx context.Context, a *app, opts *analysisOptions) (report.MessageLinker, string) {
+	if opts.format != report.FormatMarkdown && opts.format != report.FormatHTML {
+		return nil, ""
+	}
+	teamURL, err := a.publisher.TeamURL(ctx)
+	if err != nil {
+		return nil, fmt.Sprintf("メッセージへのリンクを作成できませんでした: %v", err)
+	}
+	return func(channelID, ts string) string {
+		return slackinfra.MessageURL(teamURL, channelID, ts)
+	}, ""
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	slackinfra "github.com/Tattsum/slack-reaction/internal/infrastructure/slack"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/slack-go/slack"
+)
+
+// blockBuilder はメッセージのリンクの作成方法を受け取り、投稿するブロックを作成する
+type blockBuilder func(link report.MessageLinker) []slack.Block
+
+// publishPayload は -dry-run で出力する chat.postMessage のペイロード
+type publishPayload struct {
+	Channel string        `json:"channel"`
+	Text    string        `json:"text"`
+	Blocks  []slack.Block `json:"blocks"`
+	Files   []string      `json:"files,omitempty"` // スレッドに添付するファイル名
+}
+
+// publish は分析結果をBlock Kitのメッセージとして opts.post のチャンネルに投稿する
+// files が指定された場合は、投稿したメッセージのスレッドに添付する
+// opts.dryRun の場合は投稿せず、ペイロードのJSONを stdout に出力する
+func publish(ctx context.Context, a *app, opts *analysisOptions, text string, build blockBuilder, files []slackinfra.File, stdout io.Writer) error {
+	teamURL, err := a.publisher.TeamURL(ctx)
+	if err != nil {
+		return err
+	}
+	blocks := build(func(channelID, ts string) string {
+		return slackinfra.MessageURL(teamURL, channelID, ts)
+	})
+
+	if opts.dryRun {
+		payload := publishPayload{Channel: "#" + opts.post, Text: text, Blocks: blocks}
+		for _, file := range files {
+			payload.Files = append(payload.Files, file.Name)
+		}
+		encoder := json.NewEncoder(stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(payload)
+	}
+
+	channel, err := a.channelRepo.FindByName(ctx, opts.post)
+	if err != nil {
+		return err
+	}
+	url, err := a.publisher.PostBlocks(ctx, channel.ID, blocks, text, files...)
+	if err != nil {
+		if url != "" {
+			return fmt.Errorf("%w（投稿したメッセージ: %s）", err, url)
+		}
+		return err
+	}
+	fmt.Fprintf(stdout, "#%s に分析結果を投稿しました %s\n", channel.Name, url)
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Tattsum/slack-reaction/internal/chart"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/store"
+	"github.com/slack-go/slack"
+)
+
+// runSearch は search コマンドを実行する
+func runSearch(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("search", "[オプション] <検索クエリ>", stderr)
+	flags := addAnalysisFlags(fs)
+	flags.allowFormats(report.FormatJSON, report.FormatCSV, report.FormatMarkdown, report.FormatHTML)
+	flags.allowExcludeChannels()
+	flags.allowTemplate()
+	flags.allowImages()
+	flags.allowOutput()
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	opts, err := flags.resolve(report.DefaultChannelLimits)
+	if err != nil {
+		return err
+	}
+	// 引用符で囲まれていない検索クエリは、スペースで区切られた複数の引数として渡される
+	query := strings.TrimSpace(strings.Join(positional, " "))
+	if query == "" {
+		return newUsageError("検索クエリを指定してください（例: 'in:#general has:link \"release\"'）")
+	}
+
+	a, err := newAnalysisApp(ctx, opts, stderr)
+	if err != nil {
+		return err
+	}
+	rep, err := searchReport(ctx, a, query, opts)
+	if err != nil {
+		return err
+	}
+	return writeReport(ctx, a, opts, rep, stdout, stderr)
+}
+
+// searchReport は検索クエリに一致したメッセージを分析する
+// テキスト形式ではメッセージに投稿先のチャンネル名を付記する（チャンネル一覧を取得できない場合はチャンネルID）
+func searchReport(ctx context.Context, a *app, query string, opts *analysisOptions) (*analysisReport, error) {
+	if err := excludeChannels(ctx, a, opts.excludeChannels); err != nil {
+		return nil, err
+	}
+	result, err := a.analyzer.AnalyzeSearch(ctx, query, opts.dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string)
+	channels, err := a.channelRepo.FindAll(ctx)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("チャンネル一覧の取得に失敗したため、チャンネルIDで表示します: %v", err))
+	}
+	for _, channel := range channels {
+		names[channel.ID] = channel.Name
+	}
+	channelName := func(channelID string) string {
+		if name, ok := names[channelID]; ok {
+			return name
+		}
+		return channelID
+	}
+
+	title := "検索「" + query + "」の分析結果"
+	return &analysisReport{
+		title: title,
+		json: func(w io.Writer, meta report.Metadata) error {
+			meta.Query = query
+			return report.WriteChannelJSON(w, meta, result, opts.limits)
+		},
+		csv: func() []report.CSVSection {
+			return report.ChannelCSVSections(result, opts.limits)
+		},
+		markdown: func(w io.Writer, md report.MarkdownOptions) error {
+			return report.WriteChannelMarkdown(w, title, opts.dateRange, result, opts.limits, md)
+		},
+		html: func(w io.Writer, meta report.Metadata, link report.MessageLinker) error {
+			meta.Query = query
+			return report.WriteChannelHTML(w, title, meta, result, link)
+		},
+		template: func(meta report.Metadata) *report.TemplateData {
+			meta.Query = query
+			return report.NewSearchTemplateData(meta, title, result, opts.limits, channelName)
+		},
+		charts: func() []*chart.Chart {
+			return chart.FromAnalysisResult(result, opts.dateRange, opts.limits)
+		},
+		dataset: func() *store.Dataset {
+			return &store.Dataset{Messages: result.Messages}
+		},
+		blocks: func(link report.MessageLinker) []slack.Block {
+			return report.ChannelBlocks(title, opts.dateRange, result, opts.limits, link)
+		},
+		warnings: result.Warnings,
+	}, nil
+}
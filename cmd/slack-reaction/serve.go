@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/api"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/Tattsum/slack-reaction/internal/slashcommand"
+)
+
+// defaultSigningSecretEnv はスラッシュコマンドの署名シークレットを設定するデフォルトの環境変数名
+const defaultSigningSecretEnv = "SLACK_SIGNING_SECRET"
+
+// slashCommandPath はスラッシュコマンドのリクエストを受け付けるパス
+const slashCommandPath = "/slack/commands"
+
+// shutdownTimeout はサーバー停止時に処理中のリクエストを待つ最大時間
+const shutdownTimeout = 10 * time.Second
+
+// runServe は serve コマンドを実行する
+func runServe(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("serve", "[オプション]", stderr)
+	var (
+		addr             string
+		tokenEnv         string
+		signingSecretEnv string
+		maxJobs          int
+		maxQueuedJobs    int
+		excludeUsers     stringList
+	)
+	fs.StringVar(&addr, "addr", "localhost:8080", "待ち受けるアドレス")
+	fs.StringVar(&tokenEnv, "token-env", defaultTokenEnv, "Slackトークンを読み込む環境変数名")
+	fs.StringVar(&signingSecretEnv, "signing-secret-env", defaultSigningSecretEnv, "スラッシュコマンドの署名シークレットを読み込む環境変数名（設定されている場合のみ "+slashCommandPath+" を有効にする）")
+	fs.IntVar(&maxJobs, "max-jobs", 2, "同時に実行する分析（同期実行と非同期ジョブ）の最大数")
+	fs.IntVar(&maxQueuedJobs, "max-queued-jobs", 20, "実行待ちにできる分析（同期実行と非同期ジョブ）の最大数（超えた場合は 503 を返す）")
+	fs.Var(&excludeUsers, "exclude-users", "集計から除外するユーザー（名前またはID、カンマ区切り）")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return newUsageError("serve コマンドは位置引数を受け付けません: %v", positional)
+	}
+	if maxJobs < 1 {
+		return newUsageError("-max-jobs には1以上の値を指定してください")
+	}
+	if maxQueuedJobs < 0 {
+		return newUsageError("-max-queued-jobs には0以上の値を指定してください")
+	}
+
+	a, err := newApp(tokenEnv)
+	if err != nil {
+		return err
+	}
+	if len(excludeUsers) > 0 {
+		userIDs, err := service.ResolveUserIDs(ctx, a.userRepo, excludeUsers)
+		if err != nil {
+			return err
+		}
+		a.analyzer.ExcludeUsers(userIDs)
+	}
+	a.analyzer.SetProgressOutput(io.Discard)
+	a.messageRepo.SetProgressOutput(io.Discard)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("待ち受けエラー: %w", err)
+	}
+
+	// ジョブはサーバーの停止時にキャンセルする
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+	apiServer := api.NewServer(jobCtx, a.analyzer, a.channelRepo, maxJobs, maxQueuedJobs)
+	apiServer.SetToolVersion(toolVersion())
+	mux := http.NewServeMux()
+	mux.Handle("/", apiServer)
+	var slashHandler *slashcommand.Handler
+	if secret := os.Getenv(signingSecretEnv); secret != "" {
+		slashHandler = slashcommand.NewHandler(jobCtx, secret, a.analyzer, a.channelRepo)
+		slashHandler.SetLogOutput(stderr)
+		mux.Handle("POST "+slashCommandPath, slashHandler)
+	}
+	server := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+	fmt.Fprintf(stderr, "APIサーバーを起動しました: http://%s（API定義: /openapi.json）\n", listener.Addr())
+	if slashHandler != nil {
+		fmt.Fprintf(stderr, "スラッシュコマンドを受け付けます: %s\n", slashCommandPath)
+	}
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("サーバーエラー: %w", err)
+	case <-ctx.Done():
+	}
+
+	fmt.Fprintf(stderr, "APIサーバーを停止しています...\n")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	cancelJobs()
+	if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("サーバー停止エラー: %w", err)
+	}
+	apiServer.Wait()
+	if slashHandler != nil {
+		slashHandler.Wait()
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	slackinfra "github.com/Tattsum/slack-reaction/internal/infrastructure/slack"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/slack-go/slack"
+)
+
+// runThread は thread コマンドを実行する
+func runThread(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("thread", "[オプション] <メッセージのパーマリンク>", stderr)
+	flags := addAnalysisFlags(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	opts, err := flags.resolve(report.DefaultThreadLimits)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return newUsageError("メッセージのパーマリンク（「リンクをコピー」のURL）を1つ指定してください")
+	}
+	channelID, threadTS, err := slackinfra.ParsePermalink(positional[0])
+	if err != nil {
+		return &usageError{msg: err.Error()}
+	}
+
+	a, err := newAnalysisApp(ctx, opts, stderr)
+	if err != nil {
+		return err
+	}
+	rep, err := threadReport(ctx, a, channelID, threadTS, opts)
+	if err != nil {
+		return err
+	}
+	return writeReport(ctx, a, opts, rep, stdout, stderr)
+}
+
+// threadReport は1つのスレッドの親メッセージと返信を分析する
+func threadReport(ctx context.Context, a *app, channelID, threadTS string, opts *analysisOptions) (*analysisReport, error) {
+	result, err := a.analyzer.AnalyzeThread(ctx, channelID, threadTS, opts.dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	title := "スレッドの分析結果"
+	return &analysisReport{
+		title: title,
+		write: func(w io.Writer, text report.TextOptions) error {
+			return report.WriteThreadText(w, result, opts.limits, text)
+		},
+		blocks: func(link report.MessageLinker) []slack.Block {
+			return report.ThreadBlocks(title, result, opts.limits, link)
+		},
+		warnings: result.Warnings,
+	}, nil
+}
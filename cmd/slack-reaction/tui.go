@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/tui"
+)
+
+// runTUI は tui コマンドを実行する
+func runTUI(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("tui", "[オプション] <チャンネル名> | -user <ユーザー名>", stderr)
+	flags := addAnalysisFlags(fs)
+	var userName string
+	fs.StringVar(&userName, "user", "", "チャンネルの代わりにユーザーを分析する")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	opts, err := flags.resolve(report.DefaultChannelLimits)
+	if err != nil {
+		return err
+	}
+	if opts.post != "" {
+		return newUsageError("tui コマンドでは -post は使用できません")
+	}
+	channelName, userName, err := selectTarget(positional, userName, opts)
+	if err != nil {
+		return err
+	}
+
+	// 分析に時間がかかるため、端末でない場合は先にエラーにする
+	out, ok := stdout.(*os.File)
+	if !ok || tui.CheckTerminal(os.Stdin, out) != nil {
+		return newUsageError("%v", tui.ErrNotTerminal)
+	}
+
+	a, err := newAnalysisApp(ctx, opts, stderr)
+	if err != nil {
+		return err
+	}
+
+	// 初回の分析は画面を切り替える前に行い、進捗を標準エラー出力に表示する
+	a.analyzer.SetProgressOutput(stderr)
+	a.messageRepo.SetProgressOutput(stderr)
+
+	var source tui.Source
+	if userName != "" {
+		source = func(ctx context.Context, dateRange *domain.DateRange) (*tui.Dataset, error) {
+			result, err := a.analyzer.AnalyzeUser(ctx, userName, dateRange)
+			if err != nil {
+				return nil, err
+			}
+			return tui.FromUserAnalysisResult(result), nil
+		}
+	} else {
+		channel, err := a.channelRepo.FindByName(ctx, channelName)
+		if err != nil {
+			return err
+		}
+		title := "#" + channel.Name
+		source = func(ctx context.Context, dateRange *domain.DateRange) (*tui.Dataset, error) {
+			result, err := a.analyzer.AnalyzeChannel(ctx, channel.ID, dateRange)
+			if err != nil {
+				return nil, err
+			}
+			return tui.FromAnalysisResult(title, result), nil
+		}
+	}
+
+	data, err := source(ctx, opts.dateRange)
+	if err != nil {
+		return err
+	}
+
+	// 画面表示中の再分析では進捗を表示しない
+	a.analyzer.SetProgressOutput(io.Discard)
+	a.messageRepo.SetProgressOutput(io.Discard)
+
+	err = tui.Run(ctx, os.Stdin, out, source, data, opts.dateRange)
+	if err != nil && !errors.Is(err, context.Canceled) {
+		return fmt.Errorf("TUIエラー: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/store"
+	"github.com/slack-go/slack"
+)
+
+// runUser は user コマンドを実行する
+func runUser(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("user", "[オプション] <ユーザー名>", stderr)
+	flags := addAnalysisFlags(fs)
+	flags.allowFormats(report.FormatJSON, report.FormatCSV, report.FormatMarkdown, report.FormatHTML)
+	flags.allowExcludeChannels()
+	flags.allowTemplate()
+	flags.allowOutput()
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	opts, err := flags.resolve(report.DefaultUserLimits)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 && opts.user != "" {
+		positional = []string{opts.user}
+	}
+	if len(positional) != 1 {
+		return newUsageError("ユーザー名を1つ指定してください")
+	}
+
+	return analyzeUser(ctx, positional[0], opts, stdout, stderr)
+}
+
+// analyzeUser はユーザーを分析して結果を出力する
+func analyzeUser(ctx context.Context, name string, opts *analysisOptions, stdout, stderr io.Writer) error {
+	a, err := newAnalysisApp(ctx, opts, stderr)
+	if err != nil {
+		return err
+	}
+	rep, err := userReport(ctx, a, name, opts)
+	if err != nil {
+		return err
+	}
+	return writeReport(ctx, a, opts, rep, stdout, stderr)
+}
+
+// userReport はユーザーを分析する
+func userReport(ctx context.Context, a *app, name string, opts *analysisOptions) (*analysisReport, error) {
+	if err := excludeChannels(ctx, a, opts.excludeChannels); err != nil {
+		return nil, err
+	}
+	result, err := a.analyzer.AnalyzeUser(ctx, name, opts.dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	title := result.UserName + " のユーザー分析結果"
+	return &analysisReport{
+		title: title,
+		json: func(w io.Writer, meta report.Metadata) error {
+			meta.User = &report.Target{ID: result.UserID, Name: result.UserName}
+			return report.WriteUserJSON(w, meta, result, opts.limits)
+		},
+		csv: func() []report.CSVSection {
+			return report.UserCSVSections(result, opts.limits)
+		},
+		markdown: func(w io.Writer, md report.MarkdownOptions) error {
+			return report.WriteUserMarkdown(w, opts.dateRange, result, opts.limits, md)
+		},
+		html: func(w io.Writer, meta report.Metadata, link report.MessageLinker) error {
+			meta.User = &report.Target{ID: result.UserID, Name: result.UserName}
+			return report.WriteUserHTML(w, meta, result, link)
+		},
+		template: func(meta report.Metadata) *report.TemplateData {
+			meta.User = &report.Target{ID: result.UserID, Name: result.UserName}
+			return report.NewUserTemplateData(meta, title, result, opts.limits)
+		},
+		dataset: func() *store.Dataset {
+			return &store.Dataset{Messages: result.Messages}
+		},
+		blocks: func(link report.MessageLinker) []slack.Block {
+			return report.UserBlocks(opts.dateRange, result, opts.limits, link)
+		},
+		warnings: result.Warnings,
+	}, nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Tattsum/slack-reaction/internal/chart"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/Tattsum/slack-reaction/internal/store"
+	"github.com/slack-go/slack"
+)
+
+// runWorkspace は workspace コマンドを実行する
+func runWorkspace(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	fs := newFlagSet("workspace", "[オプション]", stderr)
+	flags := addAnalysisFlags(fs)
+	flags.allowFormats(report.FormatJSON, report.FormatCSV, report.FormatMarkdown, report.FormatHTML)
+	flags.allowExcludeChannels()
+	flags.allowTemplate()
+	flags.allowImages()
+	flags.allowOutput()
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) > 0 {
+		return newUsageError("不明な引数です: %v", positional)
+	}
+	opts, err := flags.resolve(report.DefaultWorkspaceLimits)
+	if err != nil {
+		return err
+	}
+
+	a, err := newAnalysisApp(ctx, opts, stderr)
+	if err != nil {
+		return err
+	}
+	rep, err := workspaceReport(ctx, a, opts, stderr)
+	if err != nil {
+		return err
+	}
+	return writeReport(ctx, a, opts, rep, stdout, stderr)
+}
+
+// workspaceReport は参加している全チャンネルを横断して分析する
+// 参加していないため対象外としたチャンネル数は stderr に表示する
+func workspaceReport(ctx context.Context, a *app, opts *analysisOptions, stderr io.Writer) (*analysisReport, error) {
+	channels, skipped, err := service.WorkspaceChannels(ctx, a.channelRepo)
+	if err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		fmt.Fprintf(stderr, "参加していない%dチャンネルは対象外です\n", skipped)
+	}
+	channels = service.ExcludeChannels(channels, opts.excludeChannels)
+	if len(channels) == 0 {
+		return nil, newUsageError("除外設定により分析対象のチャンネルがなくなりました")
+	}
+
+	result, err := a.analyzer.AnalyzeChannels(ctx, channels, opts.dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	title := fmt.Sprintf("ワークスペース全体のランキング（%dチャンネル）", len(result.Channels))
+	return &analysisReport{
+		title: title,
+		json: func(w io.Writer, meta report.Metadata) error {
+			return report.WriteMultiChannelJSON(w, meta, result, opts.limits)
+		},
+		csv: func() []report.CSVSection {
+			return report.MultiChannelCSVSections(result, opts.limits)
+		},
+		markdown: func(w io.Writer, md report.MarkdownOptions) error {
+			return report.WriteMultiChannelMarkdown(w, title, opts.dateRange, result, opts.limits, md)
+		},
+		html: func(w io.Writer, meta report.Metadata, link report.MessageLinker) error {
+			return report.WriteMultiChannelHTML(w, title, meta, result, link)
+		},
+		template: func(meta report.Metadata) *report.TemplateData {
+			return report.NewWorkspaceTemplateData(meta, title, result, opts.limits)
+		},
+		charts: func() []*chart.Chart {
+			return chart.FromAnalysisResult(result.Merged, opts.dateRange, opts.limits)
+		},
+		dataset: func() *store.Dataset {
+			return multiChannelDataset(result)
+		},
+		blocks: func(link report.MessageLinker) []slack.Block {
+			return report.MultiChannelBlocks(title, opts.dateRange, result, opts.limits, link)
+		},
+		warnings: result.Merged.Warnings,
+	}, nil
+}
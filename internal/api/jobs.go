@@ -0,0 +1,206 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// jobRetention は完了したジョブを保持する期間
+const jobRetention = time.Hour
+
+// maxRetainedJobs は保持する完了したジョブの最大数（超えた場合は古いものから削除する）
+const maxRetainedJobs = 1000
+
+// errQueueFull は実行待ちの分析が上限に達しているため、分析を受け付けられない場合のエラー
+var errQueueFull = errors.New("実行待ちの分析が上限に達しています。しばらくしてから再度リクエストしてください")
+
+// JobStatus はジョブの状態
+type JobStatus string
+
+const (
+	JobQueued    JobStatus = "queued"    // 実行待ち
+	JobRunning   JobStatus = "running"   // 実行中
+	JobSucceeded JobStatus = "succeeded" // 完了
+	JobFailed    JobStatus = "failed"    // 失敗
+)
+
+// Job は非同期で実行する分析ジョブ
+type Job struct {
+	ID         string     `json:"id"`
+	Kind       string     `json:"kind"`   // channel_analysis または user_analysis
+	Target     string     `json:"target"` // 分析対象のチャンネル名またはユーザー名
+	Status     JobStatus  `json:"status"`
+	CreatedAt  time.Time  `json:"created_at"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Error      string     `json:"error,omitempty"`
+	Result     any        `json:"result,omitempty"` // 完了時の分析結果（report.ChannelDocument または report.UserDocument）
+}
+
+// jobStore はジョブをメモリ上で管理し、同時に実行する分析の数と実行待ちの分析の数を制限する
+// 同期実行の分析も、ジョブと同じ上限で実行する
+type jobStore struct {
+	mu         sync.Mutex
+	jobs       map[string]*Job
+	pending    int // 実行待ちと実行中の分析の数
+	maxPending int
+	semaphore  chan struct{}
+	ctx        context.Context // ジョブの実行に使用するコンテキスト（サーバー停止時にキャンセルされる）
+	wg         sync.WaitGroup
+	now        func() time.Time
+}
+
+// newJobStore はジョブストアを作成する
+// 同時に最大 maxConcurrent 件を実行し、さらに最大 maxQueued 件を実行待ちにする
+func newJobStore(ctx context.Context, maxConcurrent, maxQueued int) *jobStore {
+	maxConcurrent = max(maxConcurrent, 1)
+	return &jobStore{
+		jobs:       make(map[string]*Job),
+		maxPending: maxConcurrent + max(maxQueued, 0),
+		semaphore:  make(chan struct{}, maxConcurrent),
+		ctx:        ctx,
+		now:        time.Now,
+	}
+}
+
+// submit はジョブを登録してバックグラウンドで実行し、登録時点のジョブの状態を返す
+// 実行待ちのジョブが上限に達している場合は errQueueFull を返す
+func (s *jobStore) submit(kind, target string, run func(ctx context.Context) (any, error)) (Job, error) {
+	s.mu.Lock()
+	s.prune()
+	if s.pending >= s.maxPending {
+		s.mu.Unlock()
+		return Job{}, errQueueFull
+	}
+	s.pending++
+	job := &Job{
+		ID:        newJobID(),
+		Kind:      kind,
+		Target:    target,
+		Status:    JobQueued,
+		CreatedAt: s.now(),
+	}
+	s.jobs[job.ID] = job
+	snapshot := *job
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		select {
+		case s.semaphore <- struct{}{}:
+			defer func() { <-s.semaphore }()
+		case <-s.ctx.Done():
+			s.finish(job.ID, nil, s.ctx.Err())
+			return
+		}
+
+		s.mu.Lock()
+		started := s.now()
+		job.Status = JobRunning
+		job.StartedAt = &started
+		s.mu.Unlock()
+
+		result, err := run(s.ctx)
+		s.finish(job.ID, result, err)
+	}()
+	return snapshot, nil
+}
+
+// run は分析をジョブとして記録せずに、ジョブと同じ上限で同期的に実行する
+// 実行待ちの分析が上限に達している場合は errQueueFull を返す
+func (s *jobStore) run(ctx context.Context, run func(ctx context.Context) (any, error)) (any, error) {
+	s.mu.Lock()
+	if s.pending >= s.maxPending {
+		s.mu.Unlock()
+		return nil, errQueueFull
+	}
+	s.pending++
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.pending--
+		s.mu.Unlock()
+	}()
+
+	select {
+	case s.semaphore <- struct{}{}:
+		defer func() { <-s.semaphore }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return run(ctx)
+}
+
+// finish はジョブの結果を記録する
+func (s *jobStore) finish(id string, result any, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending--
+	job, ok := s.jobs[id]
+	if !ok {
+		return
+	}
+	finished := s.now()
+	job.FinishedAt = &finished
+	if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+		return
+	}
+	job.Status = JobSucceeded
+	job.Result = result
+}
+
+// get はジョブの現在の状態を返す
+func (s *jobStore) get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// wait は実行中のジョブがすべて終了するまで待つ
+func (s *jobStore) wait() {
+	s.wg.Wait()
+}
+
+// prune は保持期間を過ぎた完了済みのジョブと、最大数を超えた古い完了済みのジョブを削除する（呼び出し元でロックを取得すること）
+func (s *jobStore) prune() {
+	threshold := s.now().Add(-jobRetention)
+	var finished []*Job
+	for id, job := range s.jobs {
+		if job.FinishedAt == nil {
+			continue
+		}
+		if job.FinishedAt.Before(threshold) {
+			delete(s.jobs, id)
+			continue
+		}
+		finished = append(finished, job)
+	}
+	if len(finished) <= maxRetainedJobs {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].FinishedAt.Before(*finished[j].FinishedAt)
+	})
+	for _, job := range finished[:len(finished)-maxRetainedJobs] {
+		delete(s.jobs, job.ID)
+	}
+}
+
+// newJobID はジョブIDとして使用するランダムな文字列を作成する
+func newJobID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
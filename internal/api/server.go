@@ -0,0 +1,280 @@
+// Package api は分析機能をJSON形式のREST APIとして提供する
+package api
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// defaultLimit は各ランキングのデフォルトの件数
+const defaultLimit = 10
+
+// maxLimit は各ランキングで指定できる最大の件数
+const maxLimit = 1000
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// Server は分析APIのHTTPハンドラー
+type Server struct {
+	analyzer    *service.Analyzer
+	channelRepo domain.ChannelRepository
+	jobs        *jobStore
+	mux         *http.ServeMux
+	now         func() time.Time
+	toolVersion string // レスポンスのメタデータに含めるツールのバージョン
+}
+
+// NewServer は分析APIのサーバーを作成する
+// 非同期ジョブは ctx がキャンセルされると中断される。分析は同期実行と非同期ジョブを合わせて同時に最大 maxJobs 件まで実行される
+// 実行待ちの分析が maxQueued 件に達している場合、分析のリクエストは 503 Service Unavailable を返す
+func NewServer(ctx context.Context, analyzer *service.Analyzer, channelRepo domain.ChannelRepository, maxJobs, maxQueued int) *Server {
+	s := &Server{
+		analyzer:    analyzer,
+		channelRepo: channelRepo,
+		jobs:        newJobStore(ctx, maxJobs, maxQueued),
+		mux:         http.NewServeMux(),
+		now:         time.Now,
+		toolVersion: "dev",
+	}
+	s.mux.HandleFunc("GET /channels/{name}/analysis", s.handleChannelAnalysis)
+	s.mux.HandleFunc("POST /channels/{name}/analysis", s.handleChannelAnalysisJob)
+	s.mux.HandleFunc("GET /users/{name}/analysis", s.handleUserAnalysis)
+	s.mux.HandleFunc("POST /users/{name}/analysis", s.handleUserAnalysisJob)
+	s.mux.HandleFunc("GET /jobs/{id}", s.handleJob)
+	s.mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	return s
+}
+
+// ServeHTTP はリクエストを各エンドポイントに振り分ける
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// SetToolVersion はレスポンスのメタデータに含めるツールのバージョンを設定する（デフォルトは "dev"）
+func (s *Server) SetToolVersion(version string) {
+	s.toolVersion = version
+}
+
+// Wait は実行中の非同期ジョブがすべて終了するまで待つ
+func (s *Server) Wait() {
+	s.jobs.wait()
+}
+
+// analysisQuery は分析エンドポイントのクエリパラメータ
+type analysisQuery struct {
+	dateRange *domain.DateRange
+	limit     int
+}
+
+// parseAnalysisQuery はクエリパラメータ（start, end, period, limit）を解析する
+func (s *Server) parseAnalysisQuery(r *http.Request) (*analysisQuery, error) {
+	q := r.URL.Query()
+	query := &analysisQuery{limit: defaultLimit}
+
+	start, end, period := q.Get("start"), q.Get("end"), q.Get("period")
+	var err error
+	switch {
+	case period != "" && (start != "" || end != ""):
+		return nil, errors.New("period と start/end は同時に指定できません")
+	case period != "":
+		query.dateRange, err = domain.ParseRelativeDateRange(period, s.now())
+	default:
+		query.dateRange, err = domain.ParseDateRange(start, end, time.Local)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxLimit {
+			return nil, errors.New("limit には1から" + strconv.Itoa(maxLimit) + "までの整数を指定してください")
+		}
+		query.limit = limit
+	}
+	return query, nil
+}
+
+// limits は全てのランキングを limit 件までとする表示件数を返す
+func (q *analysisQuery) limits() report.Limits {
+	return report.Limits{Emoji: q.limit, Message: q.limit, User: q.limit, Thread: q.limit, Channel: q.limit}
+}
+
+// analyzeChannel はチャンネルを分析してレスポンスを作成する（形式は -format json と同じ）
+func (s *Server) analyzeChannel(ctx context.Context, name string, query *analysisQuery) (*report.ChannelDocument, error) {
+	channel, err := s.channelRepo.FindByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.analyzer.AnalyzeChannel(ctx, channel.ID, query.dateRange)
+	if err != nil {
+		return nil, err
+	}
+	meta := report.NewMetadata("channel", s.toolVersion, query.dateRange, s.now())
+	meta.Channel = &report.Target{ID: channel.ID, Name: channel.Name}
+	return report.NewChannelDocument(meta, result, query.limits()), nil
+}
+
+// analyzeUser はユーザーを分析してレスポンスを作成する（形式は -format json と同じ）
+func (s *Server) analyzeUser(ctx context.Context, name string, query *analysisQuery) (*report.UserDocument, error) {
+	result, err := s.analyzer.AnalyzeUser(ctx, name, query.dateRange)
+	if err != nil {
+		return nil, err
+	}
+	meta := report.NewMetadata("user", s.toolVersion, query.dateRange, s.now())
+	meta.User = &report.Target{ID: result.UserID, Name: result.UserName}
+	return report.NewUserDocument(meta, result, query.limits()), nil
+}
+
+// handleChannelAnalysis はチャンネルを同期的に分析する
+func (s *Server) handleChannelAnalysis(w http.ResponseWriter, r *http.Request) {
+	query, err := s.parseAnalysisQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	name := r.PathValue("name")
+	s.runAnalysis(w, r, func(ctx context.Context) (any, error) {
+		return s.analyzeChannel(ctx, name, query)
+	})
+}
+
+// handleChannelAnalysisJob はチャンネル分析のジョブを登録する
+func (s *Server) handleChannelAnalysisJob(w http.ResponseWriter, r *http.Request) {
+	query, err := s.parseAnalysisQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	name := r.PathValue("name")
+	job, err := s.jobs.submit("channel_analysis", name, func(ctx context.Context) (any, error) {
+		return s.analyzeChannel(ctx, name, query)
+	})
+	if err != nil {
+		writeQueueFull(w, err)
+		return
+	}
+	writeJobAccepted(w, job)
+}
+
+// handleUserAnalysis はユーザーを同期的に分析する
+func (s *Server) handleUserAnalysis(w http.ResponseWriter, r *http.Request) {
+	query, err := s.parseAnalysisQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	name := r.PathValue("name")
+	s.runAnalysis(w, r, func(ctx context.Context) (any, error) {
+		return s.analyzeUser(ctx, name, query)
+	})
+}
+
+// runAnalysis は非同期ジョブと同じ上限で分析を同期的に実行し、結果を返す
+// 実行待ちの分析が上限に達している場合は 503 Service Unavailable を返す
+func (s *Server) runAnalysis(w http.ResponseWriter, r *http.Request, run func(ctx context.Context) (any, error)) {
+	res, err := s.jobs.run(r.Context(), run)
+	switch {
+	case errors.Is(err, errQueueFull):
+		writeQueueFull(w, err)
+	case err != nil:
+		writeError(w, statusFor(err), err)
+	default:
+		writeJSON(w, http.StatusOK, res)
+	}
+}
+
+// handleUserAnalysisJob はユーザー分析のジョブを登録する
+func (s *Server) handleUserAnalysisJob(w http.ResponseWriter, r *http.Request) {
+	query, err := s.parseAnalysisQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	name := r.PathValue("name")
+	job, err := s.jobs.submit("user_analysis", name, func(ctx context.Context) (any, error) {
+		return s.analyzeUser(ctx, name, query)
+	})
+	if err != nil {
+		writeQueueFull(w, err)
+		return
+	}
+	writeJobAccepted(w, job)
+}
+
+// handleJob はジョブの状態を返す
+func (s *Server) handleJob(w http.ResponseWriter, r *http.Request) {
+	job, ok := s.jobs.get(r.PathValue("id"))
+	if !ok {
+		writeError(w, http.StatusNotFound, errors.New("ジョブが見つかりません"))
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// handleOpenAPI はAPIのOpenAPI定義を返す
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
+
+// handleHealth は死活監視用のエンドポイント
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// statusFor は分析時のエラーに対応するHTTPステータスを返す
+func statusFor(err error) int {
+	var notFound *domain.NotFoundError
+	switch {
+	case errors.As(err, &notFound):
+		return http.StatusNotFound
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusBadGateway // Slack APIの呼び出しに失敗した場合
+	}
+}
+
+// writeJobAccepted はジョブを受け付けたことを 202 Accepted で返す
+func writeJobAccepted(w http.ResponseWriter, job Job) {
+	w.Header().Set("Location", "/jobs/"+job.ID)
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+// queueFullRetryAfter は実行待ちの分析が上限に達している場合に、再度リクエストするまでに待つよう返す秒数
+const queueFullRetryAfter = "30"
+
+// writeQueueFull は実行待ちの分析が上限に達していることを 503 Service Unavailable で返す
+func writeQueueFull(w http.ResponseWriter, err error) {
+	w.Header().Set("Retry-After", queueFullRetryAfter)
+	writeError(w, http.StatusServiceUnavailable, err)
+}
+
+// errorResponse はエラー時のレスポンス
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError はエラーをJSONで返す
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeJSON は値をJSONで返す
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
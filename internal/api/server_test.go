@@ -0,0 +1,326 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+type fakeChannelRepository struct {
+	channels []*domain.Channel
+}
+
+func (r *fakeChannelRepository) FindByName(ctx context.Context, name string) (*domain.Channel, error) {
+	for _, ch := range r.channels {
+		if ch.Name == name {
+			return ch, nil
+		}
+	}
+	return nil, &domain.NotFoundError{Kind: "チャンネル", Name: name}
+}
+
+func (r *fakeChannelRepository) FindAll(ctx context.Context) ([]*domain.Channel, error) {
+	return r.channels, nil
+}
+
+type fakeMessageRepository struct {
+	messages []*domain.Message
+	err      error
+	block    chan struct{} // 指定された場合、閉じられるまで FindByChannel を待機させる
+}
+
+func (r *fakeMessageRepository) FindByChannel(ctx context.Context, channelID string, dateRange *domain.DateRange) ([]*domain.Message, error) {
+	if r.block != nil {
+		<-r.block
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	var messages []*domain.Message
+	for _, msg := range r.messages {
+		if msg.ChannelID == channelID && dateRange.Contains(msg.Timestamp) {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+func (r *fakeMessageRepository) FindThreadReplies(ctx context.Context, channelID, threadTS string, dateRange *domain.DateRange) ([]*domain.Message, error) {
+	return nil, nil
+}
+
+func (r *fakeMessageRepository) FindThread(ctx context.Context, channelID, threadTS string, dateRange *domain.DateRange) (*domain.Message, []*domain.Message, error) {
+	return nil, nil, &domain.NotFoundError{Kind: "スレッドの親メッセージ", Name: threadTS}
+}
+
+func (r *fakeMessageRepository) FindByUser(ctx context.Context, userID string, dateRange *domain.DateRange) ([]*domain.Message, error) {
+	var messages []*domain.Message
+	for _, msg := range r.messages {
+		if msg.UserID == userID {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+func (r *fakeMessageRepository) FindBySearch(ctx context.Context, query string, dateRange *domain.DateRange, excludedChannels map[string]bool) ([]*domain.Message, []string, error) {
+	return nil, nil, nil
+}
+
+type fakeUserRepository struct {
+	users map[string]*domain.User
+}
+
+func (r *fakeUserRepository) FindByIDs(ctx context.Context, userIDs []string) (map[string]*domain.User, error) {
+	return r.users, nil
+}
+
+func (r *fakeUserRepository) FindAll(ctx context.Context) (map[string]*domain.User, error) {
+	return r.users, nil
+}
+
+func (r *fakeUserRepository) FindByName(ctx context.Context, name string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Name == name {
+			return user, nil
+		}
+	}
+	return nil, &domain.NotFoundError{Kind: "ユーザー", Name: name}
+}
+
+func newTestServer(t *testing.T, messageRepo *fakeMessageRepository) *Server {
+	t.Helper()
+	userRepo := &fakeUserRepository{users: map[string]*domain.User{
+		"U1": {ID: "U1", Name: "taro", DisplayName: "田中太郎"},
+	}}
+	analyzer := service.NewAnalyzer(messageRepo, userRepo)
+	analyzer.SetProgressOutput(io.Discard)
+	channelRepo := &fakeChannelRepository{channels: []*domain.Channel{{ID: "C1", Name: "general"}}}
+	s := NewServer(context.Background(), analyzer, channelRepo, 1, 1)
+	t.Cleanup(s.Wait)
+	return s
+}
+
+func testMessages() []*domain.Message {
+	return []*domain.Message{
+		{ID: "1.000", ChannelID: "C1", UserID: "U1", Text: "こんにちは", Timestamp: time.Date(2024, 1, 10, 10, 0, 0, 0, time.Local),
+			Reactions: []domain.Reaction{{Name: "+1", Count: 3}}},
+		{ID: "2.000", ChannelID: "C1", UserID: "U1", Text: "範囲外", Timestamp: time.Date(2024, 3, 10, 10, 0, 0, 0, time.Local),
+			Reactions: []domain.Reaction{{Name: "eyes", Count: 1}}},
+	}
+}
+
+func doRequest(t *testing.T, s *Server, method, target string, v any) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("invalid JSON response: %v\n%s", err, rec.Body.String())
+		}
+	}
+	return rec
+}
+
+func TestServer_ChannelAnalysis(t *testing.T) {
+	s := newTestServer(t, &fakeMessageRepository{messages: testMessages()})
+
+	var res report.ChannelDocument
+	rec := doRequest(t, s, http.MethodGet, "/channels/general/analysis?start=2024-01-01&end=2024-01-31", &res)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200\n%s", rec.Code, rec.Body.String())
+	}
+	meta := res.Metadata
+	if meta.SchemaVersion != report.SchemaVersion || meta.Command != "channel" || meta.Channel == nil || meta.Channel.ID != "C1" {
+		t.Errorf("Metadata = %+v, want channel C1", meta)
+	}
+	if meta.Period.Start == nil || meta.Period.End == nil {
+		t.Errorf("Period = %+v, want start and end", meta.Period)
+	}
+	if res.TotalMessages != 1 || res.TotalReactions != 3 {
+		t.Errorf("response = %+v, want 1 message and 3 reactions", res)
+	}
+	if len(res.Emoji) != 1 || res.Emoji[0].Emoji != "+1" {
+		t.Errorf("Emoji = %+v, want +1 only", res.Emoji)
+	}
+	if len(res.Messages) != 1 || res.Messages[0].MessageID != "1.000" {
+		t.Errorf("Messages = %+v, want 1.000", res.Messages)
+	}
+	if len(res.Users) != 1 || res.Users[0].UserName != "田中太郎" {
+		t.Errorf("Users = %+v, want 田中太郎", res.Users)
+	}
+	if res.Warnings == nil {
+		t.Error("Warnings should be an empty array, not null")
+	}
+}
+
+func TestServer_UserAnalysis(t *testing.T) {
+	s := newTestServer(t, &fakeMessageRepository{messages: testMessages()})
+	s.SetToolVersion("v1.2.3")
+
+	var res report.UserDocument
+	rec := doRequest(t, s, http.MethodGet, "/users/taro/analysis?limit=1", &res)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200\n%s", rec.Code, rec.Body.String())
+	}
+	meta := res.Metadata
+	if meta.ToolVersion != "v1.2.3" || meta.Command != "user" || meta.User == nil || meta.User.ID != "U1" {
+		t.Errorf("Metadata = %+v, want user U1", meta)
+	}
+	if res.TotalMessages != 2 || len(res.Emoji) != 1 {
+		t.Errorf("response = %+v, want 2 messages and 1 emoji", res)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		repoErr    error
+		wantStatus int
+	}{
+		{name: "日付形式が不正", target: "/channels/general/analysis?start=2024/01/01", wantStatus: http.StatusBadRequest},
+		{name: "periodとstartを併用", target: "/channels/general/analysis?period=7d&start=2024-01-01", wantStatus: http.StatusBadRequest},
+		{name: "limitが不正", target: "/channels/general/analysis?limit=0", wantStatus: http.StatusBadRequest},
+		{name: "存在しないチャンネル", target: "/channels/random/analysis", wantStatus: http.StatusNotFound},
+		{name: "存在しないユーザー", target: "/users/hanako/analysis", wantStatus: http.StatusNotFound},
+		{name: "Slack APIのエラー", target: "/channels/general/analysis", repoErr: errors.New("ratelimited"), wantStatus: http.StatusBadGateway},
+		{name: "存在しないジョブ", target: "/jobs/unknown", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, &fakeMessageRepository{messages: testMessages(), err: tt.repoErr})
+			var res errorResponse
+			rec := doRequest(t, s, http.MethodGet, tt.target, &res)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if res.Error == "" {
+				t.Error("error message should not be empty")
+			}
+		})
+	}
+}
+
+func TestServer_AnalysisJob(t *testing.T) {
+	block := make(chan struct{})
+	s := newTestServer(t, &fakeMessageRepository{messages: testMessages(), block: block})
+
+	var job Job
+	rec := doRequest(t, s, http.MethodPost, "/channels/general/analysis?period=1m", &job)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want 202\n%s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Location"); got != "/jobs/"+job.ID {
+		t.Errorf("Location = %q, want /jobs/%s", got, job.ID)
+	}
+	if job.Status != JobQueued {
+		t.Errorf("Status = %q, want queued", job.Status)
+	}
+
+	doRequest(t, s, http.MethodGet, "/jobs/"+job.ID, &job)
+	if job.Status == JobSucceeded || job.Status == JobFailed {
+		t.Errorf("job should not finish while the repository is blocked, got %q", job.Status)
+	}
+
+	close(block)
+	s.Wait()
+
+	var done struct {
+		Job
+		Result report.ChannelDocument `json:"result"`
+	}
+	rec = doRequest(t, s, http.MethodGet, "/jobs/"+job.ID, &done)
+	if rec.Code != http.StatusOK || done.Status != JobSucceeded {
+		t.Fatalf("status = %d, job = %+v, want succeeded", rec.Code, done.Job)
+	}
+	if done.FinishedAt == nil || done.Result.Metadata.Channel == nil || done.Result.Metadata.Channel.Name != "general" {
+		t.Errorf("finished job = %+v, want result for general", done)
+	}
+}
+
+// TestServer_AnalysisJob_QueueFull は実行待ちのジョブが上限に達している場合にジョブを登録せず 503 を返すことを確認する
+func TestServer_AnalysisJob_QueueFull(t *testing.T) {
+	block := make(chan struct{})
+	s := newTestServer(t, &fakeMessageRepository{messages: testMessages(), block: block})
+	defer close(block)
+
+	// 実行中の1件と実行待ちの1件で上限に達する
+	for range 2 {
+		if rec := doRequest(t, s, http.MethodPost, "/channels/general/analysis", nil); rec.Code != http.StatusAccepted {
+			t.Fatalf("status = %d, want 202\n%s", rec.Code, rec.Body.String())
+		}
+	}
+
+	var res errorResponse
+	rec := doRequest(t, s, http.MethodPost, "/users/taro/analysis", &res)
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") == "" || res.Error == "" {
+		t.Errorf("Retry-After = %q, error = %q, want both set", rec.Header().Get("Retry-After"), res.Error)
+	}
+}
+
+// TestServer_ChannelAnalysis_QueueFull は同期実行の分析も非同期ジョブと同じ上限に従い、上限に達している場合は 503 を返すことを確認する
+func TestServer_ChannelAnalysis_QueueFull(t *testing.T) {
+	block := make(chan struct{})
+	s := newTestServer(t, &fakeMessageRepository{messages: testMessages(), block: block})
+	defer close(block)
+
+	// 実行中の1件と実行待ちの1件で上限に達する
+	for range 2 {
+		if rec := doRequest(t, s, http.MethodPost, "/channels/general/analysis", nil); rec.Code != http.StatusAccepted {
+			t.Fatalf("status = %d, want 202\n%s", rec.Code, rec.Body.String())
+		}
+	}
+
+	for _, target := range []string{"/channels/general/analysis", "/users/taro/analysis"} {
+		var res errorResponse
+		rec := doRequest(t, s, http.MethodGet, target, &res)
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("GET %s status = %d, want 503", target, rec.Code)
+		}
+		if rec.Header().Get("Retry-After") == "" || res.Error == "" {
+			t.Errorf("GET %s Retry-After = %q, error = %q, want both set", target, rec.Header().Get("Retry-After"), res.Error)
+		}
+	}
+}
+
+func TestServer_OpenAPI(t *testing.T) {
+	s := newTestServer(t, &fakeMessageRepository{})
+
+	var spec struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	rec := doRequest(t, s, http.MethodGet, "/openapi.json", &spec)
+	if rec.Code != http.StatusOK || spec.OpenAPI == "" {
+		t.Fatalf("status = %d, openapi = %q", rec.Code, spec.OpenAPI)
+	}
+
+	// ルーティングされているエンドポイントが定義に含まれていることを確認する
+	for path, methods := range map[string][]string{
+		"/channels/{name}/analysis": {"get", "post"},
+		"/users/{name}/analysis":    {"get", "post"},
+		"/jobs/{id}":                {"get"},
+	} {
+		for _, method := range methods {
+			if _, ok := spec.Paths[path][method]; !ok {
+				t.Errorf("OpenAPI spec does not define %s %s", method, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+// Package chart は分析結果のグラフをPNG・SVGの画像として描画する
+// 外部のサービスやブラウザを使わずに描画し、同じ入力からは常に同じ画像を出力する
+package chart
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// 画像の大きさ（ピクセル）
+const (
+	Width  = 800
+	Height = 480
+)
+
+// 画像の形式
+const (
+	FormatPNG = "png"
+	FormatSVG = "svg"
+)
+
+// Formats は対応している画像の形式
+var Formats = []string{FormatPNG, FormatSVG}
+
+// OtherLabel は円グラフで上位以外をまとめた項目の名前
+const OtherLabel = "その他"
+
+// maxPieSlices は円グラフの扇形の最大数（OtherLabel を含む）
+const maxPieSlices = 10
+
+// Kind はグラフの種類
+type Kind int
+
+const (
+	KindBar  Kind = iota // 横棒グラフ（ランキング）
+	KindPie              // 円グラフ（割合）
+	KindLine             // 折れ線グラフ（推移）
+)
+
+// Chart は画像として描画するグラフ
+type Chart struct {
+	Name   string   // ファイル名に使用する名前（emoji / posters / activity）
+	Kind   Kind     // グラフの種類
+	Title  string   // グラフのタイトル
+	Alt    string   // 英数字のみのタイトル（組み込みのフォントで描画する場合とSlackの代替テキストに使用する）
+	Labels []string // 項目（棒・扇形）または期間（折れ線）の名前
+	Values []int    // Labels と同じ順の値
+}
+
+// EmojiRanking はよく使われたスタンプの先頭n件の横棒グラフを作成する
+func EmojiRanking(stats []domain.EmojiCount, n int) *Chart {
+	c := &Chart{Name: "emoji", Kind: KindBar, Title: fmt.Sprintf("よく使われたスタンプ TOP%d", n), Alt: fmt.Sprintf("Top %d emoji", n)}
+	for _, stat := range stats[:min(max(n, 0), len(stats))] {
+		c.Labels = append(c.Labels, ":"+stat.Emoji+":")
+		c.Values = append(c.Values, stat.Count)
+	}
+	return c
+}
+
+// PosterShare は投稿数の多いユーザーの先頭n件が投稿全体に占める割合の円グラフを作成する
+// n件（最大9件）より後のユーザーは OtherLabel の1つの扇形にまとめる
+func PosterShare(stats []domain.UserStats, n int) *Chart {
+	c := &Chart{Name: "posters", Kind: KindPie, Title: "投稿者の割合", Alt: "Share of messages by poster"}
+	n = min(n, maxPieSlices-1)
+	other := 0
+	for i, stat := range stats {
+		if i >= n {
+			other += stat.Count
+			continue
+		}
+		c.Labels = append(c.Labels, stat.UserName)
+		c.Values = append(c.Values, stat.Count)
+	}
+	if other > 0 {
+		c.Labels = append(c.Labels, OtherLabel)
+		c.Values = append(c.Values, other)
+	}
+	return c
+}
+
+// Activity は投稿数の推移の折れ線グラフを作成する
+// 期間の単位は分析期間の長さから決める（31日までは日別）
+func Activity(messages []*domain.Message, dateRange *domain.DateRange) *Chart {
+	granularity, periods := service.ActivityTimeline(messages, dateRange)
+	c := &Chart{
+		Name: "activity", Kind: KindLine,
+		Title: "投稿数の推移（" + granularity.String() + "）", Alt: "Messages per " + granularityUnit(granularity),
+	}
+	for _, period := range periods {
+		c.Labels = append(c.Labels, periodLabel(period.Start, granularity))
+		c.Values = append(c.Values, period.Messages)
+	}
+	return c
+}
+
+// FromAnalysisResult はチャンネル分析の結果から、スタンプのランキング・投稿者の割合・投稿数の推移のグラフを作成する
+// データのないグラフは含めない
+func FromAnalysisResult(result *service.AnalysisResult, dateRange *domain.DateRange, limits report.Limits) []*Chart {
+	var charts []*Chart
+	for _, c := range []*Chart{
+		EmojiRanking(result.EmojiStats, limits.Emoji),
+		PosterShare(result.UserStats, limits.User),
+		Activity(result.Messages, dateRange),
+	} {
+		if len(c.Values) > 0 {
+			charts = append(charts, c)
+		}
+	}
+	return charts
+}
+
+// FileName は画像のファイル名（例: emoji.png）を返す
+func (c *Chart) FileName(format string) string {
+	return c.Name + "." + format
+}
+
+// Render はグラフを format の形式の画像として書き出す
+// font はPNGのラベルの描画に使用する（nilの場合は組み込みの英数字のフォント）
+func (c *Chart) Render(w io.Writer, format string, font *Font) error {
+	switch format {
+	case FormatPNG:
+		return WritePNG(w, c, font)
+	case FormatSVG:
+		return WriteSVG(w, c)
+	default:
+		return fmt.Errorf("画像の形式 '%s' には対応していません", format)
+	}
+}
+
+// granularityUnit は期間の単位の英語の名前を返す
+func granularityUnit(granularity service.Granularity) string {
+	switch granularity {
+	case service.GranularityHour:
+		return "hour"
+	case service.GranularityWeek:
+		return "week"
+	case service.GranularityMonth:
+		return "month"
+	default:
+		return "day"
+	}
+}
+
+// periodLabel は推移の期間を表す（時間別は日時、日別・週別は日付、月別は年月）
+func periodLabel(start time.Time, granularity service.Granularity) string {
+	switch granularity {
+	case service.GranularityHour:
+		return start.Format("01-02 15:04")
+	case service.GranularityMonth:
+		return start.Format("2006-01")
+	default:
+		return start.Format("2006-01-02")
+	}
+}
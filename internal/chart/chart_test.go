@@ -0,0 +1,174 @@
+package chart
+
+import (
+	"bytes"
+	"flag"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// update が指定された場合は、テストの出力で testdata のゴールデンファイルを更新する
+// go test ./internal/chart -update
+var update = flag.Bool("update", false, "testdata のゴールデンファイルを更新する")
+
+func testResult() *service.AnalysisResult {
+	day := func(d, h int) time.Time { return time.Date(2024, 1, d, h, 0, 0, 0, time.UTC) }
+	return &service.AnalysisResult{
+		EmojiStats: []domain.EmojiCount{
+			{Emoji: "+1", Count: 42},
+			{Emoji: "eyes", Count: 17},
+			{Emoji: "tada", Count: 9},
+		},
+		UserStats: []domain.UserStats{
+			{UserID: "U1", UserName: "taro", Count: 6},
+			{UserID: "U2", UserName: "hanako", Count: 3},
+			{UserID: "U3", UserName: "jiro", Count: 1},
+		},
+		Messages: []*domain.Message{
+			{UserID: "U1", Timestamp: day(1, 9)}, {UserID: "U1", Timestamp: day(1, 10)}, {UserID: "U2", Timestamp: day(1, 11)},
+			{UserID: "U1", Timestamp: day(3, 9)}, {UserID: "U3", Timestamp: day(4, 9)}, {UserID: "U1", Timestamp: day(5, 9)},
+			{UserID: "U2", Timestamp: day(5, 15)}, {UserID: "U1", Timestamp: day(6, 9)}, {UserID: "U2", Timestamp: day(7, 9)},
+			{UserID: "U1", Timestamp: day(7, 18)},
+		},
+	}
+}
+
+func testDateRange() *domain.DateRange {
+	return &domain.DateRange{
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 1, 7, 23, 59, 59, 0, time.UTC),
+	}
+}
+
+func TestFromAnalysisResult(t *testing.T) {
+	charts := FromAnalysisResult(testResult(), testDateRange(), report.Limits{Emoji: 2, User: 2})
+	if len(charts) != 3 {
+		t.Fatalf("len(charts) = %d, want 3", len(charts))
+	}
+
+	emoji := charts[0]
+	if emoji.Kind != KindBar || emoji.FileName(FormatPNG) != "emoji.png" || len(emoji.Values) != 2 || emoji.Labels[0] != ":+1:" {
+		t.Errorf("emoji chart = %+v", emoji)
+	}
+
+	posters := charts[1]
+	wantLabels := []string{"taro", "hanako", OtherLabel}
+	wantValues := []int{6, 3, 1}
+	if posters.Kind != KindPie || len(posters.Labels) != len(wantLabels) {
+		t.Fatalf("posters chart = %+v", posters)
+	}
+	for i := range wantLabels {
+		if posters.Labels[i] != wantLabels[i] || posters.Values[i] != wantValues[i] {
+			t.Errorf("posters[%d] = %s %d, want %s %d", i, posters.Labels[i], posters.Values[i], wantLabels[i], wantValues[i])
+		}
+	}
+
+	activity := charts[2]
+	wantCounts := []int{3, 0, 1, 1, 2, 1, 2}
+	if activity.Kind != KindLine || activity.Title != "投稿数の推移（日別）" || len(activity.Values) != len(wantCounts) {
+		t.Fatalf("activity chart = %+v", activity)
+	}
+	for i, want := range wantCounts {
+		if activity.Values[i] != want {
+			t.Errorf("activity.Values[%d] = %d, want %d", i, activity.Values[i], want)
+		}
+	}
+	if activity.Labels[0] != "2024-01-01" {
+		t.Errorf("activity.Labels[0] = %q, want 2024-01-01", activity.Labels[0])
+	}
+
+	if got := FromAnalysisResult(&service.AnalysisResult{}, nil, report.Limits{Emoji: 3, User: 3}); len(got) != 0 {
+		t.Errorf("FromAnalysisResult(empty) = %d charts, want 0", len(got))
+	}
+}
+
+// TestRender_Golden は同じ入力から常に testdata の画像と同じバイト列を出力することを確認する
+func TestRender_Golden(t *testing.T) {
+	for _, c := range FromAnalysisResult(testResult(), testDateRange(), report.Limits{Emoji: 3, User: 2}) {
+		for _, format := range Formats {
+			name := c.FileName(format)
+			t.Run(name, func(t *testing.T) {
+				var first, second bytes.Buffer
+				if err := c.Render(&first, format, nil); err != nil {
+					t.Fatalf("Render() error = %v", err)
+				}
+				if err := c.Render(&second, format, nil); err != nil {
+					t.Fatalf("Render() error = %v", err)
+				}
+				if !bytes.Equal(first.Bytes(), second.Bytes()) {
+					t.Fatal("Render() is not deterministic")
+				}
+
+				golden := filepath.Join("testdata", name)
+				if *update {
+					if err := os.WriteFile(golden, first.Bytes(), 0o644); err != nil {
+						t.Fatal(err)
+					}
+				}
+				want, err := os.ReadFile(golden)
+				if err != nil {
+					t.Fatalf("ゴールデンファイルがありません（go test ./internal/chart -update で作成）: %v", err)
+				}
+				if !bytes.Equal(first.Bytes(), want) {
+					t.Errorf("%s と出力が異なります", golden)
+				}
+			})
+		}
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	var buf bytes.Buffer
+	c := &Chart{Name: "emoji", Kind: KindBar, Title: "スタンプ", Labels: []string{":tada:"}, Values: []int{1}}
+	if err := WritePNG(&buf, c, nil); err != nil {
+		t.Fatalf("WritePNG() error = %v", err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != Width || b.Dy() != Height {
+		t.Errorf("size = %dx%d, want %dx%d", b.Dx(), b.Dy(), Width, Height)
+	}
+}
+
+func TestWriteSVG_Escape(t *testing.T) {
+	var buf bytes.Buffer
+	c := &Chart{Name: "posters", Kind: KindPie, Title: "<script>", Labels: []string{"田中 & 佐藤"}, Values: []int{1}}
+	if err := WriteSVG(&buf, c); err != nil {
+		t.Fatalf("WriteSVG() error = %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{"&lt;script&gt;", "田中 &amp; 佐藤  1 (100.0%)"} {
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Errorf("SVG does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestRender_UnsupportedFormat(t *testing.T) {
+	c := &Chart{Name: "emoji"}
+	if err := c.Render(&bytes.Buffer{}, "gif", nil); err == nil {
+		t.Error("Render() error = nil, want error")
+	}
+}
+
+func TestLoadFont_Error(t *testing.T) {
+	if _, err := LoadFont(filepath.Join(t.TempDir(), "missing.ttf")); err == nil {
+		t.Error("LoadFont() error = nil, want error for missing file")
+	}
+	path := filepath.Join(t.TempDir(), "invalid.ttf")
+	if err := os.WriteFile(path, []byte("not a font"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadFont(path); err == nil {
+		t.Error("LoadFont() error = nil, want error for invalid font")
+	}
+}
@@ -0,0 +1,227 @@
+package chart
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"github.com/Tattsum/slack-reaction/internal/textwidth"
+)
+
+// 描画に使用する色
+var (
+	backgroundColor = color.NRGBA{0xff, 0xff, 0xff, 0xff}
+	textColor       = color.NRGBA{0x33, 0x33, 0x33, 0xff}
+	mutedColor      = color.NRGBA{0x88, 0x88, 0x88, 0xff}
+	gridColor       = color.NRGBA{0xdd, 0xdd, 0xdd, 0xff}
+)
+
+// palette は棒・扇形・折れ線の色（項目の順に使用する）
+var palette = []color.NRGBA{
+	{0x4e, 0x79, 0xa7, 0xff}, {0xf2, 0x8e, 0x2b, 0xff}, {0xe1, 0x57, 0x59, 0xff}, {0x76, 0xb7, 0xb2, 0xff},
+	{0x59, 0xa1, 0x4f, 0xff}, {0xed, 0xc9, 0x48, 0xff}, {0xb0, 0x7a, 0xa1, 0xff}, {0xff, 0x9d, 0xa7, 0xff},
+	{0x9c, 0x75, 0x5f, 0xff}, {0xba, 0xb0, 0xac, 0xff},
+}
+
+// 文字の大きさ（ピクセル）
+const (
+	titleSize = 20
+	labelSize = 13
+)
+
+// maxLabelWidth はラベルの最大の表示幅（全角文字は2桁として数え、超える場合は切り詰める）
+const maxLabelWidth = 24
+
+// anchor は文字列の配置の基準
+type anchor int
+
+const (
+	anchorStart  anchor = iota // 左端を基準にする
+	anchorMiddle               // 中央を基準にする
+	anchorEnd                  // 右端を基準にする
+)
+
+// point は画像上の座標
+type point struct {
+	x, y float64
+}
+
+// canvas はグラフを描画する先（SVG・PNG）
+type canvas interface {
+	// asciiOnly は英数字しか描画できない場合にtrueを返す（タイトルなどは英語で描画する）
+	asciiOnly() bool
+	fillRect(x, y, w, h float64, c color.NRGBA)
+	fillPolygon(points []point, c color.NRGBA)
+	strokeLine(points []point, width float64, c color.NRGBA)
+	// text は y をベースラインとして文字列を描画する
+	text(x, y float64, s string, size float64, a anchor, c color.NRGBA)
+}
+
+// draw はグラフを canvas に描画する
+func draw(c *Chart, cv canvas) {
+	title, empty := c.Title, "データがありません"
+	if cv.asciiOnly() {
+		title, empty = c.Alt, "No data"
+	}
+	cv.fillRect(0, 0, Width, Height, backgroundColor)
+	cv.text(24, 40, title, titleSize, anchorStart, textColor)
+	if total(c.Values) == 0 {
+		cv.text(Width/2, Height/2, empty, labelSize, anchorMiddle, mutedColor)
+		return
+	}
+	switch c.Kind {
+	case KindPie:
+		drawPie(c, cv)
+	case KindLine:
+		drawLine(c, cv)
+	default:
+		drawBars(c, cv)
+	}
+}
+
+// drawBars は横棒グラフを描画する（ラベル・棒・値の順）
+func drawBars(c *Chart, cv canvas) {
+	const (
+		top       = 70.0
+		bottom    = Height - 30.0
+		labelEnd  = 200.0
+		barStart  = 212.0
+		barMaxEnd = Width - 90.0
+	)
+	rowHeight := min(40, (bottom-top)/float64(len(c.Values)))
+	maxValue := float64(maxOf(c.Values))
+	for i, v := range c.Values {
+		y := top + rowHeight*float64(i)
+		baseline := y + rowHeight/2 + labelSize*0.35
+		cv.text(labelEnd, baseline, label(c.Labels[i]), labelSize, anchorEnd, textColor)
+		barWidth := (barMaxEnd - barStart) * float64(v) / maxValue
+		cv.fillRect(barStart, y+rowHeight*0.2, barWidth, rowHeight*0.6, palette[0])
+		cv.text(barStart+barWidth+8, baseline, fmt.Sprint(v), labelSize, anchorStart, textColor)
+	}
+}
+
+// drawPie は円グラフと凡例を描画する（12時の位置から時計回り）
+func drawPie(c *Chart, cv canvas) {
+	const (
+		cx, cy, radius = 220.0, 270.0, 170.0
+		legendX        = 440.0
+		legendTop      = 90.0
+		legendRow      = 28.0
+	)
+	sum := float64(total(c.Values))
+	angle := -math.Pi / 2
+	for i, v := range c.Values {
+		fill := palette[i%len(palette)]
+		if v > 0 {
+			sweep := 2 * math.Pi * float64(v) / sum
+			cv.fillPolygon(wedge(cx, cy, radius, angle, sweep), fill)
+			angle += sweep
+		}
+		y := legendTop + legendRow*float64(i)
+		cv.fillRect(legendX, y, 14, 14, fill)
+		name := c.Labels[i]
+		if name == OtherLabel && cv.asciiOnly() {
+			name = "Others"
+		}
+		text := fmt.Sprintf("%s  %d (%.1f%%)", label(name), v, float64(v)*100/sum)
+		cv.text(legendX+22, y+12, text, labelSize, anchorStart, textColor)
+	}
+}
+
+// wedge は円グラフの扇形を、弧を2度ごとの頂点で近似した多角形で表す
+func wedge(cx, cy, radius, start, sweep float64) []point {
+	steps := max(int(math.Ceil(sweep/(math.Pi/90))), 1)
+	points := []point{{cx, cy}}
+	for i := 0; i <= steps; i++ {
+		a := start + sweep*float64(i)/float64(steps)
+		points = append(points, point{cx + radius*math.Cos(a), cy + radius*math.Sin(a)})
+	}
+	return points
+}
+
+// drawLine は目盛り・折れ線・期間のラベルを描画する
+func drawLine(c *Chart, cv canvas) {
+	const (
+		left   = 70.0
+		right  = Width - 50.0
+		top    = 70.0
+		bottom = Height - 60.0
+		ticks  = 4
+	)
+	step := niceStep(float64(maxOf(c.Values)) / ticks)
+	yMax := step * ticks
+	for i := 0; i <= ticks; i++ {
+		y := bottom - (bottom-top)*float64(i)/ticks
+		cv.strokeLine([]point{{left, y}, {right, y}}, 1, gridColor)
+		cv.text(left-8, y+labelSize*0.35, fmt.Sprint(int(step)*i), labelSize, anchorEnd, mutedColor)
+	}
+
+	points := make([]point, len(c.Values))
+	for i, v := range c.Values {
+		x := (left + right) / 2
+		if len(c.Values) > 1 {
+			x = left + (right-left)*float64(i)/float64(len(c.Values)-1)
+		}
+		points[i] = point{x, bottom - (bottom-top)*float64(v)/yMax}
+	}
+	area := append([]point{{points[0].x, bottom}}, points...)
+	area = append(area, point{points[len(points)-1].x, bottom})
+	fill := palette[0]
+	fill.A = 0x33
+	cv.fillPolygon(area, fill)
+	cv.strokeLine(points, 2.5, palette[0])
+	if len(points) <= 60 {
+		for _, p := range points {
+			cv.fillRect(p.x-2.5, p.y-2.5, 5, 5, palette[0])
+		}
+	}
+
+	// 期間のラベルは最初と最後を含めて最大6つ表示する
+	every := max((len(c.Labels)+4)/5, 1)
+	for i, l := range c.Labels {
+		if i%every != 0 && i != len(c.Labels)-1 {
+			continue
+		}
+		if i != len(c.Labels)-1 && len(c.Labels)-1-i < every/2 {
+			continue
+		}
+		cv.text(points[i].x, bottom+24, l, labelSize, anchorMiddle, mutedColor)
+	}
+}
+
+// niceStep は目盛りの間隔を、x以上の 1・2・5 × 10^k の値（最小1）にする
+func niceStep(x float64) float64 {
+	if x <= 1 {
+		return 1
+	}
+	base := math.Pow(10, math.Floor(math.Log10(x)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if base*m >= x {
+			return base * m
+		}
+	}
+	return base * 10
+}
+
+// label はラベルを最大の表示幅に切り詰める
+func label(s string) string {
+	return textwidth.Truncate(s, maxLabelWidth)
+}
+
+// total は値の合計を返す
+func total(values []int) int {
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return sum
+}
+
+// maxOf は値の最大値を返す（空の場合は0）
+func maxOf(values []int) int {
+	m := 0
+	for _, v := range values {
+		m = max(m, v)
+	}
+	return m
+}
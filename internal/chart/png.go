@@ -0,0 +1,147 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+	"golang.org/x/image/vector"
+)
+
+// Font はPNGのラベルの描画に使用するTrueType / OpenTypeのフォント
+// 組み込みのフォントは英数字のみのため、日本語のユーザー名などを描画する場合に指定する
+type Font struct {
+	font  *opentype.Font
+	faces map[float64]font.Face // 文字の大きさごとのフェイス
+}
+
+// LoadFont はフォントファイル（.ttf / .otf）を読み込む
+func LoadFont(path string) (*Font, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("フォントの読み込みエラー: %w", err)
+	}
+	f, err := opentype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("フォントの解析エラー: %w", err)
+	}
+	return &Font{font: f, faces: make(map[float64]font.Face)}, nil
+}
+
+// face は大きさ size ピクセルのフェイスを返す
+// ヒンティングはしない（環境によらず同じ画像にするため）
+func (f *Font) face(size float64) (font.Face, error) {
+	if face, ok := f.faces[size]; ok {
+		return face, nil
+	}
+	face, err := opentype.NewFace(f.font, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingNone})
+	if err != nil {
+		return nil, fmt.Errorf("フォントの作成エラー: %w", err)
+	}
+	f.faces[size] = face
+	return face, nil
+}
+
+// WritePNG はグラフをPNG形式で書き出す
+// labelFont がnilの場合は組み込みの英数字のフォントで描画し、描画できない文字は ? で表す
+func WritePNG(w io.Writer, c *Chart, labelFont *Font) error {
+	cv := &pngCanvas{img: image.NewRGBA(image.Rect(0, 0, Width, Height)), font: labelFont}
+	draw(c, cv)
+	if cv.err != nil {
+		return cv.err
+	}
+	return png.Encode(w, cv.img)
+}
+
+// pngCanvas は画像に描画する canvas
+type pngCanvas struct {
+	img  *image.RGBA
+	font *Font
+	err  error // 文字列の描画で発生した最初のエラー
+}
+
+// asciiOnly はフォントが指定されていない（組み込みのフォントで描画する）場合にtrueを返す
+func (cv *pngCanvas) asciiOnly() bool {
+	return cv.font == nil
+}
+
+func (cv *pngCanvas) fillRect(x, y, w, h float64, c color.NRGBA) {
+	cv.fillPolygon([]point{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}, c)
+}
+
+func (cv *pngCanvas) fillPolygon(points []point, c color.NRGBA) {
+	if len(points) < 3 {
+		return
+	}
+	r := vector.NewRasterizer(Width, Height)
+	r.MoveTo(float32(points[0].x), float32(points[0].y))
+	for _, p := range points[1:] {
+		r.LineTo(float32(p.x), float32(p.y))
+	}
+	r.ClosePath()
+	r.Draw(cv.img, cv.img.Bounds(), image.NewUniform(c), image.Point{})
+}
+
+// strokeLine は線分ごとに幅を持つ四角形を描画し、頂点を正方形で埋めてつなぐ
+// 重なる部分の塗りが打ち消し合わないよう、四角形は1つずつ描画する
+func (cv *pngCanvas) strokeLine(points []point, width float64, c color.NRGBA) {
+	half := width / 2
+	for i := 1; i < len(points); i++ {
+		a, b := points[i-1], points[i]
+		length := math.Hypot(b.x-a.x, b.y-a.y)
+		if length == 0 {
+			continue
+		}
+		nx, ny := -(b.y-a.y)/length*half, (b.x-a.x)/length*half
+		cv.fillPolygon([]point{{a.x + nx, a.y + ny}, {b.x + nx, b.y + ny}, {b.x - nx, b.y - ny}, {a.x - nx, a.y - ny}}, c)
+	}
+	for _, p := range points[1:max(len(points)-1, 1)] {
+		cv.fillRect(p.x-half, p.y-half, width, width, c)
+	}
+}
+
+func (cv *pngCanvas) text(x, y float64, s string, size float64, a anchor, c color.NRGBA) {
+	face, s, err := cv.face(size, s)
+	if err != nil {
+		if cv.err == nil {
+			cv.err = err
+		}
+		return
+	}
+	d := &font.Drawer{Dst: cv.img, Src: image.NewUniform(c), Face: face}
+	width := float64(d.MeasureString(s)) / 64
+	switch a {
+	case anchorMiddle:
+		x -= width / 2
+	case anchorEnd:
+		x -= width
+	}
+	d.Dot = fixed.Point26_6{X: fixed.Int26_6(math.Round(x * 64)), Y: fixed.Int26_6(math.Round(y * 64))}
+	d.DrawString(s)
+}
+
+// face は文字列の描画に使用するフェイスを返す
+// 組み込みのフォントの場合は大きさによらず同じフェイスを使用し、描画できない文字を ? に置き換えた文字列を返す
+func (cv *pngCanvas) face(size float64, s string) (font.Face, string, error) {
+	if cv.font != nil {
+		face, err := cv.font.face(size)
+		return face, s, err
+	}
+	face := basicfont.Face7x13
+	s = strings.Map(func(r rune) rune {
+		if _, ok := face.GlyphAdvance(r); !ok {
+			return '?'
+		}
+		return r
+	}, s)
+	return face, s, nil
+}
@@ -0,0 +1,85 @@
+package chart
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// svgFontFamily はSVGの文字列に指定するフォント（日本語は閲覧する環境のフォントで表示される）
+const svgFontFamily = "Hiragino Sans, Noto Sans JP, Meiryo, sans-serif"
+
+// WriteSVG はグラフをSVG形式で書き出す
+func WriteSVG(w io.Writer, c *Chart) error {
+	cv := &svgCanvas{}
+	fmt.Fprintf(&cv.b, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\" font-family=\"%s\">\n",
+		Width, Height, Width, Height, svgFontFamily)
+	draw(c, cv)
+	cv.b.WriteString("</svg>\n")
+	_, err := w.Write(cv.b.Bytes())
+	return err
+}
+
+// svgCanvas はSVGの要素を書き出す canvas
+type svgCanvas struct {
+	b bytes.Buffer
+}
+
+// asciiOnly はfalseを返す（文字は閲覧する環境のフォントで表示される）
+func (cv *svgCanvas) asciiOnly() bool {
+	return false
+}
+
+func (cv *svgCanvas) fillRect(x, y, w, h float64, c color.NRGBA) {
+	fmt.Fprintf(&cv.b, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\"%s/>\n", num(x), num(y), num(w), num(h), paint("fill", c))
+}
+
+func (cv *svgCanvas) fillPolygon(points []point, c color.NRGBA) {
+	fmt.Fprintf(&cv.b, "<polygon points=\"%s\"%s/>\n", pointList(points), paint("fill", c))
+}
+
+func (cv *svgCanvas) strokeLine(points []point, width float64, c color.NRGBA) {
+	fmt.Fprintf(&cv.b, "<polyline points=\"%s\" fill=\"none\" stroke-width=\"%s\" stroke-linejoin=\"round\"%s/>\n",
+		pointList(points), num(width), paint("stroke", c))
+}
+
+func (cv *svgCanvas) text(x, y float64, s string, size float64, a anchor, c color.NRGBA) {
+	fmt.Fprintf(&cv.b, "<text x=\"%s\" y=\"%s\" font-size=\"%s\"", num(x), num(y), num(size))
+	switch a {
+	case anchorMiddle:
+		cv.b.WriteString(` text-anchor="middle"`)
+	case anchorEnd:
+		cv.b.WriteString(` text-anchor="end"`)
+	}
+	cv.b.WriteString(paint("fill", c) + ">")
+	xml.EscapeText(&cv.b, []byte(s))
+	cv.b.WriteString("</text>\n")
+}
+
+// paint は fill / stroke の色の属性を作成する（不透明でない場合は透明度も指定する）
+func paint(attr string, c color.NRGBA) string {
+	s := fmt.Sprintf(" %s=\"#%02x%02x%02x\"", attr, c.R, c.G, c.B)
+	if c.A != 0xff {
+		s += fmt.Sprintf(" %s-opacity=\"%s\"", attr, num(float64(c.A)/0xff))
+	}
+	return s
+}
+
+// pointList は座標を points 属性の形式（x,y x,y ...）にする
+func pointList(points []point) string {
+	parts := make([]string, len(points))
+	for i, p := range points {
+		parts[i] = num(p.x) + "," + num(p.y)
+	}
+	return strings.Join(parts, " ")
+}
+
+// num は座標を小数点以下2桁までで表す（出力を環境によらず同じにするため丸める）
+func num(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
@@ -0,0 +1,191 @@
+// Package config は名前付きプロファイルを持つ設定ファイルを読み込む
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName はカレントディレクトリで探索する設定ファイル名
+const FileName = ".slack-reaction.yaml"
+
+// Config は設定ファイル全体を表す
+type Config struct {
+	Profiles map[string]*Profile `yaml:"profiles"`
+	Jobs     map[string]*Job     `yaml:"jobs"`
+}
+
+// Profile は分析のデフォルト値をまとめた名前付きプロファイル
+// 値が空のフィールドはコマンドのデフォルト値が使用される
+type Profile struct {
+	Channels        []string `yaml:"channels"`         // チャンネル名またはglobパターン
+	User            string   `yaml:"user"`             // ユーザー分析の対象ユーザー
+	Start           string   `yaml:"start"`            // 開始日（YYYY-MM-DD）
+	End             string   `yaml:"end"`              // 終了日（YYYY-MM-DD）
+	Period          string   `yaml:"period"`           // 直近の期間（例: 7d, 2w, 1m）。start/end とは併用不可
+	ExcludeUsers    []string `yaml:"exclude_users"`    // 集計から除外するユーザー（名前またはID）
+	ExcludeChannels []string `yaml:"exclude_channels"` // 分析から除外するチャンネル名またはglobパターン
+	Limits          Limits   `yaml:"limits"`           // ランキングの表示件数
+	Format          string   `yaml:"format"`           // 出力形式
+	TokenEnv        string   `yaml:"token_env"`        // Slackトークンを読み込む環境変数名
+}
+
+// Limits はランキングの表示件数（0の場合はデフォルト値）
+type Limits struct {
+	Emoji   int `yaml:"emoji"`
+	Message int `yaml:"message"`
+	User    int `yaml:"user"`
+	Thread  int `yaml:"thread"`
+	Channel int `yaml:"channel"`
+}
+
+// JobCommands はジョブで実行できる分析コマンド
+var JobCommands = []string{"channel", "channels", "workspace", "user"}
+
+// Job は daemon コマンドで定期的に実行する分析ジョブ
+// 分析の対象や除外設定はプロファイルから読み込み、期間は実行ごとに period から求める
+type Job struct {
+	Schedule string   `yaml:"schedule"` // cron式（例: "0 9 * * mon"）
+	Command  string   `yaml:"command"`  // 分析コマンド（channel, channels, workspace, user）
+	Profile  string   `yaml:"profile"`  // 分析条件のプロファイル名
+	Period   string   `yaml:"period"`   // 実行予定日の前日までの直近の期間（例: 7d, 1m）
+	Outputs  []Output `yaml:"outputs"`  // 分析結果の出力先
+}
+
+// Output はジョブの分析結果の出力先（いずれか1つを指定する）
+type Output struct {
+	Post string `yaml:"post"` // Block Kitのメッセージとして投稿するチャンネル名
+	File string `yaml:"file"` // テキスト形式で書き出すファイルのパス（{date} は実行予定日に置き換える）
+}
+
+// DefaultPath は設定ファイルのデフォルトパスを返す
+// カレントディレクトリの .slack-reaction.yaml、ユーザー設定ディレクトリの slack-reaction/config.yaml の順に探索する
+func DefaultPath() (string, error) {
+	candidates := []string{FileName}
+	if dir, err := os.UserConfigDir(); err == nil {
+		candidates = append(candidates, filepath.Join(dir, "slack-reaction", "config.yaml"))
+	}
+	for _, path := range candidates {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("設定ファイルが見つかりません（探索したパス: %v）", candidates)
+}
+
+// Load は設定ファイルを読み込む
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("設定ファイル読み込みエラー: %w", err)
+	}
+	cfg, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Parse はYAML形式の設定を解析し、各プロファイルを検証する
+func Parse(r io.Reader) (*Config, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+
+	cfg := &Config{}
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("設定ファイル解析エラー: %w", err)
+	}
+	for name, profile := range cfg.Profiles {
+		if profile == nil {
+			cfg.Profiles[name] = &Profile{}
+			continue
+		}
+		if err := profile.validate(); err != nil {
+			return nil, fmt.Errorf("プロファイル '%s': %w", name, err)
+		}
+	}
+	for name, job := range cfg.Jobs {
+		if err := cfg.validateJob(job); err != nil {
+			return nil, fmt.Errorf("ジョブ '%s': %w", name, err)
+		}
+	}
+	return cfg, nil
+}
+
+// Profile は指定された名前のプロファイルを返す
+func (c *Config) Profile(name string) (*Profile, error) {
+	profile, ok := c.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("プロファイル '%s' が設定ファイルにありません", name)
+	}
+	return profile, nil
+}
+
+// DateRange はプロファイルの期間指定からDateRangeを作成する
+// 期間が指定されていない場合は無制限の範囲を返す
+func (p *Profile) DateRange(now time.Time) (*domain.DateRange, error) {
+	if p.Period != "" {
+		return domain.ParseRelativeDateRange(p.Period, now)
+	}
+	return domain.ParseDateRange(p.Start, p.End, now.Location())
+}
+
+// validate はプロファイルの値を検証する
+func (p *Profile) validate() error {
+	if p.Period != "" && (p.Start != "" || p.End != "") {
+		return fmt.Errorf("period と start/end は同時に指定できません")
+	}
+	if _, err := p.DateRange(time.Now()); err != nil {
+		return err
+	}
+	for _, limit := range []int{p.Limits.Emoji, p.Limits.Message, p.Limits.User, p.Limits.Thread, p.Limits.Channel} {
+		if limit < 0 {
+			return fmt.Errorf("limits には0以上の値を指定してください")
+		}
+	}
+	return nil
+}
+
+// validateJob はジョブの値と参照するプロファイルを検証する
+// cron式・期間・出力形式は実行するパッケージに依存するため、daemon コマンドがジョブを読み込む際に検証する
+func (c *Config) validateJob(job *Job) error {
+	if job == nil {
+		return fmt.Errorf("schedule、command、profile、period、outputs を指定してください")
+	}
+	if !slices.Contains(JobCommands, job.Command) {
+		return fmt.Errorf("command には %s のいずれかを指定してください", strings.Join(JobCommands, ", "))
+	}
+	profile, err := c.Profile(job.Profile)
+	if err != nil {
+		return err
+	}
+	switch {
+	case job.Command == "channel" && len(profile.Channels) != 1:
+		return fmt.Errorf("channel コマンドのプロファイルには channels を1つだけ指定してください")
+	case job.Command == "channel" && len(profile.ExcludeChannels) > 0:
+		return fmt.Errorf("channel コマンドのプロファイルには exclude_channels を指定できません")
+	case job.Command == "channels" && len(profile.Channels) == 0:
+		return fmt.Errorf("channels コマンドのプロファイルには channels を指定してください")
+	case job.Command == "user" && profile.User == "":
+		return fmt.Errorf("user コマンドのプロファイルには user を指定してください")
+	}
+	if len(job.Outputs) == 0 {
+		return fmt.Errorf("outputs に出力先を1つ以上指定してください")
+	}
+	for _, output := range job.Outputs {
+		if (output.Post == "") == (output.File == "") {
+			return fmt.Errorf("outputs の各項目には post と file のどちらか一方を指定してください")
+		}
+	}
+	return nil
+}
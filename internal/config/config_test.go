@@ -0,0 +1,170 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sampleConfig = `
+profiles:
+  weekly-eng:
+    channels: ["team-*", "eng-general"]
+    period: 7d
+    exclude_users: [deploy-bot]
+    exclude_channels: ["team-random"]
+    limits:
+      emoji: 5
+      user: 20
+    format: text
+  yearly:
+    start: 2023-01-01
+    end: 2023-12-31
+    token_env: OTHER_SLACK_TOKEN
+`
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse(strings.NewReader(sampleConfig))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	profile, err := cfg.Profile("weekly-eng")
+	if err != nil {
+		t.Fatalf("Profile() error = %v", err)
+	}
+	if want := []string{"team-*", "eng-general"}; !reflect.DeepEqual(profile.Channels, want) {
+		t.Errorf("Channels = %v, want %v", profile.Channels, want)
+	}
+	if want := (Limits{Emoji: 5, User: 20}); profile.Limits != want {
+		t.Errorf("Limits = %+v, want %+v", profile.Limits, want)
+	}
+	if want := []string{"deploy-bot"}; !reflect.DeepEqual(profile.ExcludeUsers, want) {
+		t.Errorf("ExcludeUsers = %v, want %v", profile.ExcludeUsers, want)
+	}
+
+	now := time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC)
+	dateRange, err := profile.DateRange(now)
+	if err != nil {
+		t.Fatalf("DateRange() error = %v", err)
+	}
+	if want := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC); !dateRange.Start.Equal(want) {
+		t.Errorf("DateRange().Start = %v, want %v", dateRange.Start, want)
+	}
+
+	yearly, err := cfg.Profile("yearly")
+	if err != nil {
+		t.Fatalf("Profile() error = %v", err)
+	}
+	if yearly.TokenEnv != "OTHER_SLACK_TOKEN" {
+		t.Errorf("TokenEnv = %q, want OTHER_SLACK_TOKEN", yearly.TokenEnv)
+	}
+
+	if _, err := cfg.Profile("unknown"); err == nil {
+		t.Error("Profile(unknown) error = nil, want error")
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{
+			name:   "未知のフィールド",
+			config: "profiles:\n  p:\n    channel: general\n",
+		},
+		{
+			name:   "periodとstartの併用",
+			config: "profiles:\n  p:\n    period: 7d\n    start: 2023-01-01\n",
+		},
+		{
+			name:   "不正な期間",
+			config: "profiles:\n  p:\n    period: 7y\n",
+		},
+		{
+			name:   "開始日が終了日より後",
+			config: "profiles:\n  p:\n    start: 2023-02-01\n    end: 2023-01-01\n",
+		},
+		{
+			name:   "負の表示件数",
+			config: "profiles:\n  p:\n    limits:\n      emoji: -1\n",
+		},
+		{
+			name:   "ジョブのプロファイルがない",
+			config: "profiles:\n  p:\n    channels: [general]\n" + "jobs:\n  j:\n    schedule: \"0 9 * * 1\"\n    command: channel\n    profile: missing\n    period: 7d\n    outputs:\n      - post: reports\n",
+		},
+		{
+			name:   "ジョブのコマンドとプロファイルが一致しない",
+			config: "profiles:\n  p:\n    channels: [general]\n" + "jobs:\n  j:\n    schedule: \"0 9 * * 1\"\n    command: user\n    profile: p\n    period: 7d\n    outputs:\n      - post: reports\n",
+		},
+		{
+			name:   "channelコマンドのジョブでexclude_channels",
+			config: "profiles:\n  p:\n    channels: [general]\n    exclude_channels: [random]\n" + "jobs:\n  j:\n    schedule: \"0 9 * * 1\"\n    command: channel\n    profile: p\n    period: 7d\n    outputs:\n      - post: reports\n",
+		},
+		{
+			name:   "ジョブの出力先がない",
+			config: "profiles:\n  p:\n    channels: [general]\n" + "jobs:\n  j:\n    schedule: \"0 9 * * 1\"\n    command: channel\n    profile: p\n    period: 7d\n",
+		},
+		{
+			name:   "ジョブの出力先にpostとfileを併記",
+			config: "profiles:\n  p:\n    channels: [general]\n" + "jobs:\n  j:\n    schedule: \"0 9 * * 1\"\n    command: channel\n    profile: p\n    period: 7d\n    outputs:\n      - post: reports\n        file: out.txt\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.config)); err == nil {
+				t.Errorf("Parse() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(sampleConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(cfg.Profiles) != 2 {
+		t.Errorf("len(Profiles) = %d, want 2", len(cfg.Profiles))
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load(missing) error = nil, want error")
+	}
+}
+
+func TestParse_Jobs(t *testing.T) {
+	cfg, err := Parse(strings.NewReader(sampleConfig + `
+jobs:
+  weekly-eng-report:
+    schedule: "0 9 * * mon"
+    command: channels
+    profile: weekly-eng
+    period: 7d
+    outputs:
+      - post: "#eng-reports"
+      - file: reports/weekly-{date}.txt
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	job := cfg.Jobs["weekly-eng-report"]
+	if job == nil {
+		t.Fatal("Jobs[weekly-eng-report] = nil")
+	}
+	want := []Output{{Post: "#eng-reports"}, {File: "reports/weekly-{date}.txt"}}
+	if job.Command != "channels" || job.Profile != "weekly-eng" || !reflect.DeepEqual(job.Outputs, want) {
+		t.Errorf("job = %+v", job)
+	}
+}
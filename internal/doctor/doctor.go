@@ -0,0 +1,231 @@
+// Package doctor は分析の実行前に、トークン・スコープ・チャンネルへの参加状況を診断する
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// Status は診断項目の結果
+type Status int
+
+const (
+	StatusOK      Status = iota // 問題なし
+	StatusWarning               // 一部の機能が使用できない
+	StatusError                 // 分析を実行できない
+)
+
+// Result は1つの診断項目の結果
+type Result struct {
+	Status  Status
+	Name    string // 診断項目
+	Message string
+	Fix     string // 対処方法（問題がない場合は空）
+}
+
+// Inspector はSlack APIでトークンの権限を確認する
+type Inspector interface {
+	// AuthTest はトークンの認証情報とスコープを取得する
+	AuthTest(ctx context.Context) (*domain.TokenInfo, error)
+	// Probe はスコープが必要なAPIを呼び出してアクセスできるかどうかを確認する
+	Probe(ctx context.Context, scope, userID string) error
+	// CheckHistory はチャンネルのメッセージ履歴を取得できるかどうかを確認する
+	CheckHistory(ctx context.Context, channelID string) error
+}
+
+// scopeRequirement はツールが使用するスコープとその用途
+type scopeRequirement struct {
+	scope    string
+	required bool   // 分析に必須かどうか
+	purpose  string // スコープが必要な機能
+	probe    bool   // Inspector.Probe でアクセスを確認できるか
+}
+
+// scopeRequirements はツールが使用するスコープ（docs/slack-token-setup.md の必須・オプションスコープ）
+var scopeRequirements = []scopeRequirement{
+	{scope: "channels:read", required: true, purpose: "パブリックチャンネルの一覧と情報の取得", probe: true},
+	{scope: "channels:history", required: true, purpose: "パブリックチャンネルのメッセージ履歴の取得"},
+	{scope: "users:read", required: true, purpose: "ユーザー情報の取得", probe: true},
+	{scope: "reactions:read", required: true, purpose: "リアクション情報の取得"},
+	{scope: "groups:read", purpose: "プライベートチャンネルの一覧と情報の取得", probe: true},
+	{scope: "groups:history", purpose: "プライベートチャンネルのメッセージ履歴の取得"},
+	{scope: "search:read", purpose: "Search APIによるメッセージの検索（user / search コマンド）", probe: true},
+	{scope: "chat:write", purpose: "-post による分析結果の投稿"},
+}
+
+// troubleshooting は対処方法の詳細を記載したドキュメント
+const troubleshooting = "docs/TROUBLESHOOTING.md"
+
+// 対処方法（docs/TROUBLESHOOTING.md の内容を要約したもの）
+const (
+	fixNoToken = "Slackトークンを取得して環境変数に設定してください。新しいターミナルセッションでは再度設定が必要です" +
+		"（docs/slack-token-setup.md、" + troubleshooting + " の「環境変数 SLACK_USER_TOKEN が設定されていません」を参照）"
+	fixInvalidAuth = "トークンが正しくコピーされているか、再生成されていないか確認し、必要に応じて新しいトークンを取得してください" +
+		"（" + troubleshooting + " の「invalid_auth または not_authed」を参照）"
+	fixNotInChannel = "Slackでチャンネルを開いて「参加」をクリックしてください。プライベートチャンネルはメンバーに招待を依頼し、" +
+		"アーカイブ済みのチャンネルは復元してください（" + troubleshooting + " の「チャンネルに参加していません」を参照）"
+	fixChannelNotFound = "チャンネル名が正しいか確認してください（大文字小文字を区別します）。プライベートチャンネルの場合は groups:read と groups:history スコープを追加してください" +
+		"（" + troubleshooting + " の「チャンネルが見つからない」を参照）"
+	fixUserToken = "User Token（xoxp-）を使用してください。Bot Tokenではボットが参加しているチャンネルのみ分析でき、Search APIは使用できません" +
+		"（" + troubleshooting + " の「not_allowed_token_type」を参照）"
+)
+
+// fixMissingScope はスコープが不足している場合の対処方法を返す
+func fixMissingScope(scope string) string {
+	return fmt.Sprintf("Slackアプリの「OAuth & Permissions」で User Token Scopes に %s を追加し、「Install to Workspace」で再インストールしてください"+
+		"（%s の「missing_scope」を参照）", scope, troubleshooting)
+}
+
+// TokenMissing はトークンが設定されていない場合の診断結果を返す
+func TokenMissing(tokenEnv string) Result {
+	return Result{
+		Status:  StatusError,
+		Name:    "トークン",
+		Message: fmt.Sprintf("環境変数 %s が設定されていません", tokenEnv),
+		Fix:     fixNoToken,
+	}
+}
+
+// Doctor はトークン・スコープ・チャンネルを順に診断する
+type Doctor struct {
+	inspector   Inspector
+	channelRepo domain.ChannelRepository
+}
+
+// New は新しいDoctorを作成する
+func New(inspector Inspector, channelRepo domain.ChannelRepository) *Doctor {
+	return &Doctor{
+		inspector:   inspector,
+		channelRepo: channelRepo,
+	}
+}
+
+// Run は診断を実行する
+// channels には分析対象のチャンネル名またはglobパターンを指定し、参加状況と履歴の取得を確認する
+// 認証に失敗した場合はそれ以降の項目を診断せず、認証のエラーを返す
+func (d *Doctor) Run(ctx context.Context, channels []string) ([]Result, error) {
+	info, err := d.inspector.AuthTest(ctx)
+	if err != nil {
+		return []Result{{Status: StatusError, Name: "認証", Message: err.Error(), Fix: fixFor(err, "")}}, err
+	}
+
+	results := []Result{
+		{Status: StatusOK, Name: "認証", Message: fmt.Sprintf("%s（%s）としてワークスペース %s に接続しました %s", info.User, info.UserID, info.Team, info.TeamURL)},
+		tokenTypeResult(info),
+	}
+	results = append(results, d.checkScopes(ctx, info)...)
+	for _, name := range channels {
+		results = append(results, d.checkChannel(ctx, name)...)
+	}
+	return results, nil
+}
+
+// tokenTypeResult はトークンの種類を診断する
+func tokenTypeResult(info *domain.TokenInfo) Result {
+	switch info.Type {
+	case domain.TokenTypeUser:
+		return Result{Status: StatusOK, Name: "トークンの種類", Message: "User Token"}
+	case domain.TokenTypeBot:
+		return Result{Status: StatusWarning, Name: "トークンの種類", Message: "Bot Token です。ボットが参加していないチャンネルは分析できず、Search APIは使用できません", Fix: fixUserToken}
+	default:
+		return Result{Status: StatusWarning, Name: "トークンの種類", Message: "トークンの種類を判別できません", Fix: fixUserToken}
+	}
+}
+
+// checkScopes はスコープの付与状況と、確認できるスコープについてはAPIへのアクセスを診断する
+func (d *Doctor) checkScopes(ctx context.Context, info *domain.TokenInfo) []Result {
+	var results []Result
+	if info.Scopes == nil {
+		results = append(results, Result{Status: StatusWarning, Name: "スコープ", Message: "付与されているスコープの一覧を取得できませんでした。APIを呼び出して確認できるスコープのみ診断します"})
+	}
+
+	for _, req := range scopeRequirements {
+		name := "スコープ " + req.scope
+		failure := StatusWarning
+		if req.required {
+			failure = StatusError
+		}
+
+		if req.scope == "search:read" && info.Type == domain.TokenTypeBot {
+			results = append(results, Result{Status: StatusWarning, Name: name, Message: "Bot Tokenでは使用できません（" + req.purpose + "は全チャンネル横断方式になります）", Fix: fixUserToken})
+			continue
+		}
+		if info.Scopes != nil && !info.HasScope(req.scope) {
+			results = append(results, Result{Status: failure, Name: name, Message: "付与されていません（" + req.purpose + "に必要）", Fix: fixMissingScope(req.scope)})
+			continue
+		}
+		if !req.probe {
+			if info.Scopes != nil {
+				results = append(results, Result{Status: StatusOK, Name: name, Message: "付与されています"})
+			}
+			continue
+		}
+		if err := d.inspector.Probe(ctx, req.scope, info.UserID); err != nil {
+			results = append(results, Result{Status: failure, Name: name, Message: fmt.Sprintf("APIを呼び出せません（%s に必要）: %v", req.purpose, err), Fix: fixFor(err, req.scope)})
+			continue
+		}
+		results = append(results, Result{Status: StatusOK, Name: name, Message: "APIを呼び出せます"})
+	}
+	return results
+}
+
+// checkChannel はチャンネル（またはパターンに一致するチャンネル）への参加状況と履歴の取得を診断する
+func (d *Doctor) checkChannel(ctx context.Context, pattern string) []Result {
+	channels, err := service.SelectChannels(ctx, d.channelRepo, []string{pattern})
+	if err != nil {
+		fix := fixFor(err, "")
+		var notFound *domain.NotFoundError
+		var noMatch *domain.NoMatchError
+		if errors.As(err, &notFound) || errors.As(err, &noMatch) {
+			fix = fixChannelNotFound
+		}
+		return []Result{{Status: StatusError, Name: "チャンネル " + pattern, Message: err.Error(), Fix: fix}}
+	}
+
+	var results []Result
+	for _, channel := range channels {
+		name := "チャンネル #" + channel.Name
+		if !channel.IsMember {
+			results = append(results, Result{Status: StatusError, Name: name, Message: "参加していません", Fix: fixNotInChannel})
+			continue
+		}
+		if err := d.inspector.CheckHistory(ctx, channel.ID); err != nil {
+			scope := "channels:history"
+			if channel.IsPrivate {
+				scope = "groups:history"
+			}
+			results = append(results, Result{Status: StatusError, Name: name, Message: fmt.Sprintf("メッセージ履歴を取得できません: %v", err), Fix: fixFor(err, scope)})
+			continue
+		}
+		results = append(results, Result{Status: StatusOK, Name: name, Message: "参加しており、メッセージ履歴を取得できます"})
+	}
+	return results
+}
+
+// fixFor はSlack APIのエラーコードに対応する対処方法を返す（対応するものがない場合は空文字列）
+func fixFor(err error, scope string) string {
+	var notInChannel *domain.NotInChannelError
+	msg := err.Error()
+	switch {
+	case errors.As(err, &notInChannel), strings.Contains(msg, "not_in_channel"):
+		return fixNotInChannel
+	case strings.Contains(msg, "missing_scope"):
+		if scope == "" {
+			scope = "必要なスコープ"
+		}
+		return fixMissingScope(scope)
+	case strings.Contains(msg, "invalid_auth"), strings.Contains(msg, "not_authed"),
+		strings.Contains(msg, "token_revoked"), strings.Contains(msg, "token_expired"), strings.Contains(msg, "account_inactive"):
+		return fixInvalidAuth
+	case strings.Contains(msg, "channel_not_found"):
+		return fixChannelNotFound
+	case strings.Contains(msg, "not_allowed_token_type"):
+		return fixUserToken
+	}
+	return ""
+}
@@ -0,0 +1,159 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// mockInspector はInspectorのモック実装
+type mockInspector struct {
+	info        *domain.TokenInfo
+	authErr     error
+	probeErrs   map[string]error
+	historyErrs map[string]error
+	probed      []string
+}
+
+func (m *mockInspector) AuthTest(ctx context.Context) (*domain.TokenInfo, error) {
+	return m.info, m.authErr
+}
+
+func (m *mockInspector) Probe(ctx context.Context, scope, userID string) error {
+	m.probed = append(m.probed, scope)
+	return m.probeErrs[scope]
+}
+
+func (m *mockInspector) CheckHistory(ctx context.Context, channelID string) error {
+	return m.historyErrs[channelID]
+}
+
+// mockChannelRepository はChannelRepositoryのモック実装
+type mockChannelRepository struct {
+	channels []*domain.Channel
+}
+
+func (m *mockChannelRepository) FindByName(ctx context.Context, name string) (*domain.Channel, error) {
+	for _, channel := range m.channels {
+		if channel.Name == name {
+			return channel, nil
+		}
+	}
+	return nil, &domain.NotFoundError{Kind: "チャンネル", Name: name}
+}
+
+func (m *mockChannelRepository) FindAll(ctx context.Context) ([]*domain.Channel, error) {
+	return m.channels, nil
+}
+
+// findResult は診断項目の名前で結果を検索する
+func findResult(t *testing.T, results []Result, name string) Result {
+	t.Helper()
+	for _, result := range results {
+		if result.Name == name {
+			return result
+		}
+	}
+	t.Fatalf("result %q not found in %+v", name, results)
+	return Result{}
+}
+
+func TestDoctor_Run(t *testing.T) {
+	inspector := &mockInspector{
+		info: &domain.TokenInfo{
+			Type:   domain.TokenTypeUser,
+			UserID: "U1",
+			User:   "taro",
+			Team:   "Example",
+			Scopes: []string{"channels:read", "users:read", "reactions:read", "search:read"},
+		},
+		historyErrs: map[string]error{"C3": errors.New("missing_scope"), "G1": errors.New("missing_scope")},
+	}
+	repo := &mockChannelRepository{
+		channels: []*domain.Channel{
+			{ID: "C1", Name: "general", IsMember: true},
+			{ID: "C2", Name: "random", IsMember: false},
+			{ID: "C3", Name: "team-a", IsMember: true},
+			{ID: "G1", Name: "secret", IsMember: true, IsPrivate: true},
+		},
+	}
+
+	results, err := New(inspector, repo).Run(context.Background(), []string{"general", "random", "team-*", "secret", "missing", "nothing-*"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		status Status
+		fix    string
+	}{
+		{name: "トークンの種類", status: StatusOK},
+		{name: "スコープ channels:read", status: StatusOK},
+		{name: "スコープ channels:history", status: StatusError, fix: "channels:history を追加"},
+		{name: "スコープ groups:read", status: StatusWarning, fix: "groups:read を追加"},
+		{name: "スコープ search:read", status: StatusOK},
+		{name: "チャンネル #general", status: StatusOK},
+		{name: "チャンネル #random", status: StatusError, fix: "「チャンネルに参加していません」"},
+		{name: "チャンネル #team-a", status: StatusError, fix: "channels:history を追加"},
+		{name: "チャンネル #secret", status: StatusError, fix: "groups:history を追加"},
+		{name: "チャンネル missing", status: StatusError, fix: "「チャンネルが見つからない」"},
+		{name: "チャンネル nothing-*", status: StatusError, fix: "「チャンネルが見つからない」"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := findResult(t, results, tt.name)
+			if got.Status != tt.status {
+				t.Errorf("Status = %d, want %d (%s)", got.Status, tt.status, got.Message)
+			}
+			if !strings.Contains(got.Fix, tt.fix) {
+				t.Errorf("Fix = %q, want to contain %q", got.Fix, tt.fix)
+			}
+		})
+	}
+
+	// 付与されていないスコープのAPIは呼び出さない
+	for _, scope := range inspector.probed {
+		if scope == "groups:read" {
+			t.Errorf("probed %s which is not granted", scope)
+		}
+	}
+}
+
+func TestDoctor_Run_BotTokenWithoutScopes(t *testing.T) {
+	inspector := &mockInspector{
+		info:      &domain.TokenInfo{Type: domain.TokenTypeBot, UserID: "U1"},
+		probeErrs: map[string]error{"users:read": errors.New("missing_scope")},
+	}
+
+	results, err := New(inspector, &mockChannelRepository{}).Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if got := findResult(t, results, "トークンの種類"); got.Status != StatusWarning {
+		t.Errorf("token type status = %d, want warning", got.Status)
+	}
+	if got := findResult(t, results, "スコープ search:read"); got.Status != StatusWarning || !strings.Contains(got.Fix, "User Token") {
+		t.Errorf("search:read = %+v, want warning with user token fix", got)
+	}
+	// スコープの一覧がない場合はAPIを呼び出して確認する
+	if got := findResult(t, results, "スコープ users:read"); got.Status != StatusError || !strings.Contains(got.Fix, "users:read を追加") {
+		t.Errorf("users:read = %+v, want error with missing scope fix", got)
+	}
+}
+
+func TestDoctor_Run_AuthError(t *testing.T) {
+	inspector := &mockInspector{authErr: errors.New("認証情報取得エラー: invalid_auth")}
+
+	results, err := New(inspector, &mockChannelRepository{}).Run(context.Background(), []string{"general"})
+	if err == nil {
+		t.Fatal("Run() error = nil, want error")
+	}
+	if len(results) != 1 || results[0].Status != StatusError || !strings.Contains(results[0].Fix, "「invalid_auth または not_authed」") {
+		t.Errorf("results = %+v, want a single auth error with fix", results)
+	}
+}
@@ -1,11 +1,18 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
 
 // Channel はSlackチャンネルを表すドメインモデル
 type Channel struct {
-	ID   string
-	Name string
+	ID        string
+	Name      string
+	IsMember  bool      // トークンのユーザーがチャンネルに参加しているか
+	IsPrivate bool      // プライベートチャンネルか
+	Created   time.Time // チャンネルの作成日時（不明な場合はゼロ値）
 }
 
 // DateRange は日付範囲を表す値オブジェクト
@@ -29,3 +36,90 @@ func (dr *DateRange) Contains(t time.Time) bool {
 	}
 	return true
 }
+
+// EndBefore は next の直前の時刻（期間の終わりとして含める最後の時刻）を返す
+// Slackのタイムスタンプはマイクロ秒単位のため、1マイクロ秒前とする（1秒前とすると最後の1秒間の投稿が期間から漏れる）
+func EndBefore(next time.Time) time.Time {
+	return next.Add(-time.Microsecond)
+}
+
+// dateLayout は日付指定で使用するレイアウト（YYYY-MM-DD）
+const dateLayout = "2006-01-02"
+
+// ParseDateRange はYYYY-MM-DD形式の開始日・終了日からDateRangeを作成する
+// 空文字列の場合はその側を無制限とし、終了日はその日の終わり（翌日0時の直前）までを含む
+func ParseDateRange(start, end string, loc *time.Location) (*DateRange, error) {
+	dateRange := &DateRange{}
+	if start != "" {
+		t, err := time.ParseInLocation(dateLayout, start, loc)
+		if err != nil {
+			return nil, fmt.Errorf("開始日の形式が不正です（YYYY-MM-DD形式で指定してください）: %s", start)
+		}
+		dateRange.Start = t
+	}
+	if end != "" {
+		t, err := time.ParseInLocation(dateLayout, end, loc)
+		if err != nil {
+			return nil, fmt.Errorf("終了日の形式が不正です（YYYY-MM-DD形式で指定してください）: %s", end)
+		}
+		dateRange.End = EndBefore(t.AddDate(0, 0, 1))
+	}
+	if !dateRange.IsValid() {
+		return nil, fmt.Errorf("開始日 %s が終了日 %s より後になっています", start, end)
+	}
+	return dateRange, nil
+}
+
+// ParseRelativeDateRange は "7d"（日）、"2w"（週）、"1m"（月）形式の期間指定から、
+// 基準時刻 now までの直近の期間を表すDateRangeを作成する
+func ParseRelativeDateRange(period string, now time.Time) (*DateRange, error) {
+	if len(period) < 2 {
+		return nil, fmt.Errorf("期間の形式が不正です（例: 7d, 2w, 1m）: %s", period)
+	}
+	n, err := strconv.Atoi(period[:len(period)-1])
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("期間の形式が不正です（例: 7d, 2w, 1m）: %s", period)
+	}
+
+	var start time.Time
+	switch period[len(period)-1] {
+	case 'd':
+		start = now.AddDate(0, 0, -n)
+	case 'w':
+		start = now.AddDate(0, 0, -7*n)
+	case 'm':
+		start = now.AddDate(0, -n, 0)
+	default:
+		return nil, fmt.Errorf("期間の単位が不正です（d, w, m のいずれかを指定してください）: %s", period)
+	}
+	return &DateRange{Start: start, End: now}, nil
+}
+
+// Previous は比較の基準となる直前の期間を返す
+// 月初から月末までの期間は直前の同じ月数の暦月、それ以外は直前の同じ長さの期間とする
+func (dr *DateRange) Previous() (*DateRange, error) {
+	if dr.Start.IsZero() || dr.End.IsZero() {
+		return nil, fmt.Errorf("直前の期間を求めるには開始日と終了日の両方が必要です")
+	}
+	end := EndBefore(dr.Start)
+	if months := dr.calendarMonths(); months > 0 {
+		return &DateRange{Start: dr.Start.AddDate(0, -months, 0), End: end}, nil
+	}
+	return &DateRange{Start: end.Add(-dr.End.Sub(dr.Start)), End: end}, nil
+}
+
+// calendarMonths は期間が月初から月末までの暦月である場合にその月数を返す（それ以外は0）
+func (dr *DateRange) calendarMonths() int {
+	isMonthStart := func(t time.Time) bool {
+		return t.Day() == 1 && t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0
+	}
+	next := dr.End.Add(time.Microsecond)
+	if !isMonthStart(dr.Start) || !isMonthStart(next) {
+		return 0
+	}
+	months := (next.Year()-dr.Start.Year())*12 + int(next.Month()-dr.Start.Month())
+	if months <= 0 || !dr.Start.AddDate(0, months, 0).Equal(next) {
+		return 0
+	}
+	return months
+}
@@ -151,3 +151,191 @@ func TestDateRange_Contains(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDateRange(t *testing.T) {
+	loc := time.UTC
+
+	tests := []struct {
+		name      string
+		start     string
+		end       string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "開始日と終了日を指定",
+			start:     "2023-01-01",
+			end:       "2023-01-31",
+			wantStart: time.Date(2023, 1, 1, 0, 0, 0, 0, loc),
+			wantEnd:   time.Date(2023, 1, 31, 23, 59, 59, 999999000, loc),
+		},
+		{
+			name: "両方とも省略",
+		},
+		{
+			name:      "開始日のみ",
+			start:     "2023-01-01",
+			wantStart: time.Date(2023, 1, 1, 0, 0, 0, 0, loc),
+		},
+		{
+			name:      "同じ日",
+			start:     "2023-01-01",
+			end:       "2023-01-01",
+			wantStart: time.Date(2023, 1, 1, 0, 0, 0, 0, loc),
+			wantEnd:   time.Date(2023, 1, 1, 23, 59, 59, 999999000, loc),
+		},
+		{
+			name:    "形式が不正",
+			start:   "2023/01/01",
+			wantErr: true,
+		},
+		{
+			name:    "開始日が終了日より後",
+			start:   "2023-02-01",
+			end:     "2023-01-01",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDateRange(tt.start, tt.end, loc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Start.Equal(tt.wantStart) {
+				t.Errorf("Start = %v, want %v", got.Start, tt.wantStart)
+			}
+			if !got.End.Equal(tt.wantEnd) {
+				t.Errorf("End = %v, want %v", got.End, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestParseRelativeDateRange(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		period    string
+		wantStart time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "日数",
+			period:    "7d",
+			wantStart: time.Date(2024, 3, 24, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "週数",
+			period:    "2w",
+			wantStart: time.Date(2024, 3, 17, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "月数",
+			period:    "1m",
+			wantStart: time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC), // 2月31日は3月2日に正規化される
+		},
+		{
+			name:    "単位が不正",
+			period:  "7y",
+			wantErr: true,
+		},
+		{
+			name:    "数値が不正",
+			period:  "xd",
+			wantErr: true,
+		},
+		{
+			name:    "0以下",
+			period:  "0d",
+			wantErr: true,
+		},
+		{
+			name:    "空文字列",
+			period:  "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRelativeDateRange(tt.period, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRelativeDateRange() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Start.Equal(tt.wantStart) {
+				t.Errorf("Start = %v, want %v", got.Start, tt.wantStart)
+			}
+			if !got.End.Equal(now) {
+				t.Errorf("End = %v, want %v", got.End, now)
+			}
+		})
+	}
+}
+
+func TestDateRange_Previous(t *testing.T) {
+	mustParse := func(start, end string) *DateRange {
+		dr, err := ParseDateRange(start, end, time.UTC)
+		if err != nil {
+			t.Fatalf("ParseDateRange() error = %v", err)
+		}
+		return dr
+	}
+
+	tests := []struct {
+		name    string
+		dr      *DateRange
+		want    *DateRange
+		wantErr bool
+	}{
+		{
+			name: "暦月（前月の日数が異なる）",
+			dr:   mustParse("2024-03-01", "2024-03-31"),
+			want: mustParse("2024-02-01", "2024-02-29"),
+		},
+		{
+			name: "複数の暦月",
+			dr:   mustParse("2024-01-01", "2024-03-31"),
+			want: mustParse("2023-10-01", "2023-12-31"),
+		},
+		{
+			name: "日単位の期間",
+			dr:   mustParse("2024-03-11", "2024-03-24"),
+			want: mustParse("2024-02-26", "2024-03-10"),
+		},
+		{
+			name: "相対期間",
+			dr:   &DateRange{Start: time.Date(2024, 3, 24, 12, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)},
+			want: &DateRange{Start: time.Date(2024, 3, 17, 11, 59, 59, 999999000, time.UTC), End: time.Date(2024, 3, 24, 11, 59, 59, 999999000, time.UTC)},
+		},
+		{
+			name:    "終了日なし",
+			dr:      mustParse("2024-03-01", ""),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.dr.Previous()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Previous() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Start.Equal(tt.want.Start) || !got.End.Equal(tt.want.End) {
+				t.Errorf("Previous() = %v 〜 %v, want %v 〜 %v", got.Start, got.End, tt.want.Start, tt.want.End)
+			}
+		})
+	}
+}
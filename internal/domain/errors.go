@@ -0,0 +1,31 @@
+package domain
+
+import "fmt"
+
+// NotFoundError はチャンネルやユーザーなどの検索対象が見つからないことを表す
+type NotFoundError struct {
+	Kind string // 検索対象の種類（例: チャンネル、ユーザー）
+	Name string // 検索に使用した名前
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s '%s' が見つかりません", e.Kind, e.Name)
+}
+
+// NoMatchError はglobパターンに一致するチャンネルがないことを表す
+type NoMatchError struct {
+	Pattern string
+}
+
+func (e *NoMatchError) Error() string {
+	return fmt.Sprintf("パターン '%s' に一致するチャンネルがありません", e.Pattern)
+}
+
+// NotInChannelError はトークンのユーザーがチャンネルに参加していないため、メッセージを取得できないことを表す
+type NotInChannelError struct {
+	ChannelID string
+}
+
+func (e *NotInChannelError) Error() string {
+	return fmt.Sprintf("チャンネル '%s' に参加していません。Slackでこのチャンネルに参加してから再度実行してください", e.ChannelID)
+}
@@ -1,17 +1,22 @@
 package domain
 
-import "time"
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Message はSlackメッセージを表すドメインモデル
 type Message struct {
-	ID          string
-	Text        string
-	UserID      string
-	ChannelID   string
-	Timestamp   time.Time
-	Reactions   []Reaction
-	IsBot       bool
-	ThreadTS    string // スレッドのタイムスタンプ（空文字列の場合は通常メッセージ）
+	ID        string     `json:"id"`
+	Text      string     `json:"text"`
+	UserID    string     `json:"user_id"`
+	ChannelID string     `json:"channel_id"`
+	Timestamp time.Time  `json:"timestamp"`
+	Reactions []Reaction `json:"reactions"`
+	IsBot     bool       `json:"is_bot"`
+	ThreadTS  string     `json:"thread_ts,omitempty"` // スレッドのタイムスタンプ（空文字列の場合は通常メッセージ）
 }
 
 // HasReactions はメッセージにリアクションがあるかどうかを返す
@@ -28,6 +33,17 @@ func (m *Message) TotalReactionCount() int {
 	return total
 }
 
+// ReactionCount は指定された絵文字のリアクション数を返す（肌の色のバリエーションを含む）
+func (m *Message) ReactionCount(emoji string) int {
+	count := 0
+	for _, r := range m.Reactions {
+		if r.Matches(emoji) {
+			count += r.Count
+		}
+	}
+	return count
+}
+
 // IsThreadReply はこのメッセージがスレッドの返信かどうかを返す
 func (m *Message) IsThreadReply() bool {
 	return m.ThreadTS != "" && m.ThreadTS != m.ID
@@ -37,3 +53,23 @@ func (m *Message) IsThreadReply() bool {
 func (m *Message) IsThreadParent() bool {
 	return m.ThreadTS != "" && m.ThreadTS == m.ID
 }
+
+// PostedAt はメッセージのID（Slackのタイムスタンプ、例: "1705280000.000100"）から投稿日時をマイクロ秒まで求める
+// Timestamp は秒単位のため、IDを解析できない場合のみ Timestamp を返す
+func (m *Message) PostedAt() time.Time {
+	sec, frac, _ := strings.Cut(m.ID, ".")
+	s, err := strconv.ParseInt(sec, 10, 64)
+	if err != nil || len(frac) > 9 {
+		return m.Timestamp
+	}
+	var nsec int64
+	if frac != "" {
+		n, err := strconv.ParseInt(frac, 10, 64)
+		if err != nil {
+			return m.Timestamp
+		}
+		// 小数部の桁数からナノ秒に換算する（"000100" は100マイクロ秒）
+		nsec = n * int64(math.Pow10(9-len(frac)))
+	}
+	return time.Unix(s, nsec)
+}
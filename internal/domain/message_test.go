@@ -80,6 +80,56 @@ func TestMessage_TotalReactionCount(t *testing.T) {
 	}
 }
 
+func TestMessage_ReactionCount(t *testing.T) {
+	message := &Message{
+		Reactions: []Reaction{
+			{Name: "+1", Count: 3},
+			{Name: "+1::skin-tone-2", Count: 2},
+			{Name: "tada", Count: 4},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		emoji    string
+		expected int
+	}{
+		{name: "肌の色のバリエーションを含む", emoji: "+1", expected: 5},
+		{name: "肌の色のバリエーションを指定", emoji: "+1::skin-tone-2", expected: 2},
+		{name: "バリエーションなし", emoji: "tada", expected: 4},
+		{name: "リアクションなし", emoji: "eyes", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := message.ReactionCount(tt.emoji); got != tt.expected {
+				t.Errorf("ReactionCount(%q) = %v, want %v", tt.emoji, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNormalizeEmojiName(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "コロンで囲まれた絵文字名", input: ":tada:", expected: "tada"},
+		{name: "コロンなし", input: "tada", expected: "tada"},
+		{name: "肌の色のバリエーション", input: ":+1::skin-tone-2:", expected: "+1::skin-tone-2"},
+		{name: "前後の空白", input: " :eyes: ", expected: "eyes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NormalizeEmojiName(tt.input); got != tt.expected {
+				t.Errorf("NormalizeEmojiName(%q) = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
 func TestMessage_IsThreadReply(t *testing.T) {
 	now := time.Now()
 	timestamp := now.Format("1504840306.000009")
@@ -168,3 +218,26 @@ func TestMessage_IsThreadParent(t *testing.T) {
 		})
 	}
 }
+
+func TestMessage_PostedAt(t *testing.T) {
+	posted := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		id       string
+		expected time.Time
+	}{
+		{name: "マイクロ秒まで", id: "1705280000.000100", expected: time.Unix(1705280000, 100_000)},
+		{name: "小数部なし", id: "1705280000", expected: time.Unix(1705280000, 0)},
+		{name: "解析できないID", id: "invalid", expected: posted},
+		{name: "小数部が数値でない", id: "1705280000.abc", expected: posted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &Message{ID: tt.id, Timestamp: posted}
+			if got := msg.PostedAt(); !got.Equal(tt.expected) {
+				t.Errorf("PostedAt() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
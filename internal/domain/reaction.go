@@ -1,27 +1,58 @@
 package domain
 
+import (
+	"strings"
+	"time"
+)
+
 // Reaction はSlackのリアクション（絵文字）を表すドメインモデル
 type Reaction struct {
-	Name  string // 絵文字名（例: "thumbsup", "smile"）
-	Count int    // リアクション数
+	Name  string `json:"name"`  // 絵文字名（例: "thumbsup", "smile"）
+	Count int    `json:"count"` // リアクション数
+}
+
+// skinToneSeparator は絵文字名と肌の色のバリエーションの区切り（例: "+1::skin-tone-2"）
+const skinToneSeparator = "::"
+
+// BaseName は肌の色のバリエーションを除いた絵文字名を返す（例: "+1::skin-tone-2" → "+1"）
+func (r Reaction) BaseName() string {
+	name, _, _ := strings.Cut(r.Name, skinToneSeparator)
+	return name
+}
+
+// Matches はリアクションが指定された絵文字かどうかを返す
+// 肌の色のバリエーションを指定しない場合は、全てのバリエーションに一致する
+func (r Reaction) Matches(emoji string) bool {
+	return r.Name == emoji || r.BaseName() == emoji
+}
+
+// NormalizeEmojiName は ":tada:" のようにコロンで囲まれた絵文字名からコロンを除く
+func NormalizeEmojiName(name string) string {
+	return strings.Trim(strings.TrimSpace(name), ":")
 }
 
 // EmojiCount は絵文字の使用回数を集計するためのドメインモデル
 type EmojiCount struct {
-	Emoji string
-	Count int
+	Emoji string `json:"emoji"`
+	Count int    `json:"count"`
 }
 
 // MessageReaction はメッセージとそのリアクション数を表すドメインモデル
 type MessageReaction struct {
-	Text      string
-	Reactions int
-	Timestamp string
+	Text      string    `json:"text"`
+	Reactions int       `json:"reactions"`
+	Timestamp time.Time `json:"timestamp"`  // メッセージの投稿日時
+	UserID    string    `json:"user_id"`    // メッセージの投稿者
+	ChannelID string    `json:"channel_id"` // メッセージが投稿されたチャンネル
+	MessageID string    `json:"message_id"` // メッセージのID（Slackのタイムスタンプ）
 }
 
 // ThreadStats はスレッドのコメント数を表すドメインモデル
 type ThreadStats struct {
-	Text      string
-	ReplyCount int
-	Timestamp string
+	Text       string    `json:"text"`
+	ReplyCount int       `json:"reply_count"`
+	Timestamp  time.Time `json:"timestamp"`  // スレッドの親メッセージの投稿日時
+	UserID     string    `json:"user_id"`    // スレッドの親メッセージの投稿者
+	ChannelID  string    `json:"channel_id"` // スレッドの親メッセージが投稿されたチャンネル
+	MessageID  string    `json:"message_id"` // スレッドの親メッセージのID（Slackのタイムスタンプ）
 }
@@ -12,7 +12,9 @@ type ChannelRepository interface {
 type MessageRepository interface {
 	FindByChannel(ctx context.Context, channelID string, dateRange *DateRange) ([]*Message, error)
 	FindThreadReplies(ctx context.Context, channelID string, threadTS string, dateRange *DateRange) ([]*Message, error)
+	FindThread(ctx context.Context, channelID string, threadTS string, dateRange *DateRange) (*Message, []*Message, error)
 	FindByUser(ctx context.Context, userID string, dateRange *DateRange) ([]*Message, error)
+	FindBySearch(ctx context.Context, query string, dateRange *DateRange, excludedChannels map[string]bool) ([]*Message, []string, error)
 }
 
 // UserRepository はユーザー情報を取得するリポジトリインターフェース
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// HistorySample はチャンネルの履歴の一部（最新の1ページ）を取得した結果を表すドメインモデル
+// API呼び出し回数の見積もりに使用する
+type HistorySample struct {
+	Messages      int       // 取得したメッセージ数
+	ThreadParents int       // そのうち返信のあるスレッドの親メッセージ数
+	HasMore       bool      // 期間内にさらに古いメッセージがあるか
+	Oldest        time.Time // 取得した最も古いメッセージの日時
+	Newest        time.Time // 取得した最も新しいメッセージの日時
+}
+
+// SearchSample はSearch APIでユーザーの投稿を検索した最初のページの結果を表すドメインモデル
+type SearchSample struct {
+	Total    int  // 検索に一致したメッセージの総数
+	Channels int  // 最初のページに含まれるチャンネル数
+	Complete bool // 最初のページに全件が含まれているか
+}
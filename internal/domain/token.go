@@ -0,0 +1,32 @@
+package domain
+
+// TokenType はSlackトークンの種類
+type TokenType string
+
+// トークンの種類
+const (
+	TokenTypeUser    TokenType = "user"    // User Token（xoxp-）
+	TokenTypeBot     TokenType = "bot"     // Bot Token（xoxb-）
+	TokenTypeUnknown TokenType = "unknown" // 判別できないトークン
+)
+
+// TokenInfo はトークンの認証情報と付与されているスコープを表すドメインモデル
+type TokenInfo struct {
+	Type    TokenType
+	UserID  string
+	User    string
+	TeamID  string
+	Team    string
+	TeamURL string
+	Scopes  []string // 付与されているスコープ（取得できなかった場合は nil）
+}
+
+// HasScope はスコープが付与されているかどうかを返す
+func (t *TokenInfo) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -10,9 +10,9 @@ type User struct {
 
 // UserStats はユーザーの統計情報を表すドメインモデル
 type UserStats struct {
-	UserID   string
-	UserName string
-	Count    int
+	UserID   string `json:"user_id"`
+	UserName string `json:"user_name"`
+	Count    int    `json:"count"`
 }
 
 // GetDisplayName は表示名を優先順位に従って返す
@@ -34,8 +34,11 @@ func (r *ChannelRepository) FindByName(ctx context.Context, name string) (*domai
 	for _, conversation := range conversations {
 		if conversation.Name == name {
 			return &domain.Channel{
-				ID:   conversation.ID,
-				Name: conversation.Name,
+				ID:        conversation.ID,
+				Name:      conversation.Name,
+				IsMember:  conversation.IsMember,
+				IsPrivate: conversation.IsPrivate,
+				Created:   conversation.Created.Time(),
 			}, nil
 		}
 	}
@@ -54,14 +57,17 @@ func (r *ChannelRepository) FindByName(ctx context.Context, name string) (*domai
 		for _, conversation := range conversations {
 			if conversation.Name == name {
 				return &domain.Channel{
-					ID:   conversation.ID,
-					Name: conversation.Name,
+					ID:        conversation.ID,
+					Name:      conversation.Name,
+					IsMember:  conversation.IsMember,
+					IsPrivate: conversation.IsPrivate,
+					Created:   conversation.Created.Time(),
 				}, nil
 			}
 		}
 	}
 
-	return nil, fmt.Errorf("チャンネル '%s' が見つかりません", name)
+	return nil, &domain.NotFoundError{Kind: "チャンネル", Name: name}
 }
 
 // FindAll はすべてのチャンネルを取得する
@@ -81,8 +87,11 @@ func (r *ChannelRepository) FindAll(ctx context.Context) ([]*domain.Channel, err
 
 		for _, conversation := range conversations {
 			allChannels = append(allChannels, &domain.Channel{
-				ID:   conversation.ID,
-				Name: conversation.Name,
+				ID:        conversation.ID,
+				Name:      conversation.Name,
+				IsMember:  conversation.IsMember,
+				IsPrivate: conversation.IsPrivate,
+				Created:   conversation.Created.Time(),
 			})
 		}
 
@@ -94,3 +103,27 @@ func (r *ChannelRepository) FindAll(ctx context.Context) ([]*domain.Channel, err
 
 	return allChannels, nil
 }
+
+// IsMember はユーザーがチャンネルのメンバーかどうかを返す
+func (r *ChannelRepository) IsMember(ctx context.Context, channelID, userID string) (bool, error) {
+	cursor := ""
+	for {
+		members, nextCursor, err := r.client.GetUsersInConversationContext(ctx, &slack.GetUsersInConversationParameters{
+			ChannelID: channelID,
+			Cursor:    cursor,
+			Limit:     1000,
+		})
+		if err != nil {
+			return false, fmt.Errorf("チャンネルのメンバー取得エラー: %w", err)
+		}
+		for _, member := range members {
+			if member == userID {
+				return true, nil
+			}
+		}
+		if nextCursor == "" {
+			return false, nil
+		}
+		cursor = nextCursor
+	}
+}
@@ -0,0 +1,27 @@
+package slack
+
+import "strings"
+
+// authErrorCodes は認証・認可の失敗を表すSlack APIのエラーコード
+var authErrorCodes = []string{
+	"invalid_auth",
+	"not_authed",
+	"token_revoked",
+	"token_expired",
+	"account_inactive",
+	"missing_scope",
+}
+
+// IsAuthError は認証・認可に関するSlack APIエラーかどうかを返す
+func IsAuthError(err error) bool {
+	if err == nil {
+		return false
+	}
+	errMsg := err.Error()
+	for _, code := range authErrorCodes {
+		if strings.Contains(errMsg, code) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestIsAuthError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "invalid_auth",
+			err:      errors.New("invalid_auth"),
+			expected: true,
+		},
+		{
+			name:     "ラップされたmissing_scope",
+			err:      fmt.Errorf("チャンネル一覧取得エラー: %w", errors.New("missing_scope")),
+			expected: true,
+		},
+		{
+			name:     "レート制限",
+			err:      errors.New("slack rate limit exceeded, retry after 10s"),
+			expected: false,
+		},
+		{
+			name:     "nil",
+			err:      nil,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsAuthError(tt.err); got != tt.expected {
+				t.Errorf("IsAuthError(%v) = %v, want %v", tt.err, got, tt.expected)
+			}
+		})
+	}
+}
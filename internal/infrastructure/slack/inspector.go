@@ -0,0 +1,132 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+// scopesHeader はトークンに付与されているスコープを返すレスポンスヘッダー
+const scopesHeader = "X-OAuth-Scopes"
+
+// Inspector はトークンの種類・スコープと、各APIへのアクセス可否を確認する
+// スコープはSlack APIのレスポンスヘッダーから取得するため、専用のクライアントを使用する
+type Inspector struct {
+	client *slack.Client
+	token  string
+
+	mu     sync.Mutex
+	scopes *string // 最後に受け取ったレスポンスのスコープ（ヘッダーがない場合は nil）
+}
+
+// NewInspector はトークンから新しいInspectorを作成する
+func NewInspector(token string, options ...slack.Option) *Inspector {
+	i := &Inspector{token: token}
+	httpClient := &http.Client{Transport: &scopeRecorder{inspector: i, base: http.DefaultTransport}}
+	i.client = slack.New(token, append(options, slack.OptionHTTPClient(httpClient))...)
+	return i
+}
+
+// Client は診断に使用するSlackクライアントを返す（リポジトリの作成に使用する）
+func (i *Inspector) Client() *slack.Client {
+	return i.client
+}
+
+// AuthTest は auth.test でトークンの認証情報とスコープを取得する
+func (i *Inspector) AuthTest(ctx context.Context) (*domain.TokenInfo, error) {
+	resp, err := i.client.AuthTestContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("認証情報取得エラー: %w", err)
+	}
+
+	info := &domain.TokenInfo{
+		Type:    tokenType(i.token, resp.BotID),
+		UserID:  resp.UserID,
+		User:    resp.User,
+		TeamID:  resp.TeamID,
+		Team:    resp.Team,
+		TeamURL: resp.URL,
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if i.scopes != nil {
+		info.Scopes = parseScopes(*i.scopes)
+	}
+	return info, nil
+}
+
+// Probe はスコープが必要なAPIを実際に呼び出し、アクセスできるかどうかを確認する
+// 対応するスコープ: channels:read, groups:read, users:read, search:read
+func (i *Inspector) Probe(ctx context.Context, scope, userID string) error {
+	var err error
+	switch scope {
+	case "channels:read":
+		_, _, err = i.client.GetConversationsContext(ctx, &slack.GetConversationsParameters{Types: []string{"public_channel"}, Limit: 1})
+	case "groups:read":
+		_, _, err = i.client.GetConversationsContext(ctx, &slack.GetConversationsParameters{Types: []string{"private_channel"}, Limit: 1})
+	case "users:read":
+		_, err = i.client.GetUserInfoContext(ctx, userID)
+	case "search:read":
+		_, err = i.client.SearchMessagesContext(ctx, "from:<@"+userID+">", slack.SearchParameters{Count: 1, Page: 1})
+	default:
+		return fmt.Errorf("スコープ %s の確認には対応していません", scope)
+	}
+	return err
+}
+
+// CheckHistory はチャンネルのメッセージ履歴を取得できるかどうかを確認する
+func (i *Inspector) CheckHistory(ctx context.Context, channelID string) error {
+	_, err := i.client.GetConversationHistoryContext(ctx, &slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Limit:     1,
+	})
+	return err
+}
+
+// tokenType はトークンの接頭辞と auth.test の bot_id からトークンの種類を判別する
+func tokenType(token, botID string) domain.TokenType {
+	switch {
+	case strings.HasPrefix(token, "xoxp-"):
+		return domain.TokenTypeUser
+	case strings.HasPrefix(token, "xoxb-"), botID != "":
+		return domain.TokenTypeBot
+	default:
+		return domain.TokenTypeUnknown
+	}
+}
+
+// parseScopes はカンマ区切りのスコープを分割する
+func parseScopes(header string) []string {
+	scopes := []string{}
+	for _, scope := range strings.Split(header, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+// scopeRecorder はSlack APIのレスポンスヘッダーからスコープを記録する
+type scopeRecorder struct {
+	inspector *Inspector
+	base      http.RoundTripper
+}
+
+func (r *scopeRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := r.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if values, ok := resp.Header[http.CanonicalHeaderKey(scopesHeader)]; ok {
+		scopes := strings.Join(values, ",")
+		r.inspector.mu.Lock()
+		r.inspector.scopes = &scopes
+		r.inspector.mu.Unlock()
+	}
+	return resp, nil
+}
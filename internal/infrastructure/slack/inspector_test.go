@@ -0,0 +1,52 @@
+package slack
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+func TestInspector_AuthTest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(scopesHeader, "channels:read,channels:history, users:read")
+		w.Write([]byte(`{"ok":true,"url":"https://example.slack.com/","team":"Example","user":"taro","team_id":"T1","user_id":"U1"}`))
+	}))
+	defer server.Close()
+
+	inspector := NewInspector("xoxp-test", slack.OptionAPIURL(server.URL+"/"))
+	info, err := inspector.AuthTest(context.Background())
+	if err != nil {
+		t.Fatalf("AuthTest() error = %v", err)
+	}
+	if info.Type != domain.TokenTypeUser || info.UserID != "U1" || info.TeamURL != "https://example.slack.com/" {
+		t.Errorf("AuthTest() = %+v", info)
+	}
+	if want := []string{"channels:read", "channels:history", "users:read"}; !reflect.DeepEqual(info.Scopes, want) {
+		t.Errorf("Scopes = %v, want %v", info.Scopes, want)
+	}
+}
+
+func TestTokenType(t *testing.T) {
+	tests := []struct {
+		token    string
+		botID    string
+		expected domain.TokenType
+	}{
+		{token: "xoxp-1", expected: domain.TokenTypeUser},
+		{token: "xoxb-1", expected: domain.TokenTypeBot},
+		{token: "xoxe-1", botID: "B1", expected: domain.TokenTypeBot},
+		{token: "xoxe-1", expected: domain.TokenTypeUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := tokenType(tt.token, tt.botID); got != tt.expected {
+			t.Errorf("tokenType(%q, %q) = %s, want %s", tt.token, tt.botID, got, tt.expected)
+		}
+	}
+}
@@ -2,7 +2,10 @@ package slack
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,32 +17,32 @@ import (
 
 // MessageRepository はSlack APIを使用してメッセージを取得するリポジトリ
 type MessageRepository struct {
-	client *slack.Client
+	client   *slack.Client
+	progress io.Writer // 進捗表示の出力先
 }
 
 // NewMessageRepository は新しいMessageRepositoryを作成する
 func NewMessageRepository(client *slack.Client) *MessageRepository {
 	return &MessageRepository{
-		client: client,
+		client:   client,
+		progress: os.Stdout,
 	}
 }
 
+// SetProgressOutput は進捗表示の出力先を設定する（io.Discardで非表示）
+func (r *MessageRepository) SetProgressOutput(w io.Writer) {
+	r.progress = w
+}
+
 // FindByChannel はチャンネルのメッセージを取得する
 func (r *MessageRepository) FindByChannel(ctx context.Context, channelID string, dateRange *domain.DateRange) ([]*domain.Message, error) {
-	var oldest, latest string
-	if dateRange != nil {
-		if !dateRange.Start.IsZero() {
-			oldest = fmt.Sprintf("%d", dateRange.Start.Unix())
-		}
-		if !dateRange.End.IsZero() {
-			latest = fmt.Sprintf("%d", dateRange.End.Unix())
-		}
-	}
+	oldest, latest := timestampBounds(dateRange)
 
 	params := slack.GetConversationHistoryParameters{
 		ChannelID: channelID,
 		Oldest:    oldest,
 		Latest:    latest,
+		Inclusive: true,
 		Limit:     1000,
 	}
 
@@ -59,7 +62,7 @@ func (r *MessageRepository) FindByChannel(ctx context.Context, channelID string,
 			if err != nil {
 				// not_in_channelエラーの場合は、より分かりやすいメッセージを表示
 				if strings.Contains(err.Error(), "not_in_channel") {
-					return nil, fmt.Errorf("チャンネル '%s' に参加していません。Slackでこのチャンネルに参加してから再度実行してください", channelID)
+					return nil, &domain.NotInChannelError{ChannelID: channelID}
 				}
 				if isRateLimitError(err) {
 					sleepTime := extractRetryAfter(err.Error())
@@ -92,7 +95,7 @@ func (r *MessageRepository) FindByChannel(ctx context.Context, channelID string,
 			}
 		}
 
-		fmt.Printf("メッセージ取得中... (ページ %d, 累計: %d件)\n", pageCount, len(messages))
+		fmt.Fprintf(r.progress, "メッセージ取得中... (ページ %d, 累計: %d件)\n", pageCount, len(messages))
 
 		hasMore = history.HasMore
 		if hasMore {
@@ -103,69 +106,117 @@ func (r *MessageRepository) FindByChannel(ctx context.Context, channelID string,
 	return messages, nil
 }
 
-// FindThreadReplies はスレッドの返信を取得する
+// FindThreadReplies はスレッドの返信を取得する（親メッセージは含まない）
 func (r *MessageRepository) FindThreadReplies(ctx context.Context, channelID string, threadTS string, dateRange *domain.DateRange) ([]*domain.Message, error) {
-	var oldest, latest string
-	if dateRange != nil {
-		if !dateRange.Start.IsZero() {
-			oldest = fmt.Sprintf("%d", dateRange.Start.Unix())
+	oldest, latest := timestampBounds(dateRange)
+
+	params := slack.GetConversationRepliesParameters{
+		ChannelID: channelID,
+		Timestamp: threadTS,
+		Oldest:    oldest,
+		Latest:    latest,
+		Inclusive: true,
+		Limit:     1000,
+	}
+
+	thread, err := r.fetchThread(ctx, &params)
+	if err != nil {
+		return nil, err
+	}
+
+	var messages []*domain.Message
+	for _, msg := range thread {
+		// 親メッセージとボットの投稿をスキップ
+		if msg.ID == threadTS || msg.IsBot {
+			continue
 		}
-		if !dateRange.End.IsZero() {
-			latest = fmt.Sprintf("%d", dateRange.End.Unix())
+		// 日付範囲チェック
+		if dateRange != nil && !dateRange.Contains(msg.Timestamp) {
+			continue
 		}
+		messages = append(messages, msg)
 	}
 
+	return messages, nil
+}
+
+// FindThread はスレッドの親メッセージと返信を取得する
+// 返信は dateRange の期間内のもののみ返す（親メッセージは期間に関わらず返す）
+func (r *MessageRepository) FindThread(ctx context.Context, channelID string, threadTS string, dateRange *domain.DateRange) (*domain.Message, []*domain.Message, error) {
 	params := slack.GetConversationRepliesParameters{
 		ChannelID: channelID,
 		Timestamp: threadTS,
-		Oldest:    oldest,
-		Latest:    latest,
 		Limit:     1000,
 	}
 
+	thread, err := r.fetchThread(ctx, &params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 親メッセージはボットの投稿でも返し、返信からはボットの投稿を除く
+	var parent *domain.Message
+	var replies []*domain.Message
+	for _, msg := range thread {
+		switch {
+		case msg.ID == threadTS:
+			parent = msg
+		case msg.IsBot:
+			continue
+		case dateRange == nil || dateRange.Contains(msg.Timestamp):
+			replies = append(replies, msg)
+		}
+	}
+	if parent == nil {
+		return nil, nil, &domain.NotFoundError{Kind: "スレッドの親メッセージ", Name: threadTS}
+	}
+
+	return parent, replies, nil
+}
+
+// fetchThread はスレッドのメッセージ（親メッセージと返信）を全ページ取得する
+// ボットの投稿も含めて返すため、呼び出し側で IsBot を確認する
+func (r *MessageRepository) fetchThread(ctx context.Context, params *slack.GetConversationRepliesParameters) ([]*domain.Message, error) {
 	const maxRetries = 3
 	var messages []*domain.Message
 
-	for retry := 0; retry < maxRetries; retry++ {
-		replies, hasMore, _, err := r.client.GetConversationRepliesContext(ctx, &params)
+	for {
+		var (
+			replies    []slack.Message
+			hasMore    bool
+			nextCursor string
+			err        error
+		)
+
+		// レート制限対応のリトライループ
+		for retry := 0; retry < maxRetries; retry++ {
+			replies, hasMore, nextCursor, err = r.client.GetConversationRepliesContext(ctx, params)
+			if err == nil || !isRateLimitError(err) {
+				break
+			}
+			sleepTime := extractRetryAfter(err.Error())
+			if sleepTime <= 0 {
+				sleepTime = 10 + retry*5
+			}
+			time.Sleep(time.Duration(sleepTime) * time.Second)
+		}
 		if err != nil {
 			// not_in_channelエラーの場合は、より分かりやすいメッセージを表示
 			if strings.Contains(err.Error(), "not_in_channel") {
-				return nil, fmt.Errorf("チャンネル '%s' に参加していません。Slackでこのチャンネルに参加してから再度実行してください", channelID)
-			}
-			if isRateLimitError(err) {
-				sleepTime := extractRetryAfter(err.Error())
-				if sleepTime > 0 {
-					time.Sleep(time.Duration(sleepTime) * time.Second)
-					continue
-				} else {
-					time.Sleep(time.Duration(10+retry*5) * time.Second)
-					continue
-				}
+				return nil, &domain.NotInChannelError{ChannelID: params.ChannelID}
 			}
 			return nil, fmt.Errorf("スレッドメッセージ取得エラー: %w", err)
 		}
 
-		// 最初のメッセージ（親メッセージ）をスキップ
-		if len(replies) > 1 {
-			for _, msg := range replies[1:] {
-				domainMsg := r.convertToDomainMessage(&msg, channelID)
-				if domainMsg != nil {
-					// 日付範囲チェック
-					if dateRange != nil && !dateRange.Contains(domainMsg.Timestamp) {
-						continue
-					}
-					messages = append(messages, domainMsg)
-				}
-			}
+		for _, msg := range replies {
+			messages = append(messages, toDomainMessage(&msg, params.ChannelID))
 		}
 
-		if !hasMore {
-			break
+		if !hasMore || nextCursor == "" {
+			return messages, nil
 		}
+		params.Cursor = nextCursor
 	}
-
-	return messages, nil
 }
 
 // findByChannelSilentWithRetry はfindByChannelSilentをレート制限対応で呼び出す
@@ -206,7 +257,11 @@ func (r *MessageRepository) convertToDomainMessage(msg *slack.Message, channelID
 	if msg.SubType == "bot_message" || msg.BotID != "" {
 		return nil
 	}
+	return toDomainMessage(msg, channelID)
+}
 
+// toDomainMessage はボットの投稿を含めてSlackのMessageをドメインモデルに変換する
+func toDomainMessage(msg *slack.Message, channelID string) *domain.Message {
 	timestamp, _ := parseSlackTimestamp(msg.Timestamp)
 
 	reactions := make([]domain.Reaction, 0, len(msg.Reactions))
@@ -229,6 +284,26 @@ func (r *MessageRepository) convertToDomainMessage(msg *slack.Message, channelID
 	}
 }
 
+// timestampBounds は期間をSlack APIの oldest / latest（マイクロ秒単位のタイムスタンプ）に変換する
+// 期間の端の投稿も含めるよう、inclusive を指定して使用する
+func timestampBounds(dateRange *domain.DateRange) (oldest, latest string) {
+	if dateRange == nil {
+		return "", ""
+	}
+	if !dateRange.Start.IsZero() {
+		oldest = formatSlackTimestamp(dateRange.Start)
+	}
+	if !dateRange.End.IsZero() {
+		latest = formatSlackTimestamp(dateRange.End)
+	}
+	return oldest, latest
+}
+
+// formatSlackTimestamp は時刻をSlackのタイムスタンプ文字列（例: "1705280000.000100"）に変換する
+func formatSlackTimestamp(t time.Time) string {
+	return fmt.Sprintf("%d.%06d", t.Unix(), t.Nanosecond()/int(time.Microsecond))
+}
+
 // parseSlackTimestamp はSlackのタイムスタンプ文字列をtime.Timeに変換する
 func parseSlackTimestamp(ts string) (time.Time, error) {
 	parts := strings.Split(ts, ".")
@@ -290,87 +365,33 @@ func (r *MessageRepository) FindByUser(ctx context.Context, userID string, dateR
 	// エラーメッセージを分かりやすく表示
 	errMsg := err.Error()
 	if strings.Contains(errMsg, "not_allowed_token_type") {
-		fmt.Printf("Search APIは現在のトークンタイプでは利用できません。全チャンネル横断方式で検索します...\n")
-		fmt.Printf("（注: Search APIを使用するにはBot Tokenが必要です。User Tokenでは利用できません）\n")
+		fmt.Fprintf(r.progress, "Search APIは現在のトークンタイプでは利用できません。全チャンネル横断方式で検索します...\n")
+		fmt.Fprintf(r.progress, "（注: Search APIを使用するにはBot Tokenが必要です。User Tokenでは利用できません）\n")
 	} else {
-		fmt.Printf("Search APIが利用できないため、全チャンネル横断方式で検索します... (エラー: %v)\n", err)
+		fmt.Fprintf(r.progress, "Search APIが利用できないため、全チャンネル横断方式で検索します... (エラー: %v)\n", err)
 	}
 	return r.findByUserFallback(ctx, userID, dateRange)
 }
 
 // findByUserWithSearchAPI はSearch APIを使用してユーザーのメッセージを検索する
 func (r *MessageRepository) findByUserWithSearchAPI(ctx context.Context, userID string, dateRange *domain.DateRange) ([]*domain.Message, error) {
-	// 検索クエリを構築: "from:userID"
-	query := fmt.Sprintf("from:<@%s>", userID)
+	query := userSearchQuery(userID, dateRange)
 
-	// 日付範囲がある場合はクエリに追加
-	if dateRange != nil {
-		if !dateRange.Start.IsZero() {
-			query += fmt.Sprintf(" after:%s", dateRange.Start.Format("2006-01-02"))
-		}
-		if !dateRange.End.IsZero() {
-			query += fmt.Sprintf(" before:%s", dateRange.End.Format("2006-01-02"))
-		}
+	matches, err := r.searchAll(ctx, query)
+	if err != nil {
+		return nil, err
 	}
 
+	// 検索結果をドメインモデルに変換
 	var allMessages []*domain.Message
-	page := 1
-	const maxRetries = 3
-
-	for {
-	searchParams := slack.NewSearchParameters()
-	searchParams.Count = 100 // 最大100件/ページ
-	searchParams.Page = page
-
-		var searchResults *slack.SearchMessages
-		var err error
-
-		// レート制限対応のリトライループ
-		for retry := 0; retry < maxRetries; retry++ {
-			searchResults, err = r.client.SearchMessages(query, searchParams)
-			if err != nil {
-				if isRateLimitError(err) {
-					sleepTime := extractRetryAfter(err.Error())
-					if sleepTime > 0 {
-						time.Sleep(time.Duration(sleepTime) * time.Second)
-						continue
-					} else {
-						time.Sleep(time.Duration(10+retry*5) * time.Second)
-						continue
-					}
-				}
-				// レート制限以外のエラーは即座に返す
-				if retry == maxRetries-1 {
-					return nil, fmt.Errorf("Search APIエラー: %w", err)
-				}
-			} else {
-				// 成功したらループを抜ける
-				break
-			}
+	for i := range matches {
+		msg := r.convertSearchResultToDomainMessage(&matches[i], dateRange)
+		if msg != nil && msg.UserID == userID {
+			allMessages = append(allMessages, msg)
 		}
-
-		if err != nil {
-			return nil, fmt.Errorf("Search APIエラー: %w", err)
-		}
-
-		// 検索結果をドメインモデルに変換
-		for _, match := range searchResults.Matches {
-			msg := r.convertSearchResultToDomainMessage(&match, dateRange)
-			if msg != nil && msg.UserID == userID {
-				allMessages = append(allMessages, msg)
-			}
-		}
-
-		fmt.Printf("Search API: ページ %d 処理完了 (累計: %d件)\n", page, len(allMessages))
-
-		// 次のページがあるかチェック
-		if page >= searchResults.Paging.Pages {
-			break
-		}
-		page++
 	}
 
-	fmt.Printf("Search API検索完了: 合計 %d件のメッセージが見つかりました\n", len(allMessages))
+	fmt.Fprintf(r.progress, "Search API検索完了: 合計 %d件のメッセージが見つかりました\n", len(allMessages))
 
 	// リアクション情報とスレッド情報を補完するため、チャンネルごとにメッセージを取得
 	// チャンネルIDのセットを作成（容量を事前に推定）
@@ -382,7 +403,7 @@ func (r *MessageRepository) findByUserWithSearchAPI(ctx context.Context, userID
 	}
 
 	// 各チャンネルからメッセージを取得してリアクション情報を補完（並列処理）
-	fmt.Printf("リアクション情報を補完中... (%dチャンネル)\n", len(channelIDs))
+	fmt.Fprintf(r.progress, "リアクション情報を補完中... (%dチャンネル)\n", len(channelIDs))
 	// チャンネル数分の容量を事前に確保
 	channelMsgMap := make(map[string]map[string]*domain.Message, len(channelIDs)) // channelID -> messageID -> message
 	var mu sync.Mutex
@@ -463,10 +484,16 @@ func (r *MessageRepository) findByUserWithSearchAPI(ctx context.Context, userID
 	// スレッド返信を追加
 	allMessages = append(allMessages, threadReplies...)
 
-	fmt.Printf("リアクション情報の補完完了: 合計 %d件のメッセージ\n", len(allMessages))
+	fmt.Fprintf(r.progress, "リアクション情報の補完完了: 合計 %d件のメッセージ\n", len(allMessages))
 	return allMessages, nil
 }
 
+// userSearchQuery はユーザーの投稿を検索するSearch APIのクエリを作成する
+func userSearchQuery(userID string, dateRange *domain.DateRange) string {
+	// 検索クエリを構築: "from:userID"
+	return withDateFilters(fmt.Sprintf("from:<@%s>", userID), dateRange)
+}
+
 // convertSearchResultToDomainMessage はSearch APIの結果をドメインモデルに変換する
 func (r *MessageRepository) convertSearchResultToDomainMessage(match *slack.SearchMessage, dateRange *domain.DateRange) *domain.Message {
 	// タイムスタンプを解析
@@ -515,8 +542,8 @@ func (r *MessageRepository) findByUserFallback(ctx context.Context, userID strin
 	var allMessages []*domain.Message
 	totalChannels := len(channels)
 
-	fmt.Printf("全%dチャンネルからメッセージを検索します（並列処理、最大10並行）...\n", totalChannels)
-	fmt.Printf("進捗は10チャンネルごとに表示されます。処理には時間がかかる場合があります。\n")
+	fmt.Fprintf(r.progress, "全%dチャンネルからメッセージを検索します（並列処理、最大10並行）...\n", totalChannels)
+	fmt.Fprintf(r.progress, "進捗は10チャンネルごとに表示されます。処理には時間がかかる場合があります。\n")
 
 	// 並列処理でチャンネルからメッセージを取得
 	var mu sync.Mutex
@@ -538,7 +565,8 @@ func (r *MessageRepository) findByUserFallback(ctx context.Context, userID strin
 			messages, err := r.findByChannelSilentWithRetry(ctx, ch.ID, dateRange)
 			if err != nil {
 				// チャンネルに参加していない場合はスキップ
-				if strings.Contains(err.Error(), "参加していません") {
+				var notInChannel *domain.NotInChannelError
+				if errors.As(err, &notInChannel) {
 					processedMutex.Lock()
 					processedCount++
 					currentCount := processedCount
@@ -548,7 +576,7 @@ func (r *MessageRepository) findByUserFallback(ctx context.Context, userID strin
 						mu.Lock()
 						messageCount := len(allMessages)
 						mu.Unlock()
-						fmt.Printf("進捗: %d/%dチャンネル処理完了 (見つかったメッセージ: %d件)\n", currentCount, totalChannels, messageCount)
+						fmt.Fprintf(r.progress, "進捗: %d/%dチャンネル処理完了 (見つかったメッセージ: %d件)\n", currentCount, totalChannels, messageCount)
 					}
 					return
 				}
@@ -563,7 +591,7 @@ func (r *MessageRepository) findByUserFallback(ctx context.Context, userID strin
 						mu.Lock()
 						messageCount := len(allMessages)
 						mu.Unlock()
-						fmt.Printf("進捗: %d/%dチャンネル処理完了 (見つかったメッセージ: %d件)\n", currentCount, totalChannels, messageCount)
+						fmt.Fprintf(r.progress, "進捗: %d/%dチャンネル処理完了 (見つかったメッセージ: %d件)\n", currentCount, totalChannels, messageCount)
 					}
 					return
 				}
@@ -576,8 +604,8 @@ func (r *MessageRepository) findByUserFallback(ctx context.Context, userID strin
 					mu.Lock()
 					messageCount := len(allMessages)
 					mu.Unlock()
-					fmt.Printf("警告: チャンネル '%s' のメッセージ取得エラー: %v\n", ch.Name, err)
-					fmt.Printf("進捗: %d/%dチャンネル処理完了 (見つかったメッセージ: %d件)\n", currentCount, totalChannels, messageCount)
+					fmt.Fprintf(r.progress, "警告: チャンネル '%s' のメッセージ取得エラー: %v\n", ch.Name, err)
+					fmt.Fprintf(r.progress, "進捗: %d/%dチャンネル処理完了 (見つかったメッセージ: %d件)\n", currentCount, totalChannels, messageCount)
 				}
 				return
 			}
@@ -629,33 +657,26 @@ func (r *MessageRepository) findByUserFallback(ctx context.Context, userID strin
 				mu.Lock()
 				messageCount := len(allMessages)
 				mu.Unlock()
-				fmt.Printf("進捗: %d/%dチャンネル処理完了 (見つかったメッセージ: %d件)\n", currentCount, totalChannels, messageCount)
+				fmt.Fprintf(r.progress, "進捗: %d/%dチャンネル処理完了 (見つかったメッセージ: %d件)\n", currentCount, totalChannels, messageCount)
 			}
 		}(channel)
 	}
 
 	wg.Wait()
 
-	fmt.Printf("メッセージ検索完了: 合計 %d件のメッセージが見つかりました\n", len(allMessages))
+	fmt.Fprintf(r.progress, "メッセージ検索完了: 合計 %d件のメッセージが見つかりました\n", len(allMessages))
 	return allMessages, nil
 }
 
 // findByChannelSilent はFindByChannelと同じだが、進捗表示を抑制する
 func (r *MessageRepository) findByChannelSilent(ctx context.Context, channelID string, dateRange *domain.DateRange) ([]*domain.Message, error) {
-	var oldest, latest string
-	if dateRange != nil {
-		if !dateRange.Start.IsZero() {
-			oldest = fmt.Sprintf("%d", dateRange.Start.Unix())
-		}
-		if !dateRange.End.IsZero() {
-			latest = fmt.Sprintf("%d", dateRange.End.Unix())
-		}
-	}
+	oldest, latest := timestampBounds(dateRange)
 
 	params := slack.GetConversationHistoryParameters{
 		ChannelID: channelID,
 		Oldest:    oldest,
 		Latest:    latest,
+		Inclusive: true,
 		Limit:     1000,
 	}
 
@@ -673,7 +694,7 @@ func (r *MessageRepository) findByChannelSilent(ctx context.Context, channelID s
 			if err != nil {
 				// not_in_channelエラーの場合は、より分かりやすいメッセージを表示
 				if strings.Contains(err.Error(), "not_in_channel") {
-					return nil, fmt.Errorf("チャンネル '%s' に参加していません。Slackでこのチャンネルに参加してから再度実行してください", channelID)
+					return nil, &domain.NotInChannelError{ChannelID: channelID}
 				}
 				if isRateLimitError(err) {
 					sleepTime := extractRetryAfter(err.Error())
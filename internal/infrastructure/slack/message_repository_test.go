@@ -1,6 +1,15 @@
 package slack
 
-import "testing"
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/slack-go/slack"
+)
 
 func TestExtractRetryAfter(t *testing.T) {
 	tests := []struct {
@@ -49,3 +58,44 @@ func TestExtractRetryAfter(t *testing.T) {
 		})
 	}
 }
+
+// TestTimestampBounds は終了日の最後の1秒間の投稿も含まれるよう、マイクロ秒単位のタイムスタンプに変換することを確認する
+func TestTimestampBounds(t *testing.T) {
+	dateRange, err := domain.ParseDateRange("2024-01-15", "2024-01-15", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldest, latest := timestampBounds(dateRange)
+	if oldest != "1705276800.000000" || latest != "1705363199.999999" {
+		t.Errorf("timestampBounds() = %q, %q, want 1705276800.000000, 1705363199.999999", oldest, latest)
+	}
+
+	if oldest, latest := timestampBounds(nil); oldest != "" || latest != "" {
+		t.Errorf("timestampBounds(nil) = %q, %q, want empty", oldest, latest)
+	}
+}
+
+// TestFindThread_BotParent はボットが投稿した親メッセージを返し、返信からはボットの投稿を除くことを確認する
+func TestFindThread_BotParent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true,"has_more":false,"messages":[
+			{"type":"message","subtype":"bot_message","bot_id":"B1","text":"デプロイしました","ts":"1705280000.000100","thread_ts":"1705280000.000100"},
+			{"type":"message","user":"U1","text":"確認しました","ts":"1705280100.000200","thread_ts":"1705280000.000100"},
+			{"type":"message","bot_id":"B1","text":"ロールバックしました","ts":"1705280200.000300","thread_ts":"1705280000.000100"}
+		]}`))
+	}))
+	defer server.Close()
+
+	repo := NewMessageRepository(slack.New("xoxp-test", slack.OptionAPIURL(server.URL+"/")))
+	parent, replies, err := repo.FindThread(context.Background(), "C1", "1705280000.000100", nil)
+	if err != nil {
+		t.Fatalf("FindThread() error = %v", err)
+	}
+	if parent.ID != "1705280000.000100" || !parent.IsBot {
+		t.Errorf("parent = %+v, want the bot message", parent)
+	}
+	if len(replies) != 1 || replies[0].UserID != "U1" {
+		t.Errorf("replies = %+v, want only U1's reply", replies)
+	}
+}
@@ -0,0 +1,41 @@
+package slack
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// archivePath はメッセージのパーマリンクのパス（/archives/<チャンネルID>/p<タイムスタンプ>）
+var archivePath = regexp.MustCompile(`^/archives/([A-Z0-9]+)/p(\d{10})(\d{6})/?$`)
+
+// ParsePermalink はメッセージのパーマリンク（「リンクをコピー」のURL）からチャンネルIDとスレッドのタイムスタンプを返す
+// スレッドの返信のパーマリンクの場合は、親メッセージのタイムスタンプ（thread_ts）を返す
+// 例: https://example.slack.com/archives/C0123/p1700000000123456 → "C0123", "1700000000.123456"
+func ParsePermalink(permalink string) (channelID, threadTS string, err error) {
+	u, err := url.Parse(permalink)
+	if err != nil || u.Host == "" {
+		return "", "", fmt.Errorf("パーマリンクの形式が正しくありません: %s", permalink)
+	}
+	match := archivePath.FindStringSubmatch(u.Path)
+	if match == nil {
+		return "", "", fmt.Errorf("パーマリンクの形式が正しくありません（https://<ワークスペース>.slack.com/archives/<チャンネルID>/p<タイムスタンプ> の形式で指定してください）: %s", permalink)
+	}
+
+	channelID = match[1]
+	threadTS = match[2] + "." + match[3]
+	if parentTS := permalinkThreadTS(permalink); parentTS != "" {
+		threadTS = parentTS
+	}
+	return channelID, threadTS, nil
+}
+
+// permalinkThreadTS はスレッドの返信のパーマリンク（...?thread_ts=...）から親メッセージのタイムスタンプを返す
+// スレッドの返信でない場合は空文字列を返す
+func permalinkThreadTS(permalink string) string {
+	u, err := url.Parse(permalink)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("thread_ts")
+}
@@ -0,0 +1,80 @@
+package slack
+
+import "testing"
+
+func TestParsePermalink(t *testing.T) {
+	tests := []struct {
+		name          string
+		permalink     string
+		wantChannelID string
+		wantThreadTS  string
+		wantErr       bool
+	}{
+		{
+			name:          "親メッセージ",
+			permalink:     "https://example.slack.com/archives/C0123ABC/p1700000000123456",
+			wantChannelID: "C0123ABC",
+			wantThreadTS:  "1700000000.123456",
+		},
+		{
+			name:          "スレッドの返信",
+			permalink:     "https://example.slack.com/archives/C0123ABC/p1700000100000200?thread_ts=1700000000.123456&cid=C0123ABC",
+			wantChannelID: "C0123ABC",
+			wantThreadTS:  "1700000000.123456",
+		},
+		{
+			name:      "チャンネルのURL",
+			permalink: "https://example.slack.com/archives/C0123ABC",
+			wantErr:   true,
+		},
+		{
+			name:      "URLではない",
+			permalink: "p1700000000123456",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channelID, threadTS, err := ParsePermalink(tt.permalink)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePermalink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if channelID != tt.wantChannelID || threadTS != tt.wantThreadTS {
+				t.Errorf("ParsePermalink() = %q, %q, want %q, %q", channelID, threadTS, tt.wantChannelID, tt.wantThreadTS)
+			}
+		})
+	}
+}
+
+func TestPermalinkThreadTS(t *testing.T) {
+	tests := []struct {
+		name      string
+		permalink string
+		expected  string
+	}{
+		{
+			name:      "スレッドの返信",
+			permalink: "https://example.slack.com/archives/C0123/p1700000001000200?thread_ts=1700000000.000100&cid=C0123",
+			expected:  "1700000000.000100",
+		},
+		{
+			name:      "通常のメッセージ",
+			permalink: "https://example.slack.com/archives/C0123/p1700000000000100",
+			expected:  "",
+		},
+		{
+			name:      "パーマリンクなし",
+			permalink: "",
+			expected:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := permalinkThreadTS(tt.permalink); got != tt.expected {
+				t.Errorf("permalinkThreadTS() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package slack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/slack-go/slack"
+)
+
+// Publisher はSlackにメッセージを投稿する（読み取り専用のリポジトリとは分離している）
+// 投稿には chat:write スコープが必要
+type Publisher struct {
+	client *slack.Client
+
+	mu      sync.Mutex
+	teamURL string // ワークスペースのURL（auth.test の結果をキャッシュする）
+}
+
+// NewPublisher は新しいPublisherを作成する
+func NewPublisher(client *slack.Client) *Publisher {
+	return &Publisher{
+		client: client,
+	}
+}
+
+// TeamURL はワークスペースのURL（例: https://example.slack.com/）を返す
+func (p *Publisher) TeamURL(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.teamURL != "" {
+		return p.teamURL, nil
+	}
+	resp, err := p.client.AuthTestContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("ワークスペース情報取得エラー: %w", err)
+	}
+	p.teamURL = resp.URL
+	return p.teamURL, nil
+}
+
+// File は投稿したメッセージに添付するファイル
+type File struct {
+	Name  string // ファイル名（例: emoji.png）
+	Title string // Slackに表示するファイルのタイトル
+	Alt   string // 画像の代替テキスト
+	Data  []byte
+}
+
+// PostBlocks はBlock Kitのメッセージをチャンネルに投稿し、投稿したメッセージのURLを返す
+// text は通知やBlock Kitを表示できない環境で使用される代替テキスト
+// files が指定された場合は、投稿したメッセージのスレッドにアップロードする（files:write スコープが必要）
+func (p *Publisher) PostBlocks(ctx context.Context, channelID string, blocks []slack.Block, text string, files ...File) (string, error) {
+	channel, ts, err := p.client.PostMessageContext(ctx, channelID,
+		slack.MsgOptionBlocks(blocks...),
+		slack.MsgOptionText(text, false),
+		slack.MsgOptionDisableLinkUnfurl(),
+	)
+	if err != nil {
+		return "", fmt.Errorf("メッセージ投稿エラー: %w", err)
+	}
+	url := ""
+	if teamURL, err := p.TeamURL(ctx); err == nil {
+		// URLを取得できない場合も投稿自体は成功しているため、URLは返さずに続行する
+		url = MessageURL(teamURL, channel, ts)
+	}
+
+	for _, file := range files {
+		_, err := p.client.UploadFileV2Context(ctx, slack.UploadFileV2Parameters{
+			Reader:          bytes.NewReader(file.Data),
+			FileSize:        len(file.Data),
+			Filename:        file.Name,
+			Title:           file.Title,
+			AltTxt:          file.Alt,
+			Channel:         channel,
+			ThreadTimestamp: ts,
+		})
+		if err != nil {
+			return url, fmt.Errorf("ファイルのアップロードエラー（%s）: %w", file.Name, err)
+		}
+	}
+	return url, nil
+}
+
+// MessageURL はワークスペースのURL、チャンネルID、メッセージのタイムスタンプからメッセージのURLを作成する
+// 例: https://example.slack.com/archives/C0123/p1700000000123456
+func MessageURL(teamURL, channelID, ts string) string {
+	if teamURL == "" || channelID == "" || ts == "" {
+		return ""
+	}
+	return strings.TrimSuffix(teamURL, "/") + "/archives/" + channelID + "/p" + strings.Replace(ts, ".", "", 1)
+}
@@ -0,0 +1,50 @@
+package slack
+
+import "testing"
+
+func TestMessageURL(t *testing.T) {
+	tests := []struct {
+		name      string
+		teamURL   string
+		channelID string
+		ts        string
+		expected  string
+	}{
+		{
+			name:      "末尾にスラッシュあり",
+			teamURL:   "https://example.slack.com/",
+			channelID: "C0123",
+			ts:        "1700000000.123456",
+			expected:  "https://example.slack.com/archives/C0123/p1700000000123456",
+		},
+		{
+			name:      "末尾にスラッシュなし",
+			teamURL:   "https://example.slack.com",
+			channelID: "C0123",
+			ts:        "1700000000.123456",
+			expected:  "https://example.slack.com/archives/C0123/p1700000000123456",
+		},
+		{
+			name:      "ワークスペースのURLが不明",
+			teamURL:   "",
+			channelID: "C0123",
+			ts:        "1700000000.123456",
+			expected:  "",
+		},
+		{
+			name:      "タイムスタンプなし",
+			teamURL:   "https://example.slack.com/",
+			channelID: "C0123",
+			ts:        "",
+			expected:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MessageURL(tt.teamURL, tt.channelID, tt.ts); got != tt.expected {
+				t.Errorf("MessageURL() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
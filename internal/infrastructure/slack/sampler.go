@@ -0,0 +1,66 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+// SampleHistory はチャンネルの期間内の最新 limit 件の履歴を1回のAPI呼び出しで取得し、件数を返す
+// 全件は取得しないため、長時間の分析の前にAPI呼び出し回数を見積もるために使用する
+func (r *MessageRepository) SampleHistory(ctx context.Context, channelID string, dateRange *domain.DateRange, limit int) (*domain.HistorySample, error) {
+	params := slack.GetConversationHistoryParameters{
+		ChannelID: channelID,
+		Inclusive: true,
+		Limit:     limit,
+	}
+	params.Oldest, params.Latest = timestampBounds(dateRange)
+
+	history, err := r.client.GetConversationHistoryContext(ctx, &params)
+	if err != nil {
+		return nil, fmt.Errorf("メッセージ取得エラー: %w", err)
+	}
+
+	sample := &domain.HistorySample{HasMore: history.HasMore}
+	for _, msg := range history.Messages {
+		timestamp, err := parseSlackTimestamp(msg.Timestamp)
+		if err != nil {
+			continue
+		}
+		sample.Messages++
+		if msg.ReplyCount > 0 {
+			sample.ThreadParents++
+		}
+		if sample.Oldest.IsZero() || timestamp.Before(sample.Oldest) {
+			sample.Oldest = timestamp
+		}
+		if timestamp.After(sample.Newest) {
+			sample.Newest = timestamp
+		}
+	}
+	return sample, nil
+}
+
+// SampleUserSearch はSearch APIでユーザーの投稿を検索し、最初のページから総数とチャンネル数を返す
+func (r *MessageRepository) SampleUserSearch(ctx context.Context, userID string, dateRange *domain.DateRange) (*domain.SearchSample, error) {
+	params := slack.NewSearchParameters()
+	params.Count = searchPageSize
+	params.Page = 1
+
+	results, err := r.client.SearchMessagesContext(ctx, userSearchQuery(userID, dateRange), params)
+	if err != nil {
+		return nil, fmt.Errorf("Search APIエラー: %w", err)
+	}
+
+	channels := make(map[string]bool)
+	for _, match := range results.Matches {
+		channels[match.Channel.ID] = true
+	}
+	return &domain.SearchSample{
+		Total:    results.Total,
+		Channels: len(channels),
+		Complete: results.Paging.Pages <= 1,
+	}, nil
+}
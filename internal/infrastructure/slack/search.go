@@ -0,0 +1,188 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+// searchPageSize はSearch APIの1ページあたりの取得件数（最大100件）
+const searchPageSize = 100
+
+// FindBySearch はSlackの検索クエリ（例: in:#general has:link "release"）に一致するメッセージを取得する
+// Search APIの結果にはリアクションとスレッドの情報が含まれないため、チャンネルの履歴とスレッドの返信から補完する
+// 期間が指定された場合は、クエリに after: / before: を追加する
+// excludedChannels に含まれるチャンネルのメッセージは、補完する前に検索結果から取り除く
+// 補完に必要な履歴を取得できなかったチャンネル・スレッドは警告として返す（そのメッセージはリアクションなしとして扱う）
+func (r *MessageRepository) FindBySearch(ctx context.Context, query string, dateRange *domain.DateRange, excludedChannels map[string]bool) ([]*domain.Message, []string, error) {
+	matches, err := r.searchAll(ctx, withDateFilters(query, dateRange))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages := make([]*domain.Message, 0, len(matches))
+	for i := range matches {
+		msg := r.convertSearchResultToDomainMessage(&matches[i], dateRange)
+		if msg == nil || excludedChannels[msg.ChannelID] {
+			continue
+		}
+		// スレッドの返信かどうかはパーマリンクの thread_ts から判別する（親メッセージは履歴から補完する）
+		msg.ThreadTS = permalinkThreadTS(matches[i].Permalink)
+		messages = append(messages, msg)
+	}
+	fmt.Fprintf(r.progress, "Search API検索完了: 合計 %d件のメッセージが見つかりました\n", len(messages))
+
+	warnings := r.enrichSearchResults(ctx, messages)
+	return messages, warnings, nil
+}
+
+// searchAll はSearch APIで検索結果の全ページを取得する
+func (r *MessageRepository) searchAll(ctx context.Context, query string) ([]slack.SearchMessage, error) {
+	var matches []slack.SearchMessage
+	const maxRetries = 3
+
+	for page := 1; ; page++ {
+		params := slack.NewSearchParameters()
+		params.Count = searchPageSize
+		params.Page = page
+
+		var results *slack.SearchMessages
+		var err error
+
+		// レート制限対応のリトライループ
+		for retry := 0; retry < maxRetries; retry++ {
+			results, err = r.client.SearchMessagesContext(ctx, query, params)
+			if err == nil || !isRateLimitError(err) {
+				break
+			}
+			sleepTime := extractRetryAfter(err.Error())
+			if sleepTime <= 0 {
+				sleepTime = 10 + retry*5
+			}
+			time.Sleep(time.Duration(sleepTime) * time.Second)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Search APIエラー: %w", err)
+		}
+
+		matches = append(matches, results.Matches...)
+		fmt.Fprintf(r.progress, "Search API: ページ %d 処理完了 (累計: %d件)\n", page, len(matches))
+
+		if page >= results.Paging.Pages {
+			return matches, nil
+		}
+	}
+}
+
+// searchSource は検索結果のリアクションを補完するために取得する履歴（チャンネル、またはスレッドの返信）
+type searchSource struct {
+	channelID string
+	threadTS  string // スレッドの返信の場合は親メッセージのタイムスタンプ
+}
+
+// enrichSearchResults は検索結果のメッセージにリアクションとスレッドの情報を補完する
+// スレッドの返信以外はチャンネルごとに検索結果の期間の履歴を、返信はスレッドごとに返信を取得して照合する
+// 取得できなかったチャンネル（参加していないチャンネルなど）のメッセージはリアクションなしとして扱い、警告を返す
+func (r *MessageRepository) enrichSearchResults(ctx context.Context, messages []*domain.Message) []string {
+	groups := make(map[searchSource][]*domain.Message)
+	for _, msg := range messages {
+		source := searchSource{channelID: msg.ChannelID}
+		if msg.IsThreadReply() {
+			source.threadTS = msg.ThreadTS
+		}
+		groups[source] = append(groups[source], msg)
+	}
+
+	fmt.Fprintf(r.progress, "リアクション情報を補完中... (%dチャンネル・スレッド)\n", len(groups))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var warnings []string
+
+	// セマフォで同時実行数を制限（レート制限を考慮して最大10並行）
+	semaphore := make(chan struct{}, 10)
+	for source, group := range groups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}        // セマフォを取得
+			defer func() { <-semaphore }() // セマフォを解放
+
+			var full []*domain.Message
+			var err error
+			if source.threadTS != "" {
+				full, err = r.FindThreadReplies(ctx, source.channelID, source.threadTS, nil)
+			} else {
+				full, err = r.findByChannelSilentWithRetry(ctx, source.channelID, messagesSpan(group))
+			}
+			if err != nil {
+				var warning string
+				if source.threadTS != "" {
+					warning = fmt.Sprintf("スレッド %s の返信を取得できなかったため、%d件の検索結果をリアクションなしとして集計しました: %v", source.threadTS, len(group), err)
+				} else {
+					warning = fmt.Sprintf("チャンネル %s の履歴を取得できなかったため、%d件の検索結果をリアクションなしとして集計しました: %v", source.channelID, len(group), err)
+				}
+				mu.Lock()
+				warnings = append(warnings, warning)
+				mu.Unlock()
+				return
+			}
+
+			// メッセージは1つのグループにのみ含まれるため、ロックせずに更新できる
+			byID := make(map[string]*domain.Message, len(full))
+			for _, msg := range full {
+				byID[msg.ID] = msg
+			}
+			for _, msg := range group {
+				if fullMsg, found := byID[msg.ID]; found {
+					msg.Reactions = fullMsg.Reactions
+					msg.ThreadTS = fullMsg.ThreadTS
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 並行して取得するため、警告の順序を一定にする
+	sort.Strings(warnings)
+	fmt.Fprintf(r.progress, "リアクション情報の補完完了: 合計 %d件のメッセージ\n", len(messages))
+	return warnings
+}
+
+// messagesSpan はメッセージの最も古い投稿から最も新しい投稿までを含む期間を返す
+// 履歴のAPIはタイムスタンプを秒単位で指定するため、前後に1秒の余裕を持たせる
+func messagesSpan(messages []*domain.Message) *domain.DateRange {
+	span := &domain.DateRange{}
+	for _, msg := range messages {
+		if span.Start.IsZero() || msg.Timestamp.Before(span.Start) {
+			span.Start = msg.Timestamp
+		}
+		if msg.Timestamp.After(span.End) {
+			span.End = msg.Timestamp
+		}
+	}
+	if !span.Start.IsZero() {
+		span.Start = span.Start.Add(-time.Second)
+		span.End = span.End.Add(time.Second)
+	}
+	return span
+}
+
+// withDateFilters は検索クエリに期間の after: / before: を追加する
+// Slackの検索では after: と before: に指定した日自体は含まれないため、期間の前日と翌日を指定する
+func withDateFilters(query string, dateRange *domain.DateRange) string {
+	if dateRange == nil {
+		return query
+	}
+	if !dateRange.Start.IsZero() {
+		query += " after:" + dateRange.Start.AddDate(0, 0, -1).Format("2006-01-02")
+	}
+	if !dateRange.End.IsZero() {
+		query += " before:" + dateRange.End.AddDate(0, 0, 1).Format("2006-01-02")
+	}
+	return query
+}
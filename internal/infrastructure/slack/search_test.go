@@ -0,0 +1,95 @@
+package slack
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/slack-go/slack"
+)
+
+func TestWithDateFilters(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		name      string
+		query     string
+		dateRange *domain.DateRange
+		expected  string
+	}{
+		{
+			name:      "期間なし",
+			query:     `in:#general has:link "release"`,
+			dateRange: nil,
+			expected:  `in:#general has:link "release"`,
+		},
+		{
+			name:  "開始日と終了日",
+			query: "from:<@U1>",
+			dateRange: &domain.DateRange{
+				Start: time.Date(2024, 1, 1, 0, 0, 0, 0, jst),
+				End:   time.Date(2024, 1, 31, 23, 59, 59, 0, jst),
+			},
+			expected: "from:<@U1> after:2023-12-31 before:2024-02-01",
+		},
+		{
+			name:      "開始日のみ",
+			query:     "release",
+			dateRange: &domain.DateRange{Start: time.Date(2024, 3, 1, 0, 0, 0, 0, jst)},
+			expected:  "release after:2024-02-29",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := withDateFilters(tt.query, tt.dateRange); got != tt.expected {
+				t.Errorf("withDateFilters() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestFindBySearch_HistoryError は履歴を取得できなかったチャンネルを警告として返し、
+// 除外したチャンネルは履歴を取得せずに検索結果から取り除くことを確認する
+func TestFindBySearch_HistoryError(t *testing.T) {
+	historyCalls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/search.messages":
+			w.Write([]byte(`{"ok":true,"messages":{"matches":[
+				{"type":"message","user":"U1","text":"release v1","ts":"1705280000.000100","channel":{"id":"C1","name":"general"}}
+			],"paging":{"count":100,"total":1,"page":1,"pages":1}}}`))
+		default:
+			historyCalls++
+			w.Write([]byte(`{"ok":false,"error":"not_in_channel"}`))
+		}
+	}))
+	defer server.Close()
+
+	repo := NewMessageRepository(slack.New("xoxp-test", slack.OptionAPIURL(server.URL+"/")))
+	repo.SetProgressOutput(io.Discard)
+	messages, warnings, err := repo.FindBySearch(context.Background(), "release", nil, nil)
+	if err != nil {
+		t.Fatalf("FindBySearch() error = %v", err)
+	}
+	if len(messages) != 1 || len(messages[0].Reactions) != 0 {
+		t.Errorf("messages = %+v, want 1 message without reactions", messages)
+	}
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "チャンネル C1") || !strings.Contains(warnings[0], "参加していません") {
+		t.Errorf("warnings = %+v, want 1 warning for C1", warnings)
+	}
+
+	historyCalls = 0
+	messages, warnings, err = repo.FindBySearch(context.Background(), "release", nil, map[string]bool{"C1": true})
+	if err != nil {
+		t.Fatalf("FindBySearch() error = %v", err)
+	}
+	if len(messages) != 0 || len(warnings) != 0 || historyCalls != 0 {
+		t.Errorf("excluded channel: messages = %+v, warnings = %v, history calls = %d; want none", messages, warnings, historyCalls)
+	}
+}
@@ -2,7 +2,6 @@ package slack
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"sync"
 
@@ -127,5 +126,5 @@ func (r *UserRepository) FindByName(ctx context.Context, name string) (*domain.U
 		}
 	}
 
-	return nil, fmt.Errorf("ユーザー '%s' が見つかりません", name)
+	return nil, &domain.NotFoundError{Kind: "ユーザー", Name: name}
 }
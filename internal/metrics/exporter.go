@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// コンテンツタイプ
+const (
+	ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+	ContentTypeText        = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// Collector は分析を実行して最新のSnapshotを作成する
+type Collector func(ctx context.Context) (*Snapshot, error)
+
+// Exporter は最後に成功した分析結果と、更新の状態をメトリクスとして公開する
+// 更新に失敗した場合は直前のSnapshotを公開し続ける
+type Exporter struct {
+	collect Collector
+	now     func() time.Time
+
+	mu          sync.RWMutex
+	snapshot    *Snapshot
+	lastRefresh time.Time     // 最後に更新を試みた時刻
+	duration    time.Duration // 最後の更新にかかった時間
+	lastSuccess bool
+	refreshes   int
+	errors      int
+}
+
+// NewExporter は新しいExporterを作成する
+func NewExporter(collect Collector) *Exporter {
+	return &Exporter{collect: collect, now: time.Now}
+}
+
+// Refresh は分析を実行してメトリクスを更新する
+func (e *Exporter) Refresh(ctx context.Context) error {
+	start := e.now()
+	snapshot, err := e.collect(ctx)
+	duration := e.now().Sub(start)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.lastRefresh = start
+	e.duration = duration
+	e.refreshes++
+	e.lastSuccess = err == nil
+	if err != nil {
+		e.errors++
+		return err
+	}
+	e.snapshot = snapshot
+	return nil
+}
+
+// Write はメトリクスをテキスト形式で出力する
+// openMetrics がtrueの場合はOpenMetrics形式、falseの場合はPrometheusのテキスト形式（0.0.4）で出力する
+func (e *Exporter) Write(w io.Writer, openMetrics bool) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	mw := &writer{openMetrics: openMetrics}
+	if e.snapshot != nil {
+		mw.writeSnapshot(e.snapshot)
+	}
+
+	mw.family("slack_reaction_last_refresh_timestamp_seconds", "gauge", "最後に分析を実行した時刻（UNIX時間）")
+	if !e.lastRefresh.IsZero() {
+		mw.sample("slack_reaction_last_refresh_timestamp_seconds", nil, float64(e.lastRefresh.UnixMilli())/1000)
+	}
+	mw.family("slack_reaction_refresh_duration_seconds", "gauge", "最後の分析にかかった時間")
+	mw.sample("slack_reaction_refresh_duration_seconds", nil, e.duration.Seconds())
+	mw.family("slack_reaction_last_refresh_success", "gauge", "最後の分析が成功した場合は1")
+	mw.sample("slack_reaction_last_refresh_success", nil, boolValue(e.lastSuccess))
+	mw.counter("slack_reaction_refreshes", "分析を実行した回数", e.refreshes)
+	mw.counter("slack_reaction_refresh_errors", "分析に失敗した回数", e.errors)
+
+	if openMetrics {
+		mw.buf.WriteString("# EOF\n")
+	}
+	_, err := w.Write(mw.buf.Bytes())
+	return err
+}
+
+// ServeHTTP はメトリクスを出力する
+// Acceptヘッダーで application/openmetrics-text を受け付ける場合はOpenMetrics形式、それ以外はPrometheusのテキスト形式で返す
+func (e *Exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+	var buf bytes.Buffer
+	if err := e.Write(&buf, openMetrics); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if openMetrics {
+		w.Header().Set("Content-Type", ContentTypeOpenMetrics)
+	} else {
+		w.Header().Set("Content-Type", ContentTypeText)
+	}
+	w.Write(buf.Bytes())
+}
+
+// WriteTextfile はnode_exporterのtextfile collectorが読み込むファイルにメトリクスを書き込む
+// 書き込み途中のファイルが読み込まれないよう、同じディレクトリの一時ファイルに書き込んでから置き換える
+func (e *Exporter) WriteTextfile(path string) error {
+	var buf bytes.Buffer
+	if err := e.Write(&buf, false); err != nil {
+		return err
+	}
+
+	// 一時ファイルは拡張子を .prom にしない（node_exporterに読み込まれないようにする）
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("メトリクスファイルの作成エラー: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("メトリクスファイルの書き込みエラー: %w", err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("メトリクスファイルの書き込みエラー: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("メトリクスファイルの書き込みエラー: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("メトリクスファイルの置き換えエラー: %w", err)
+	}
+	return nil
+}
+
+// boolValue は真偽値をメトリクスの値（1または0）に変換する
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,213 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+func testResult() *service.MultiChannelResult {
+	return &service.MultiChannelResult{
+		Merged: &service.AnalysisResult{
+			Warnings: []string{"チャンネル #secret を分析から除外しました: not_in_channel"},
+		},
+		Channels: []service.ChannelAnalysis{
+			{
+				Channel: &domain.Channel{ID: "C001", Name: `dev"team`},
+				Result: &service.AnalysisResult{
+					EmojiStats: []domain.EmojiCount{
+						{Emoji: "+1", Count: 5}, {Emoji: "eyes", Count: 3}, {Emoji: "tada", Count: 2}, {Emoji: "pray", Count: 1},
+					},
+					ThreadStats:      []domain.ThreadStats{{ReplyCount: 4}, {ReplyCount: 2}},
+					UserStats:        []domain.UserStats{{UserID: "U1", Count: 6}, {UserID: "U2", Count: 4}},
+					UserMessageCount: map[string]int{"U1": 6, "U2": 4},
+				},
+			},
+		},
+		Failed: []*domain.Channel{{ID: "C002", Name: "secret"}},
+	}
+}
+
+func TestNewSnapshot(t *testing.T) {
+	dateRange := &domain.DateRange{
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		End:   time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+	}
+	snapshot := NewSnapshot(testResult(), dateRange, 2)
+
+	if snapshot.Window != 7*24*time.Hour {
+		t.Errorf("Window = %v, want 168h", snapshot.Window)
+	}
+	if len(snapshot.Channels) != 1 {
+		t.Fatalf("len(Channels) = %d, want 1", len(snapshot.Channels))
+	}
+	ch := snapshot.Channels[0]
+	if ch.Messages != 10 || ch.Reactions != 11 || ch.Threads != 2 || ch.ThreadReplies != 6 || ch.ActiveUsers != 2 {
+		t.Errorf("ChannelSample = %+v", ch)
+	}
+	if len(snapshot.FailedChannels) != 1 || snapshot.FailedChannels[0].ID != "C002" {
+		t.Errorf("FailedChannels = %+v, want C002", snapshot.FailedChannels)
+	}
+	if snapshot.Warnings != 1 {
+		t.Errorf("Warnings = %d, want 1", snapshot.Warnings)
+	}
+	want := []domain.EmojiCount{{Emoji: "+1", Count: 5}, {Emoji: "eyes", Count: 3}, {Emoji: OtherEmoji, Count: 3}}
+	if len(ch.Emoji) != len(want) {
+		t.Fatalf("Emoji = %+v, want %+v", ch.Emoji, want)
+	}
+	for i := range want {
+		if ch.Emoji[i] != want[i] {
+			t.Errorf("Emoji[%d] = %+v, want %+v", i, ch.Emoji[i], want[i])
+		}
+	}
+}
+
+func TestExporter_Write(t *testing.T) {
+	snapshot := NewSnapshot(testResult(), nil, 10)
+	fail := false
+	e := NewExporter(func(ctx context.Context) (*Snapshot, error) {
+		if fail {
+			return nil, errors.New("API error")
+		}
+		return snapshot, nil
+	})
+	e.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	if err := e.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	fail = true
+	if err := e.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() error = nil, want error")
+	}
+
+	tests := []struct {
+		name        string
+		openMetrics bool
+		contains    []string
+		excludes    []string
+	}{
+		{
+			name:        "Prometheusのテキスト形式",
+			openMetrics: false,
+			contains: []string{
+				"# TYPE slack_reaction_messages gauge\n",
+				`slack_reaction_messages{channel="dev\"team",channel_id="C001"} 10` + "\n",
+				`slack_reaction_emoji_reactions{channel="dev\"team",channel_id="C001",emoji="+1"} 5` + "\n",
+				`slack_reaction_channel_fetch_error{channel="dev\"team",channel_id="C001"} 0` + "\n",
+				`slack_reaction_channel_fetch_error{channel="secret",channel_id="C002"} 1` + "\n",
+				"slack_reaction_warnings 1\n",
+				"# TYPE slack_reaction_refreshes_total counter\n",
+				"slack_reaction_refreshes_total 2\n",
+				"slack_reaction_refresh_errors_total 1\n",
+				"slack_reaction_last_refresh_success 0\n",
+				"slack_reaction_last_refresh_timestamp_seconds 1.7e+09\n",
+			},
+			excludes: []string{"# EOF"},
+		},
+		{
+			name:        "OpenMetrics形式",
+			openMetrics: true,
+			contains: []string{
+				"# TYPE slack_reaction_refreshes counter\n",
+				"slack_reaction_refreshes_total 2\n",
+				`slack_reaction_active_users{channel="dev\"team",channel_id="C001"} 2` + "\n",
+			},
+			excludes: []string{"# TYPE slack_reaction_refreshes_total"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := e.Write(&b, tt.openMetrics); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			got := b.String()
+			for _, s := range tt.contains {
+				if !strings.Contains(got, s) {
+					t.Errorf("output does not contain %q\n%s", s, got)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(got, s) {
+					t.Errorf("output contains %q\n%s", s, got)
+				}
+			}
+			if tt.openMetrics && !strings.HasSuffix(got, "# EOF\n") {
+				t.Errorf("output does not end with # EOF\n%s", got)
+			}
+		})
+	}
+}
+
+func TestExporter_ServeHTTP(t *testing.T) {
+	e := NewExporter(func(ctx context.Context) (*Snapshot, error) {
+		return NewSnapshot(testResult(), nil, 10), nil
+	})
+	if err := e.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{name: "Acceptヘッダーなし", accept: "", contentType: ContentTypeText},
+		{name: "OpenMetricsを受け付ける", accept: "application/openmetrics-text;version=1.0.0,text/plain;version=0.0.4;q=0.5", contentType: ContentTypeOpenMetrics},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if rec.Code != http.StatusOK {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestExporter_WriteTextfile(t *testing.T) {
+	e := NewExporter(func(ctx context.Context) (*Snapshot, error) {
+		return NewSnapshot(testResult(), nil, 10), nil
+	})
+	if err := e.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "slack_reaction.prom")
+	if err := e.WriteTextfile(path); err != nil {
+		t.Fatalf("WriteTextfile() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if !strings.Contains(string(data), "slack_reaction_messages{") || strings.Contains(string(data), "# EOF") {
+		t.Errorf("unexpected textfile content:\n%s", data)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("temporary files are left: %v", entries)
+	}
+}
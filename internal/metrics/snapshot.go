@@ -0,0 +1,75 @@
+// Package metrics は分析結果をPrometheus/OpenMetrics形式のメトリクスとして公開する
+package metrics
+
+import (
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// OtherEmoji はチャンネルごとの上位に入らなかったスタンプをまとめるラベル値
+const OtherEmoji = "(other)"
+
+// ChannelSample はチャンネルごとのメトリクスの値
+type ChannelSample struct {
+	ID            string
+	Name          string
+	Messages      int
+	Reactions     int
+	Threads       int                 // 返信がついたスレッド数
+	ThreadReplies int                 // スレッドの返信数の合計
+	ActiveUsers   int                 // 投稿したユーザー数
+	Emoji         []domain.EmojiCount // 使用回数の多い順（上位以外は OtherEmoji にまとめる）
+}
+
+// Snapshot はある時点の分析結果から作成したメトリクスの値
+type Snapshot struct {
+	Channels       []ChannelSample
+	FailedChannels []ChannelSample // 取得に失敗して分析から除外したチャンネル（ID と Name のみ）
+	Warnings       int             // 分析は続行したが一部のデータを取得できなかった警告の数
+	Window         time.Duration   // 分析した期間の長さ
+}
+
+// NewSnapshot は複数チャンネルの分析結果からSnapshotを作成する
+// ラベルの組み合わせが増えすぎないよう、スタンプはチャンネルごとに使用回数の上位 maxEmoji 種類までとし、
+// 残りは OtherEmoji にまとめる。ユーザーやメッセージはラベルに含めない
+func NewSnapshot(result *service.MultiChannelResult, dateRange *domain.DateRange, maxEmoji int) *Snapshot {
+	snapshot := &Snapshot{}
+	if dateRange != nil && !dateRange.Start.IsZero() && !dateRange.End.IsZero() {
+		snapshot.Window = dateRange.End.Sub(dateRange.Start)
+	}
+	for _, ch := range result.Channels {
+		sample := ChannelSample{
+			ID:          ch.Channel.ID,
+			Name:        ch.Channel.Name,
+			Messages:    ch.Result.TotalMessages(),
+			Reactions:   ch.Result.TotalReactions(),
+			Threads:     len(ch.Result.ThreadStats),
+			ActiveUsers: len(ch.Result.UserStats),
+			Emoji:       boundEmoji(ch.Result.EmojiStats, maxEmoji),
+		}
+		for _, stat := range ch.Result.ThreadStats {
+			sample.ThreadReplies += stat.ReplyCount
+		}
+		snapshot.Channels = append(snapshot.Channels, sample)
+	}
+	for _, ch := range result.Failed {
+		snapshot.FailedChannels = append(snapshot.FailedChannels, ChannelSample{ID: ch.ID, Name: ch.Name})
+	}
+	snapshot.Warnings = len(result.Merged.Warnings)
+	return snapshot
+}
+
+// boundEmoji は使用回数の多い順のスタンプを上位 limit 種類に絞り、残りの回数を OtherEmoji にまとめる
+func boundEmoji(stats []domain.EmojiCount, limit int) []domain.EmojiCount {
+	if len(stats) <= limit {
+		return stats
+	}
+	bounded := append([]domain.EmojiCount(nil), stats[:limit]...)
+	other := domain.EmojiCount{Emoji: OtherEmoji}
+	for _, stat := range stats[limit:] {
+		other.Count += stat.Count
+	}
+	return append(bounded, other)
+}
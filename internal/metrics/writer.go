@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// label はメトリクスのラベル
+type label struct {
+	name  string
+	value string
+}
+
+// writer はメトリクスをテキスト形式で書き込む
+type writer struct {
+	buf         bytes.Buffer
+	openMetrics bool
+}
+
+// writeSnapshot はチャンネルごとのメトリクスを書き込む
+func (w *writer) writeSnapshot(s *Snapshot) {
+	channelGauges := []struct {
+		name  string
+		help  string
+		value func(ChannelSample) int
+	}{
+		{"slack_reaction_messages", "分析期間内のメッセージ数（ボット・除外ユーザーを除く）", func(c ChannelSample) int { return c.Messages }},
+		{"slack_reaction_reactions", "分析期間内のメッセージについたリアクションの総数", func(c ChannelSample) int { return c.Reactions }},
+		{"slack_reaction_threads", "分析期間内の返信がついたスレッド数", func(c ChannelSample) int { return c.Threads }},
+		{"slack_reaction_thread_replies", "分析期間内のスレッドの返信数の合計", func(c ChannelSample) int { return c.ThreadReplies }},
+		{"slack_reaction_active_users", "分析期間内に投稿したユーザー数", func(c ChannelSample) int { return c.ActiveUsers }},
+	}
+	for _, gauge := range channelGauges {
+		w.family(gauge.name, "gauge", gauge.help)
+		for _, ch := range s.Channels {
+			w.sample(gauge.name, channelLabels(ch), float64(gauge.value(ch)))
+		}
+	}
+
+	w.family("slack_reaction_emoji_reactions", "gauge", "分析期間内のスタンプごとの使用回数（チャンネルごとの上位以外は "+OtherEmoji+" にまとめる）")
+	for _, ch := range s.Channels {
+		for _, stat := range ch.Emoji {
+			w.sample("slack_reaction_emoji_reactions", append(channelLabels(ch), label{"emoji", stat.Emoji}), float64(stat.Count))
+		}
+	}
+
+	// 取得に失敗したチャンネルは他のメトリクスに含まれないため、失敗したことを明示する
+	w.family("slack_reaction_channel_fetch_error", "gauge", "チャンネルのメッセージの取得に失敗して分析から除外した場合は1")
+	for _, ch := range s.Channels {
+		w.sample("slack_reaction_channel_fetch_error", channelLabels(ch), 0)
+	}
+	for _, ch := range s.FailedChannels {
+		w.sample("slack_reaction_channel_fetch_error", channelLabels(ch), 1)
+	}
+
+	w.family("slack_reaction_warnings", "gauge", "最後に成功した分析で一部のデータを取得できなかった警告の数")
+	w.sample("slack_reaction_warnings", nil, float64(s.Warnings))
+
+	w.family("slack_reaction_window_seconds", "gauge", "分析期間の長さ")
+	w.sample("slack_reaction_window_seconds", nil, s.Window.Seconds())
+}
+
+// family はメトリクスの説明と種類を書き込む
+func (w *writer) family(name, typ, help string) {
+	w.buf.WriteString("# HELP " + name + " " + escapeHelp(help) + "\n")
+	w.buf.WriteString("# TYPE " + name + " " + typ + "\n")
+}
+
+// counter はカウンターを書き込む
+// OpenMetrics形式ではメトリクス名に _total を付けず、値の名前にだけ付ける
+func (w *writer) counter(name, help string, value int) {
+	if w.openMetrics {
+		w.family(name, "counter", help)
+	} else {
+		w.family(name+"_total", "counter", help)
+	}
+	w.sample(name+"_total", nil, float64(value))
+}
+
+// sample は1つの値を書き込む
+func (w *writer) sample(name string, labels []label, value float64) {
+	w.buf.WriteString(name)
+	if len(labels) > 0 {
+		w.buf.WriteByte('{')
+		for i, l := range labels {
+			if i > 0 {
+				w.buf.WriteByte(',')
+			}
+			w.buf.WriteString(l.name + `="` + escapeLabel(l.value) + `"`)
+		}
+		w.buf.WriteByte('}')
+	}
+	w.buf.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
+
+// channelLabels はチャンネルを表すラベルを返す
+func channelLabels(ch ChannelSample) []label {
+	return []label{{"channel", ch.Name}, {"channel_id", ch.ID}}
+}
+
+// escapeLabel はラベルの値の \ " 改行をエスケープする
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// escapeHelp は説明文の \ 改行をエスケープする
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
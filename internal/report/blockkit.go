@@ -0,0 +1,144 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/slack-go/slack"
+)
+
+// maxSectionTextRunes はBlock Kitのセクションに含められる最大文字数
+const maxSectionTextRunes = 3000
+
+// maxHeaderTextRunes はBlock Kitのヘッダーに含められる最大文字数
+const maxHeaderTextRunes = 150
+
+// MessageLinker はチャンネルIDとメッセージのタイムスタンプからメッセージのURLを返す
+// 空文字列を返した場合はリンクにしない
+type MessageLinker func(channelID, ts string) string
+
+// ChannelBlocks はチャンネル分析結果をBlock Kitのブロックに変換する
+func ChannelBlocks(title string, dateRange *domain.DateRange, result *service.AnalysisResult, limits Limits, link MessageLinker) []slack.Block {
+	blocks := headerBlocks(title, dateRange, fmt.Sprintf("投稿数: %d件 / スタンプ数: %d回", result.TotalMessages(), result.TotalReactions()))
+	return append(blocks, channelSectionBlocks(result, limits, link)...)
+}
+
+// MultiChannelBlocks は複数チャンネル・ワークスペースの分析結果をBlock Kitのブロックに変換する
+// 合算したランキングの前に、チャンネルごとの活動量を表示する
+func MultiChannelBlocks(title string, dateRange *domain.DateRange, result *service.MultiChannelResult, limits Limits, link MessageLinker) []slack.Block {
+	blocks := headerBlocks(title, dateRange, fmt.Sprintf("%dチャンネル / 投稿数: %d件 / スタンプ数: %d回", len(result.Channels), result.Merged.TotalMessages(), result.Merged.TotalReactions()))
+
+	var lines []string
+	for i, activity := range topChannels(result, limits) {
+		lines = append(lines, fmt.Sprintf("%d. <#%s> - %d投稿 / %dスタンプ", i+1, activity.Channel.ID, activity.Messages, activity.Reactions))
+	}
+	blocks = append(blocks, rankingSection("最も投稿数が多いチャンネル", lines))
+
+	return append(blocks, channelSectionBlocks(result.Merged, limits, link)...)
+}
+
+// UserBlocks はユーザー分析結果をBlock Kitのブロックに変換する
+func UserBlocks(dateRange *domain.DateRange, result *service.UserAnalysisResult, limits Limits, link MessageLinker) []slack.Block {
+	blocks := headerBlocks("ユーザー分析結果: "+result.UserName, dateRange, fmt.Sprintf("<@%s> / 投稿総数: %d件 / スタンプ総数: %d回", result.UserID, result.TotalMessages, result.TotalReactions))
+
+	var threads []string
+	for i, stat := range head(result.ThreadStats, limits.Thread) {
+		threads = append(threads, fmt.Sprintf("%d. %s - %dコメント", i+1, messageLink(stat.Text, stat.ChannelID, stat.MessageID, link), stat.ReplyCount))
+	}
+	blocks = append(blocks, rankingSection("投稿についたコメントが多いスレッド", threads))
+
+	var emoji []string
+	for i, stat := range head(result.ReactionRanking, limits.Emoji) {
+		emoji = append(emoji, fmt.Sprintf("%d. :%s: - %d回", i+1, stat.Emoji, stat.Count))
+	}
+	return append(blocks, rankingSection("投稿についたスタンプ", emoji))
+}
+
+// headerBlocks はタイトル、期間と概要のブロックを作成する
+// タイトルがヘッダーの最大文字数を超える場合は切り詰める（チャンネル名やユーザー名が長い場合に投稿が失敗しないようにする）
+func headerBlocks(title string, dateRange *domain.DateRange, summary string) []slack.Block {
+	if runes := []rune(title); len(runes) > maxHeaderTextRunes {
+		title = string(runes[:maxHeaderTextRunes-1]) + "…"
+	}
+	return []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, title, true, false)),
+		slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("期間: %s / %s", FormatDateRange(dateRange), summary), false, false)),
+		slack.NewDividerBlock(),
+	}
+}
+
+// channelSectionBlocks はスタンプ・メッセージ・投稿者・スレッドのランキングのブロックを作成する
+func channelSectionBlocks(result *service.AnalysisResult, limits Limits, link MessageLinker) []slack.Block {
+	var emoji []string
+	for i, stat := range head(result.EmojiStats, limits.Emoji) {
+		emoji = append(emoji, fmt.Sprintf("%d. :%s: - %d回", i+1, stat.Emoji, stat.Count))
+	}
+
+	var messages []string
+	for i, stat := range head(result.MessageStats, limits.Message) {
+		messages = append(messages, fmt.Sprintf("%d. %s%s - %dリアクション", i+1, messageLink(stat.Text, stat.ChannelID, stat.MessageID, link), mention(stat.UserID), stat.Reactions))
+	}
+
+	var users []string
+	for i, stat := range head(result.UserStats, limits.User) {
+		users = append(users, fmt.Sprintf("%d. <@%s> - %d投稿", i+1, stat.UserID, stat.Count))
+	}
+
+	var threads []string
+	for i, stat := range head(result.ThreadStats, limits.Thread) {
+		threads = append(threads, fmt.Sprintf("%d. %s%s - %dコメント", i+1, messageLink(stat.Text, stat.ChannelID, stat.MessageID, link), mention(stat.UserID), stat.ReplyCount))
+	}
+
+	return []slack.Block{
+		rankingSection("最も使用されたスタンプ", emoji),
+		rankingSection("最もリアクションがついたメッセージ", messages),
+		rankingSection("最も投稿数が多いユーザー", users),
+		rankingSection("最もスレッドのコメント数が多い投稿", threads),
+	}
+}
+
+// rankingSection は見出しとランキングの行からセクションブロックを作成する
+// セクションの文字数の上限を超える場合は、上限に収まるところまでの行を表示する
+func rankingSection(heading string, lines []string) slack.Block {
+	if len(lines) == 0 {
+		lines = []string{"_該当なし_"}
+	}
+	text := "*" + heading + "*"
+	for _, line := range lines {
+		if len([]rune(text))+1+len([]rune(line)) > maxSectionTextRunes {
+			break
+		}
+		text += "\n" + line
+	}
+	return slack.NewSectionBlock(slack.NewTextBlockObject(slack.MarkdownType, text, false, false), nil, nil)
+}
+
+// messageLink はメッセージのプレビューを、リンクを作成できる場合はメッセージへのリンクにする
+func messageLink(text, channelID, ts string, link MessageLinker) string {
+	preview := escapeMrkdwn(Preview(text))
+	if preview == "" {
+		preview = "（本文なし）"
+	}
+	if link == nil {
+		return preview
+	}
+	if url := link(channelID, ts); url != "" {
+		return "<" + url + "|" + strings.ReplaceAll(preview, "|", "｜") + ">"
+	}
+	return preview
+}
+
+// mention は投稿者へのメンションを付記する（投稿者が不明な場合は空文字列）
+func mention(userID string) string {
+	if userID == "" {
+		return ""
+	}
+	return " (<@" + userID + ">)"
+}
+
+// escapeMrkdwn はSlackのmrkdwnで制御文字として扱われる &, <, > をエスケープする
+func escapeMrkdwn(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
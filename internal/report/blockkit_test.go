@@ -0,0 +1,93 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/slack-go/slack"
+)
+
+func TestChannelBlocks(t *testing.T) {
+	result := &service.AnalysisResult{
+		EmojiStats: []domain.EmojiCount{
+			{Emoji: "+1", Count: 45},
+		},
+		MessageStats: []domain.MessageReaction{
+			{Text: "<script> & リリース | 告知", Reactions: 15, UserID: "U1", ChannelID: "C1", MessageID: "1700000000.123456"},
+		},
+		UserStats: []domain.UserStats{
+			{UserID: "U1", UserName: "田中太郎", Count: 156},
+		},
+	}
+	link := func(channelID, ts string) string {
+		return "https://example.slack.com/archives/" + channelID + "/p" + strings.Replace(ts, ".", "", 1)
+	}
+
+	blocks := ChannelBlocks("#general の分析結果", nil, result, DefaultChannelLimits, link)
+	if len(blocks) != 7 {
+		t.Fatalf("len(blocks) = %d, want 7", len(blocks))
+	}
+	if blocks[0].BlockType() != slack.MBTHeader {
+		t.Errorf("blocks[0] type = %s, want header", blocks[0].BlockType())
+	}
+
+	data, err := json.Marshal(blocks)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var texts []string
+	for _, block := range blocks[3:] {
+		texts = append(texts, block.(*slack.SectionBlock).Text.Text)
+	}
+	got := strings.Join(texts, "\n")
+
+	for _, want := range []string{
+		"*最も使用されたスタンプ*\n1. :+1: - 45回",
+		"1. <https://example.slack.com/archives/C1/p1700000000123456|&lt;script&gt; &amp; リリース ｜ 告知> (<@U1>) - 15リアクション",
+		"1. <@U1> - 156投稿",
+		"*最もスレッドのコメント数が多い投稿*\n_該当なし_",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("blocks do not contain %q\n%s", want, got)
+		}
+	}
+	if !strings.Contains(string(data), "期間: 全期間") {
+		t.Errorf("context block does not contain the period\n%s", data)
+	}
+}
+
+func TestRankingSection_Limit(t *testing.T) {
+	lines := make([]string, 100)
+	for i := range lines {
+		lines[i] = strings.Repeat("あ", 50)
+	}
+
+	block := rankingSection("見出し", lines).(*slack.SectionBlock)
+	if n := len([]rune(block.Text.Text)); n > maxSectionTextRunes {
+		t.Errorf("section text has %d runes, want <= %d", n, maxSectionTextRunes)
+	}
+}
+
+func TestHeaderBlocks_Limit(t *testing.T) {
+	title := strings.Repeat("あ", 200)
+
+	block := headerBlocks(title, nil, "")[0].(*slack.HeaderBlock)
+	if n := len([]rune(block.Text.Text)); n != maxHeaderTextRunes {
+		t.Errorf("header text has %d runes, want %d", n, maxHeaderTextRunes)
+	}
+	if !strings.HasSuffix(block.Text.Text, "…") {
+		t.Errorf("header text = %q, want to end with an ellipsis", block.Text.Text)
+	}
+}
+
+func TestMessageLink_NoLinker(t *testing.T) {
+	if got := messageLink("", "C1", "1.2", nil); got != "（本文なし）" {
+		t.Errorf("messageLink() = %q, want %q", got, "（本文なし）")
+	}
+	if got := messageLink("a > b", "C1", "1.2", func(string, string) string { return "" }); got != "a &gt; b" {
+		t.Errorf("messageLink() = %q, want %q", got, "a &gt; b")
+	}
+}
@@ -0,0 +1,215 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/Tattsum/slack-reaction/internal/textwidth"
+)
+
+// DefaultTextWidth は端末の幅がわからない場合に、グラフと本文を収める幅（桁数）
+const DefaultTextWidth = 80
+
+// minBarWidth は横棒グラフの棒の最小の幅（端末の幅が狭い場合もこの幅は確保する）
+const minBarWidth = 10
+
+// TextOptions はテキスト形式の出力のオプション
+type TextOptions struct {
+	Charts bool // ランキングを横棒グラフ、推移をスパークラインで表示し、本文を端末の幅に収める
+	Width  int  // グラフと本文を収める幅（桁数、0の場合は DefaultTextWidth）
+}
+
+// lineWidth はグラフと本文を収める幅を返す
+func (o TextOptions) lineWidth() int {
+	if o.Width <= 0 {
+		return DefaultTextWidth
+	}
+	return o.Width
+}
+
+// sparkLevels はスパークラインの8段階の棒
+var sparkLevels = []rune("▁▂▃▄▅▆▇█")
+
+// barEighths は横棒グラフの棒の端に使う1/8〜7/8の幅の棒
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// Sparkline は値の推移を1行の棒（▁〜█）で表す
+// 最大値を █ とし、0より大きい値は最も低い ▁ より高い棒にする
+// 値の数が width を超える場合は、隣り合う値を合計して width 個にまとめる
+func Sparkline(values []int, width int) string {
+	if width > 0 && len(values) > width {
+		merged := make([]int, width)
+		for i, v := range values {
+			merged[i*width/len(values)] += v
+		}
+		values = merged
+	}
+
+	maxValue := 0
+	for _, v := range values {
+		maxValue = max(maxValue, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if maxValue > 0 && v > 0 {
+			level = (v*(len(sparkLevels)-1) + maxValue - 1) / maxValue
+		}
+		b.WriteRune(sparkLevels[level])
+	}
+	return b.String()
+}
+
+// bar は最大値に対する値の割合の長さの棒を、width 桁（1/8桁単位）で表す
+func bar(value, maxValue, width int) string {
+	if maxValue <= 0 || value <= 0 {
+		return strings.Repeat(" ", width)
+	}
+	eighths := (value*width*8 + maxValue/2) / maxValue
+	if eighths == 0 {
+		eighths = 1
+	}
+	s := strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+	return textwidth.Pad(s, width)
+}
+
+// chartRow はランキングの1行（横棒グラフ・メッセージの一覧）
+type chartRow struct {
+	label  string // スタンプ名・ユーザー名・メッセージの本文
+	value  int
+	suffix string // 本文の後に付記する内容（チャンネル名など）
+}
+
+// barChart は順位・ラベル・棒・値の列を揃えた横棒グラフを作成する
+// ラベルは幅の1/3まで表示し、超える場合は切り詰める
+func barChart(rows []chartRow, unit string, width int) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	rankWidth := textwidth.Width(fmt.Sprintf("%d位", len(rows)))
+	labelWidth, valueWidth, maxValue := 0, 0, 0
+	for _, row := range rows {
+		labelWidth = max(labelWidth, textwidth.Width(row.label))
+		valueWidth = max(valueWidth, textwidth.Width(fmt.Sprintf("%d%s", row.value, unit)))
+		maxValue = max(maxValue, row.value)
+	}
+	labelWidth = min(labelWidth, width/3)
+	barWidth := max(width-rankWidth-labelWidth-valueWidth-3, minBarWidth)
+
+	var b strings.Builder
+	for i, row := range rows {
+		fmt.Fprintf(&b, "%s %s %s %s\n",
+			textwidth.PadLeft(fmt.Sprintf("%d位", i+1), rankWidth),
+			textwidth.Pad(textwidth.Truncate(row.label, labelWidth), labelWidth),
+			bar(row.value, maxValue, barWidth),
+			textwidth.PadLeft(fmt.Sprintf("%d%s", row.value, unit), valueWidth))
+	}
+	return b.String()
+}
+
+// messageLines は順位・件数・本文の列を揃えたメッセージのランキングを作成する
+// 本文は1行にまとめ、付記する内容とあわせて幅に収まるよう切り詰める
+func messageLines(rows []chartRow, unit string, width int) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	rankWidth := textwidth.Width(fmt.Sprintf("%d位", len(rows)))
+	valueWidth := 0
+	for _, row := range rows {
+		valueWidth = max(valueWidth, textwidth.Width(fmt.Sprintf("%d%s", row.value, unit)))
+	}
+
+	var b strings.Builder
+	for i, row := range rows {
+		text := strings.Join(strings.Fields(row.label), " ")
+		if text == "" {
+			text = "（本文なし）"
+		}
+		prefix := textwidth.PadLeft(fmt.Sprintf("%d位", i+1), rankWidth) + " " + textwidth.PadLeft(fmt.Sprintf("%d%s", row.value, unit), valueWidth) + "  "
+		available := max(width-textwidth.Width(prefix)-textwidth.Width(row.suffix), minBarWidth)
+		b.WriteString(prefix + textwidth.Truncate(text, available) + row.suffix + "\n")
+	}
+	return b.String()
+}
+
+// emojiRows はスタンプのランキングの先頭n件を横棒グラフの行に変換する
+func emojiRows(stats []domain.EmojiCount, n int) []chartRow {
+	var rows []chartRow
+	for _, stat := range head(stats, n) {
+		rows = append(rows, chartRow{label: ":" + stat.Emoji + ":", value: stat.Count})
+	}
+	return rows
+}
+
+// userRows はユーザーのランキングの先頭n件を横棒グラフの行に変換する
+func userRows(stats []domain.UserStats, n int) []chartRow {
+	var rows []chartRow
+	for _, stat := range head(stats, n) {
+		rows = append(rows, chartRow{label: stat.UserName, value: stat.Count})
+	}
+	return rows
+}
+
+// Activity は投稿数の推移
+type Activity struct {
+	Granularity service.Granularity      // 集計した期間の単位
+	Periods     []service.ActivityPeriod // 期間ごとの投稿数（投稿のない期間も含む）
+	Counts      []int                    // 期間ごとの投稿数（Periods の Messages）
+	Max         int                      // 1期間の最大の投稿数
+	Total       int                      // 投稿数の合計
+}
+
+// newActivity はメッセージの投稿数の推移を集計する
+func newActivity(messages []*domain.Message, dateRange *domain.DateRange) *Activity {
+	activity := &Activity{}
+	activity.Granularity, activity.Periods = service.ActivityTimeline(messages, dateRange)
+	for _, period := range activity.Periods {
+		activity.Counts = append(activity.Counts, period.Messages)
+		activity.Max = max(activity.Max, period.Messages)
+		activity.Total += period.Messages
+	}
+	return activity
+}
+
+// Range は推移の最初と最後の期間を表す（例: 2024-01-01 〜 2024-01-07）
+func (a *Activity) Range() string {
+	if len(a.Periods) == 0 {
+		return ""
+	}
+	return periodRange(a.Periods[0].Start, a.Periods[len(a.Periods)-1].Start, a.Granularity)
+}
+
+// timelinePoint はスパークラインで表す推移の1期間
+type timelinePoint struct {
+	start time.Time
+	value int
+}
+
+// writeSparkline は推移をスパークラインと、期間・最大値・合計の行で書き込む（推移が空の場合は何も書き込まない）
+func writeSparkline(b *strings.Builder, granularity service.Granularity, points []timelinePoint, unit string, width int) {
+	if len(points) == 0 {
+		return
+	}
+	values := make([]int, len(points))
+	maxValue, total := 0, 0
+	for i, point := range points {
+		values[i] = point.value
+		maxValue = max(maxValue, point.value)
+		total += point.value
+	}
+	fmt.Fprintf(b, "%s\n%s / 最大 %d%s / 合計 %d%s\n",
+		Sparkline(values, width), periodRange(points[0].start, points[len(points)-1].start, granularity), maxValue, unit, total, unit)
+}
+
+// periodRange は推移の最初と最後の期間を表す
+func periodRange(first, last time.Time, granularity service.Granularity) string {
+	return periodLabel(first, granularity) + " 〜 " + periodLabel(last, granularity)
+}
+
+// periodLabel は期間の開始日時を期間の単位に応じた形式で表す（週別は週の開始日）
+func periodLabel(start time.Time, granularity service.Granularity) string {
+	return strings.TrimSuffix(formatPeriod(start, granularity), "〜")
+}
@@ -0,0 +1,101 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/textwidth"
+)
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []int
+		width  int
+		want   string
+	}{
+		{name: "最大値が█", values: []int{0, 1, 4, 8}, want: "▁▂▅█"},
+		{name: "0より大きい値は▁より高い", values: []int{1, 100}, want: "▂█"},
+		{name: "すべて0", values: []int{0, 0}, want: "▁▁"},
+		{name: "幅に収まるようにまとめる", values: []int{1, 1, 0, 0, 2, 2}, width: 3, want: "▅▁█"},
+		{name: "空", values: nil, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("Sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBarChart(t *testing.T) {
+	rows := []chartRow{
+		{label: "田中太郎", value: 20},
+		{label: "bob", value: 10},
+		{label: "とても長い名前のユーザーさん", value: 1},
+	}
+	got := barChart(rows, "投稿", 40)
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("barChart() = %q, want 3 lines", got)
+	}
+	for _, line := range lines {
+		if w := textwidth.Width(line); w != 40 {
+			t.Errorf("textwidth.Width(%q) = %d, want 40", line, w)
+		}
+	}
+	if !strings.HasPrefix(lines[0], "1位 田中太郎      ██████████") || !strings.HasSuffix(lines[0], " 20投稿") {
+		t.Errorf("line 1 = %q", lines[0])
+	}
+	if !strings.HasPrefix(lines[2], "3位 とても長い名… ▊ ") {
+		t.Errorf("line 3 = %q, want truncated label", lines[2])
+	}
+}
+
+func TestMessageLines(t *testing.T) {
+	rows := []chartRow{
+		{label: "新機能の\nリリースについてのお知らせです", value: 15, suffix: " (#general)"},
+		{label: "", value: 3},
+	}
+	got := messageLines(rows, "回", 36)
+	want := "1位 15回  新機能の リリ… (#general)\n2位  3回  （本文なし）\n"
+	if got != want {
+		t.Errorf("messageLines() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteText_Charts(t *testing.T) {
+	data := testTemplateData()
+	data.Result.Messages = []*domain.Message{
+		{UserID: "U1", Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)},
+		{UserID: "U1", Timestamp: time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC)},
+		{UserID: "U1", Timestamp: time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC)},
+	}
+	data.TextOptions = TextOptions{Charts: true, Width: 60}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, data); err != nil {
+		t.Fatalf("WriteText() error = %v", err)
+	}
+	got := buf.String()
+	for _, want := range []string{
+		"===== 投稿数の推移（日別） =====\n▅▁█\n2024-01-01 〜 2024-01-03 / 最大 2件 / 合計 3件\n\n",
+		"===== 最も使用されたスタンプ TOP2 =====\n1位 :+1:   ",
+		" 1500回\n2位 :eyes: ",
+		"===== 最もリアクションがついたメッセージ TOP1 =====\n1位 15回  <b>新機能</b>のリリースについてのお知らせです\n",
+		"1位 田中太郎 ",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+	for _, line := range strings.Split(got, "\n") {
+		if w := textwidth.Width(line); w > 60 {
+			t.Errorf("line %q is %d columns, want <= 60", line, w)
+		}
+	}
+}
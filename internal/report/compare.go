@@ -0,0 +1,99 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// DefaultCompareLimits は期間比較のデフォルト表示件数
+var DefaultCompareLimits = Limits{Emoji: 10, User: 10}
+
+// WriteComparisonText は2つの期間の比較結果をテキスト形式で出力する
+// title は比較対象（例: #general、ユーザー名）を表す
+func WriteComparisonText(w io.Writer, title string, cmp *service.Comparison, limits Limits) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "##### 期間比較: %s #####\n", title)
+	fmt.Fprintf(&b, "比較元: %s\n", FormatDateRange(cmp.BaseRange))
+	fmt.Fprintf(&b, "比較先: %s\n\n", FormatDateRange(cmp.CurrentRange))
+
+	b.WriteString("===== 全体 =====\n")
+	for _, d := range cmp.Summary {
+		fmt.Fprintf(&b, "%s: %s\n", d.Label, formatDelta(d, ""))
+	}
+	b.WriteString("\n")
+
+	b.WriteString("===== スレッド =====\n")
+	for _, d := range cmp.Threads {
+		fmt.Fprintf(&b, "%s: %s\n", d.Label, formatDelta(d, ""))
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "===== 増減が大きいスタンプ TOP%d =====\n", limits.Emoji)
+	writeDeltaRanking(&b, cmp.Emoji, limits.Emoji, "回")
+
+	if len(cmp.Users) > 0 {
+		fmt.Fprintf(&b, "===== 投稿数の増減が大きいユーザー TOP%d =====\n", limits.User)
+		writeDeltaRanking(&b, cmp.Users, limits.User, "投稿")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeDeltaRanking は増減のランキングを書き込む（増減のない項目は表示しない）
+func writeDeltaRanking(b *strings.Builder, deltas []service.Delta, limit int, unit string) {
+	changed := make([]service.Delta, 0, len(deltas))
+	for _, d := range deltas {
+		if d.Change() != 0 {
+			changed = append(changed, d)
+		}
+	}
+	for i, d := range head(changed, limit) {
+		fmt.Fprintf(b, "%d位: %s - %s\n", i+1, d.Label, formatDelta(d, unit))
+	}
+	if len(changed) == 0 {
+		b.WriteString("増減はありません\n")
+	}
+	b.WriteString("\n")
+}
+
+// formatDelta は増減を "比較元 → 比較先 (+増減, +増減率%)" 形式で表す（unit は値の単位）
+// 比較元にない項目は「新規」、比較先にない項目は「消滅」と表示する
+func formatDelta(d service.Delta, unit string) string {
+	var detail string
+	switch {
+	case d.IsNew():
+		detail = fmt.Sprintf("%+d, 新規", d.Change())
+	case d.IsDropped():
+		detail = fmt.Sprintf("%+d, 消滅", d.Change())
+	default:
+		if percent, ok := d.Percent(); ok {
+			detail = fmt.Sprintf("%+d, %+.1f%%", d.Change(), percent)
+		} else {
+			detail = fmt.Sprintf("%+d", d.Change())
+		}
+	}
+	return fmt.Sprintf("%d%s → %d%s (%s)", d.Base, unit, d.Current, unit, detail)
+}
+
+// FormatDateRange は期間を "YYYY-MM-DD 〜 YYYY-MM-DD" 形式で表す
+func FormatDateRange(dateRange *domain.DateRange) string {
+	if dateRange == nil || (dateRange.Start.IsZero() && dateRange.End.IsZero()) {
+		return "全期間"
+	}
+	return formatDate(dateRange.Start) + " 〜 " + formatDate(dateRange.End)
+}
+
+// formatDate は日付をYYYY-MM-DD形式で表す（ゼロ値の場合は「指定なし」）
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return "（指定なし）"
+	}
+	return t.Format("2006-01-02")
+}
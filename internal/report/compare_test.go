@@ -0,0 +1,48 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+func TestWriteComparisonText(t *testing.T) {
+	cmp := &service.Comparison{
+		BaseRange:    &domain.DateRange{Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 2, 29, 23, 59, 59, 0, time.UTC)},
+		CurrentRange: &domain.DateRange{Start: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)},
+		Summary:      []service.Delta{{Key: "messages", Label: "メッセージ数", Base: 120, Current: 150}},
+		Threads:      []service.Delta{{Key: "threads", Label: "スレッド数", Base: 0, Current: 0}},
+		Emoji: []service.Delta{
+			{Key: "+1", Label: ":+1:", Base: 40, Current: 30},
+			{Key: "new", Label: ":new:", Base: 0, Current: 5},
+			{Key: "old", Label: ":old:", Base: 3, Current: 0},
+			{Key: "eyes", Label: ":eyes:", Base: 2, Current: 2},
+		},
+		Users: []service.Delta{{Key: "U1", Label: "田中太郎", Base: 1, Current: 1}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteComparisonText(&buf, "#general", cmp, DefaultCompareLimits); err != nil {
+		t.Fatalf("WriteComparisonText() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"##### 期間比較: #general #####\n比較元: 2024-02-01 〜 2024-02-29\n比較先: 2024-03-01 〜 2024-03-31\n",
+		"メッセージ数: 120 → 150 (+30, +25.0%)\n",
+		"スレッド数: 0 → 0 (+0)\n",
+		"1位: :+1: - 40回 → 30回 (-10, -25.0%)\n2位: :new: - 0回 → 5回 (+5, 新規)\n3位: :old: - 3回 → 0回 (-3, 消滅)\n\n",
+		"===== 投稿数の増減が大きいユーザー TOP10 =====\n増減はありません\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, ":eyes:") {
+		t.Errorf("output contains unchanged entries\n%s", got)
+	}
+}
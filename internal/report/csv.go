@@ -0,0 +1,178 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// utf8BOM はExcelでUTF-8のCSVとして開くためにファイルの先頭に付けるBOM
+const utf8BOM = "\ufeff"
+
+// sectionColumn は縦持ちのCSVでランキングの種類を表す列名
+const sectionColumn = "section"
+
+// CSVSection はCSVに出力する1つのランキング
+type CSVSection struct {
+	Name   string     // ランキングの種類（セクションごとに出力する場合はファイル名）
+	Header []string   // 列名
+	Rows   [][]string // 順位順の行
+}
+
+// ChannelCSVSections はチャンネル分析結果（検索の分析を含む）をCSVのセクションに変換する（各ランキングは表示件数まで）
+func ChannelCSVSections(result *service.AnalysisResult, limits Limits) []CSVSection {
+	return []CSVSection{
+		emojiSection("emoji", head(result.EmojiStats, limits.Emoji)),
+		messageSection(head(result.MessageStats, limits.Message)),
+		threadSection(head(result.ThreadStats, limits.Thread)),
+		userSection(head(result.UserStats, limits.User)),
+	}
+}
+
+// MultiChannelCSVSections は複数チャンネル・ワークスペースの分析結果をCSVのセクションに変換する
+// 全チャンネルを合算した各ランキングに加えて、チャンネルごとの投稿数とスタンプ数を channels セクションに含める
+func MultiChannelCSVSections(result *service.MultiChannelResult, limits Limits) []CSVSection {
+	return append(ChannelCSVSections(result.Merged, limits), channelSection(topChannels(result, limits)))
+}
+
+// UserCSVSections はユーザー分析結果をCSVのセクションに変換する（各ランキングは表示件数まで）
+func UserCSVSections(result *service.UserAnalysisResult, limits Limits) []CSVSection {
+	return []CSVSection{
+		emojiSection("reactions", head(result.ReactionRanking, limits.Emoji)),
+		threadSection(head(result.ThreadStats, limits.Thread)),
+	}
+}
+
+// emojiSection はスタンプのランキングをCSVのセクションに変換する
+func emojiSection(name string, stats []domain.EmojiCount) CSVSection {
+	section := CSVSection{Name: name, Header: []string{"rank", "emoji", "count"}}
+	for i, stat := range stats {
+		section.Rows = append(section.Rows, []string{strconv.Itoa(i + 1), stat.Emoji, strconv.Itoa(stat.Count)})
+	}
+	return section
+}
+
+// messageSection はリアクションが多いメッセージのランキングをCSVのセクションに変換する
+func messageSection(stats []domain.MessageReaction) CSVSection {
+	section := CSVSection{Name: "messages", Header: []string{"rank", "text", "reactions", "timestamp", "user_id", "channel_id", "message_id"}}
+	for i, stat := range stats {
+		section.Rows = append(section.Rows, []string{
+			strconv.Itoa(i + 1), stat.Text, strconv.Itoa(stat.Reactions), formatCSVTime(stat.Timestamp), stat.UserID, stat.ChannelID, stat.MessageID,
+		})
+	}
+	return section
+}
+
+// threadSection は返信が多いスレッドのランキングをCSVのセクションに変換する
+func threadSection(stats []domain.ThreadStats) CSVSection {
+	section := CSVSection{Name: "threads", Header: []string{"rank", "text", "reply_count", "timestamp", "user_id", "channel_id", "message_id"}}
+	for i, stat := range stats {
+		section.Rows = append(section.Rows, []string{
+			strconv.Itoa(i + 1), stat.Text, strconv.Itoa(stat.ReplyCount), formatCSVTime(stat.Timestamp), stat.UserID, stat.ChannelID, stat.MessageID,
+		})
+	}
+	return section
+}
+
+// userSection は投稿数のランキングをCSVのセクションに変換する
+func userSection(stats []domain.UserStats) CSVSection {
+	section := CSVSection{Name: "users", Header: []string{"rank", "user_id", "user_name", "count"}}
+	for i, stat := range stats {
+		section.Rows = append(section.Rows, []string{strconv.Itoa(i + 1), stat.UserID, stat.UserName, strconv.Itoa(stat.Count)})
+	}
+	return section
+}
+
+// channelSection はチャンネルの活動量のランキングをCSVのセクションに変換する
+func channelSection(ranking []service.ChannelActivity) CSVSection {
+	section := CSVSection{Name: "channels", Header: []string{"rank", "channel_id", "channel_name", "messages", "reactions"}}
+	for i, activity := range ranking {
+		section.Rows = append(section.Rows, []string{
+			strconv.Itoa(i + 1), activity.Channel.ID, activity.Channel.Name, strconv.Itoa(activity.Messages), strconv.Itoa(activity.Reactions),
+		})
+	}
+	return section
+}
+
+// formatCSVTime は日時をRFC3339形式で表す（不明な場合は空欄）
+func formatCSVTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// WriteCSV は1つのセクションをCSV形式で出力する
+func WriteCSV(w io.Writer, section CSVSection, bom bool) error {
+	return writeCSVRecords(w, section.Header, section.Rows, bom)
+}
+
+// WriteLongCSV は全てのセクションを、先頭に section 列を持つ1つのCSV（縦持ち）として出力する
+// 列は各セクションの列を最初に現れた順に並べたもので、セクションにない列は空欄になる
+func WriteLongCSV(w io.Writer, sections []CSVSection, bom bool) error {
+	header := []string{sectionColumn}
+	for _, section := range sections {
+		for _, column := range section.Header {
+			if !slices.Contains(header, column) {
+				header = append(header, column)
+			}
+		}
+	}
+
+	var rows [][]string
+	for _, section := range sections {
+		for _, row := range section.Rows {
+			record := make([]string, len(header))
+			record[0] = section.Name
+			for i, column := range section.Header {
+				record[slices.Index(header, column)] = row[i]
+			}
+			rows = append(rows, record)
+		}
+	}
+	return writeCSVRecords(w, header, rows, bom)
+}
+
+// writeCSVRecords は列名と行をCSV形式で出力する
+func writeCSVRecords(w io.Writer, header []string, rows [][]string, bom bool) error {
+	if bom {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return err
+		}
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write(neutralizeFormulas(row)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// neutralizeFormulas は表計算ソフトで数式として解釈される値（= + - @ で始まる値）の先頭に ' を付ける
+// メッセージ本文などに含まれる数式が、CSVを開いたときに実行されないようにする
+// 数値（スタンプ名の "+1" や "-1" を含む）はそのまま出力する
+func neutralizeFormulas(row []string) []string {
+	out := make([]string, len(row))
+	for i, value := range row {
+		out[i] = value
+		if value == "" || !strings.ContainsAny(value[:1], "=+-@\t\r") {
+			continue
+		}
+		if _, err := strconv.ParseFloat(value, 64); err == nil {
+			continue
+		}
+		out[i] = "'" + value
+	}
+	return out
+}
@@ -0,0 +1,136 @@
+package report
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+func TestWriteCSV(t *testing.T) {
+	posted := time.Date(2024, 1, 10, 9, 30, 0, 0, time.FixedZone("JST", 9*60*60))
+	result := &service.AnalysisResult{
+		EmojiStats: []domain.EmojiCount{{Emoji: "+1", Count: 45}, {Emoji: "eyes", Count: 32}},
+		MessageStats: []domain.MessageReaction{
+			{Text: "リリース, 完了", Reactions: 15, Timestamp: posted, UserID: "U1", ChannelID: "C1", MessageID: "1704846600.000100"},
+		},
+	}
+	sections := ChannelCSVSections(result, Limits{Emoji: 1, Message: 3, User: 3, Thread: 3})
+
+	tests := []struct {
+		name     string
+		section  CSVSection
+		bom      bool
+		expected string
+	}{
+		{
+			name:     "スタンプ（表示件数まで）",
+			section:  sections[0],
+			expected: "rank,emoji,count\n1,+1,45\n",
+		},
+		{
+			name:     "メッセージ（カンマを含む本文）",
+			section:  sections[1],
+			expected: "rank,text,reactions,timestamp,user_id,channel_id,message_id\n1,\"リリース, 完了\",15,2024-01-10T09:30:00+09:00,U1,C1,1704846600.000100\n",
+		},
+		{
+			name:     "データなし（BOMあり）",
+			section:  sections[3],
+			bom:      true,
+			expected: "\ufeffrank,user_id,user_name,count\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteCSV(&buf, tt.section, tt.bom); err != nil {
+				t.Fatalf("WriteCSV() error = %v", err)
+			}
+			if got := buf.String(); got != tt.expected {
+				t.Errorf("WriteCSV() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteLongCSV(t *testing.T) {
+	result := &service.UserAnalysisResult{
+		ReactionRanking: []domain.EmojiCount{{Emoji: "tada", Count: 3}},
+		ThreadStats: []domain.ThreadStats{
+			{Text: "=HYPERLINK(\"http://example.com\")", ReplyCount: 4, UserID: "U1", ChannelID: "C1", MessageID: "1.000"},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteLongCSV(&buf, UserCSVSections(result, DefaultUserLimits), false); err != nil {
+		t.Fatalf("WriteLongCSV() error = %v", err)
+	}
+	expected := "section,rank,emoji,count,text,reply_count,timestamp,user_id,channel_id,message_id\n" +
+		"reactions,1,tada,3,,,,,,\n" +
+		"threads,1,,,\"'=HYPERLINK(\"\"http://example.com\"\")\",4,,U1,C1,1.000\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("WriteLongCSV() = %q, want %q", got, expected)
+	}
+}
+
+func TestNeutralizeFormulas(t *testing.T) {
+	got := neutralizeFormulas([]string{"+1", "-1", "=1+1", "@channel", "-", "通常", ""})
+	expected := []string{"+1", "-1", "'=1+1", "'@channel", "'-", "通常", ""}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Errorf("neutralizeFormulas()[%d] = %q, want %q", i, got[i], expected[i])
+		}
+	}
+}
+
+func TestMultiChannelCSVSections(t *testing.T) {
+	sections := MultiChannelCSVSections(newMultiChannelResult(), DefaultWorkspaceLimits)
+
+	var names []string
+	for _, section := range sections {
+		names = append(names, section.Name)
+	}
+	if want := []string{"emoji", "messages", "threads", "users", "channels"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("sections = %v, want %v", names, want)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, sections[4], false); err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	expected := "rank,channel_id,channel_name,messages,reactions\n" +
+		"1,C2,team-b,2,5\n" +
+		"2,C1,team-a,1,0\n"
+	if got := buf.String(); got != expected {
+		t.Errorf("WriteCSV() = %q, want %q", got, expected)
+	}
+}
+
+func TestMultiChannelCSVSections_Limit(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit int
+		want  int
+	}{
+		{name: "表示件数まで", limit: 1, want: 1},
+		{name: "0の場合は他のランキングと同じく空", limit: 0, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limits := DefaultWorkspaceLimits
+			limits.Channel = tt.limit
+			limits.Emoji = tt.limit
+			sections := MultiChannelCSVSections(newMultiChannelResult(), limits)
+			if got := len(sections[0].Rows); got > tt.want {
+				t.Errorf("emoji rows = %d, want at most %d", got, tt.want)
+			}
+			if got := len(sections[4].Rows); got != tt.want {
+				t.Errorf("channels rows = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
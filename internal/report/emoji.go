@@ -0,0 +1,131 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/slack-go/slack"
+)
+
+// DefaultEmojiLimits はスタンプ分析のデフォルト表示件数
+var DefaultEmojiLimits = Limits{Message: 10, User: 10, Channel: 10}
+
+// WriteEmojiText は1つのスタンプの分析結果をテキスト形式で出力する
+// opts.Charts の場合は推移をスパークライン、ランキングを横棒グラフで表す
+func WriteEmojiText(w io.Writer, dateRange *domain.DateRange, result *service.EmojiAnalysisResult, limits Limits, opts TextOptions) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "##### スタンプ :%s: の分析結果 #####\n", result.Emoji)
+	fmt.Fprintf(&b, "期間: %s\n", FormatDateRange(dateRange))
+	fmt.Fprintf(&b, "スタンプ数: %d回 / スタンプがついたメッセージ: %d件\n\n", result.Total, result.Messages)
+
+	fmt.Fprintf(&b, "===== 推移（%s、メッセージの投稿日） =====\n", result.Granularity)
+	if opts.Charts {
+		writeSparkline(&b, result.Granularity, emojiTimelinePoints(result.Timeline), "回", opts.lineWidth())
+	} else {
+		for _, period := range result.Timeline {
+			fmt.Fprintf(&b, "%s: %d回 (%d件)\n", formatPeriod(period.Start, result.Granularity), period.Count, period.Messages)
+		}
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "===== 最もスタンプがついたチャンネル TOP%d =====\n", limits.Channel)
+	if opts.Charts {
+		var rows []chartRow
+		for _, activity := range head(result.Channels, limits.Channel) {
+			rows = append(rows, chartRow{label: "#" + activity.Channel.Name, value: activity.Reactions})
+		}
+		b.WriteString(barChart(rows, "回", opts.lineWidth()))
+	} else {
+		for i, activity := range head(result.Channels, limits.Channel) {
+			fmt.Fprintf(&b, "%d位: #%s - %d回 (%d件)\n", i+1, activity.Channel.Name, activity.Reactions, activity.Messages)
+		}
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "===== 最もスタンプを受け取ったユーザー TOP%d =====\n", limits.User)
+	if opts.Charts {
+		b.WriteString(barChart(userRows(result.Posters, limits.User), "回", opts.lineWidth()))
+	} else {
+		for i, stat := range head(result.Posters, limits.User) {
+			fmt.Fprintf(&b, "%d位: %s - %d回\n", i+1, stat.UserName, stat.Count)
+		}
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "===== 最もスタンプがついたメッセージ TOP%d =====\n", limits.Message)
+	if opts.Charts {
+		var rows []chartRow
+		for _, stat := range head(result.TopMessages, limits.Message) {
+			rows = append(rows, chartRow{label: stat.Text, value: stat.Reactions, suffix: " (#" + result.ChannelName(stat.ChannelID) + ")"})
+		}
+		b.WriteString(messageLines(rows, "回", opts.lineWidth()))
+		b.WriteString("\n")
+	} else {
+		for i, stat := range head(result.TopMessages, limits.Message) {
+			fmt.Fprintf(&b, "%d位: %s (#%s)\n:%s: %d回\n\n", i+1, Preview(stat.Text), result.ChannelName(stat.ChannelID), result.Emoji, stat.Reactions)
+		}
+		if len(result.TopMessages) == 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// emojiTimelinePoints はスタンプの推移をスパークラインの値に変換する
+func emojiTimelinePoints(timeline []service.EmojiPeriod) []timelinePoint {
+	var points []timelinePoint
+	for _, period := range timeline {
+		points = append(points, timelinePoint{start: period.Start, value: period.Count})
+	}
+	return points
+}
+
+// EmojiBlocks は1つのスタンプの分析結果をBlock Kitのブロックに変換する
+func EmojiBlocks(title string, dateRange *domain.DateRange, result *service.EmojiAnalysisResult, limits Limits, link MessageLinker) []slack.Block {
+	blocks := headerBlocks(title, dateRange, fmt.Sprintf(":%s: %d回 / スタンプがついたメッセージ: %d件", result.Emoji, result.Total, result.Messages))
+
+	var timeline []string
+	for _, period := range result.Timeline {
+		timeline = append(timeline, fmt.Sprintf("%s - %d回", formatPeriod(period.Start, result.Granularity), period.Count))
+	}
+	blocks = append(blocks, rankingSection("推移（"+result.Granularity.String()+"）", timeline))
+
+	var channels []string
+	for i, activity := range head(result.Channels, limits.Channel) {
+		channels = append(channels, fmt.Sprintf("%d. <#%s> - %d回", i+1, activity.Channel.ID, activity.Reactions))
+	}
+	blocks = append(blocks, rankingSection("最もスタンプがついたチャンネル", channels))
+
+	var posters []string
+	for i, stat := range head(result.Posters, limits.User) {
+		posters = append(posters, fmt.Sprintf("%d. <@%s> - %d回", i+1, stat.UserID, stat.Count))
+	}
+	blocks = append(blocks, rankingSection("最もスタンプを受け取ったユーザー", posters))
+
+	var messages []string
+	for i, stat := range head(result.TopMessages, limits.Message) {
+		messages = append(messages, fmt.Sprintf("%d. %s%s - %d回", i+1, messageLink(stat.Text, stat.ChannelID, stat.MessageID, link), mention(stat.UserID), stat.Reactions))
+	}
+	return append(blocks, rankingSection("最もスタンプがついたメッセージ", messages))
+}
+
+// formatPeriod は推移の期間を表す（時間別は開始日時、日別・週別は開始日、月別は年月）
+func formatPeriod(start time.Time, granularity service.Granularity) string {
+	switch granularity {
+	case service.GranularityHour:
+		return start.Format("2006-01-02 15:04")
+	case service.GranularityWeek:
+		return start.Format("2006-01-02") + "〜"
+	case service.GranularityMonth:
+		return start.Format("2006-01")
+	default:
+		return start.Format("2006-01-02")
+	}
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/slack-go/slack"
+)
+
+func newEmojiResult() *service.EmojiAnalysisResult {
+	return &service.EmojiAnalysisResult{
+		Emoji:       "tada",
+		Total:       9,
+		Messages:    3,
+		Granularity: service.GranularityWeek,
+		Timeline: []service.EmojiPeriod{
+			{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Count: 4, Messages: 2},
+			{Start: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)},
+			{Start: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), Count: 5, Messages: 1},
+		},
+		Channels: []service.ChannelActivity{
+			{Channel: &domain.Channel{ID: "C2", Name: "general"}, Messages: 1, Reactions: 5},
+			{Channel: &domain.Channel{ID: "C1", Name: "dev"}, Messages: 2, Reactions: 4},
+		},
+		Posters: []domain.UserStats{{UserID: "U2", UserName: "佐藤花子", Count: 5}},
+		TopMessages: []domain.MessageReaction{
+			{Text: "入社しました", Reactions: 5, UserID: "U2", ChannelID: "C2", MessageID: "1705280000.000100"},
+		},
+	}
+}
+
+func TestWriteEmojiText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteEmojiText(&buf, nil, newEmojiResult(), DefaultEmojiLimits, TextOptions{}); err != nil {
+		t.Fatalf("WriteEmojiText() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"##### スタンプ :tada: の分析結果 #####\n期間: 全期間\nスタンプ数: 9回 / スタンプがついたメッセージ: 3件\n",
+		"===== 推移（週別、メッセージの投稿日） =====\n2024-01-01〜: 4回 (2件)\n2024-01-08〜: 0回 (0件)\n2024-01-15〜: 5回 (1件)\n",
+		"1位: #general - 5回 (1件)\n2位: #dev - 4回 (2件)\n",
+		"1位: 佐藤花子 - 5回\n",
+		"1位: 入社しました (#general)\n:tada: 5回\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestEmojiBlocks(t *testing.T) {
+	blocks := EmojiBlocks("スタンプ :tada: の分析結果", nil, newEmojiResult(), DefaultEmojiLimits, nil)
+	if len(blocks) != 7 {
+		t.Fatalf("len(blocks) = %d, want 7", len(blocks))
+	}
+
+	var texts []string
+	for _, block := range blocks[3:] {
+		texts = append(texts, block.(*slack.SectionBlock).Text.Text)
+	}
+	got := strings.Join(texts, "\n")
+
+	for _, want := range []string{
+		"*推移（週別）*\n2024-01-01〜 - 4回\n2024-01-08〜 - 0回",
+		"1. <#C2> - 5回\n2. <#C1> - 4回",
+		"1. <@U2> - 5回",
+		"1. 入社しました (<@U2>) - 5回",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("blocks do not contain %q\n%s", want, got)
+		}
+	}
+}
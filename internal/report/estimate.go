@@ -0,0 +1,53 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// WriteEstimateText はAPI呼び出し回数と所要時間の見積もりをテキスト形式で出力する
+// title は見積もりの対象（例: #general、ユーザー名）を表す
+func WriteEstimateText(w io.Writer, title string, dateRange *domain.DateRange, estimate *service.Estimate) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "##### API呼び出しの見積もり: %s #####\n", title)
+	fmt.Fprintf(&b, "期間: %s\n", FormatDateRange(dateRange))
+	fmt.Fprintf(&b, "対象: %dチャンネル（%dチャンネルの履歴をサンプリング）\n", estimate.Channels, estimate.SampledChannels)
+	fmt.Fprintf(&b, "推定メッセージ数: 約%d件（スレッド: 約%d件）\n\n", estimate.Messages, estimate.ThreadParents)
+
+	b.WriteString("===== API呼び出し回数 =====\n")
+	for _, c := range estimate.Calls {
+		fmt.Fprintf(&b, "%s: 約%d回（上限 %d回/分、%s）\n", c.Method, c.Calls, c.PerMinute, formatDuration(c.Duration()))
+	}
+	fmt.Fprintf(&b, "合計: 約%d回\n\n", estimate.TotalCalls())
+
+	fmt.Fprintf(&b, "予想所要時間: %s\n", formatDuration(estimate.Duration()))
+	if len(estimate.Notes) > 0 {
+		b.WriteString("\n")
+		for _, note := range estimate.Notes {
+			fmt.Fprintf(&b, "※ %s\n", note)
+		}
+	}
+	b.WriteString("※ レート制限の上限で呼び出した場合の目安です。時間がかかる場合は期間を絞り込んでください\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatDuration は所要時間を「約1時間5分」の形式に整形する
+func formatDuration(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "1分未満"
+	case d < time.Hour:
+		return fmt.Sprintf("約%d分", int(d.Round(time.Minute)/time.Minute))
+	default:
+		d = d.Round(time.Minute)
+		return fmt.Sprintf("約%d時間%d分", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+}
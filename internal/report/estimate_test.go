@@ -0,0 +1,60 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+func TestWriteEstimateText(t *testing.T) {
+	estimate := &service.Estimate{
+		Channels:        120,
+		SampledChannels: 20,
+		Messages:        45000,
+		ThreadParents:   3200,
+		Calls: []service.APICalls{
+			{Method: service.MethodConversationsHistory, Calls: 130, PerMinute: 50},
+			{Method: service.MethodConversationsReplies, Calls: 3200, PerMinute: 50},
+		},
+		Notes: []string{"サンプリングの補足"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteEstimateText(&buf, "ワークスペース全体", nil, estimate); err != nil {
+		t.Fatalf("WriteEstimateText() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"##### API呼び出しの見積もり: ワークスペース全体 #####\n期間: 全期間\n",
+		"対象: 120チャンネル（20チャンネルの履歴をサンプリング）\n",
+		"conversations.history: 約130回（上限 50回/分、約3分）\n",
+		"conversations.replies: 約3200回（上限 50回/分、約1時間4分）\n",
+		"合計: 約3330回\n",
+		"予想所要時間: 約1時間7分\n",
+		"※ サンプリングの補足\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d        time.Duration
+		expected string
+	}{
+		{d: 30 * time.Second, expected: "1分未満"},
+		{d: 150 * time.Second, expected: "約3分"},
+		{d: 2*time.Hour + 5*time.Minute, expected: "約2時間5分"},
+	}
+	for _, tt := range tests {
+		if got := formatDuration(tt.d); got != tt.expected {
+			t.Errorf("formatDuration(%v) = %q, want %q", tt.d, got, tt.expected)
+		}
+	}
+}
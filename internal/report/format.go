@@ -0,0 +1,20 @@
+package report
+
+import "slices"
+
+// 出力形式
+const (
+	FormatText     = "text"     // テキスト形式
+	FormatJSON     = "json"     // JSON形式（docs/json-schema.md）
+	FormatCSV      = "csv"      // CSV形式（ランキングごと、または section 列を持つ1つのCSV）
+	FormatMarkdown = "markdown" // Markdown形式（Wikiなどに貼り付ける）
+	FormatHTML     = "html"     // 1ファイルで完結するHTMLのダッシュボード
+)
+
+// Formats は対応している出力形式の一覧
+var Formats = []string{FormatText, FormatJSON, FormatCSV, FormatMarkdown, FormatHTML}
+
+// IsSupportedFormat は出力形式に対応しているかどうかを返す
+func IsSupportedFormat(format string) bool {
+	return slices.Contains(Formats, format)
+}
@@ -0,0 +1,286 @@
+package report
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// maxChartBars はダッシュボードのグラフに表示する最大件数
+const maxChartBars = 10
+
+// maxTableRows はダッシュボードの表に含める最大件数（ファイルサイズを抑えるため）
+const maxTableRows = 500
+
+//go:embed dashboard.html
+var dashboardHTML string
+
+// dashboardTemplate は外部のCSS・JavaScriptを読み込まない、1ファイルで完結するダッシュボードのテンプレート
+var dashboardTemplate = template.Must(template.New("dashboard").Parse(dashboardHTML))
+
+// dashboard はダッシュボードのテンプレートに渡すデータ
+type dashboard struct {
+	Title       string
+	Period      string
+	GeneratedAt string
+	Meta        Metadata
+	Summary     []dashboardStat
+	Charts      []dashboardChart
+	Tables      []dashboardTable
+	Warnings    []string
+}
+
+// dashboardStat は概要に表示する数値
+type dashboardStat struct {
+	Label string
+	Value int
+}
+
+// dashboardChart は横棒グラフ
+type dashboardChart struct {
+	Title   string
+	TableID string // 棒をクリックしたときに絞り込む表（空文字列の場合は絞り込まない）
+	Bars    []dashboardBar
+}
+
+// dashboardBar は横棒グラフの1本の棒
+type dashboardBar struct {
+	Label   string
+	Value   int
+	Percent float64 // 最大値に対する割合（棒の長さ）
+	Filter  string  // 表を絞り込む文字列
+	URL     string  // 棒のラベルのリンク先（空文字列の場合はリンクにしない）
+}
+
+// dashboardTable は並べ替えと絞り込みができる表
+type dashboardTable struct {
+	ID      string
+	Title   string
+	Columns []dashboardColumn
+	Rows    [][]dashboardCell
+}
+
+// dashboardColumn は表の列
+type dashboardColumn struct {
+	Label   string
+	Numeric bool // 数値として並べ替える
+}
+
+// dashboardCell は表のセル
+type dashboardCell struct {
+	Text    string
+	Title   string // マウスを重ねたときに表示する全文
+	URL     string // リンク先（空文字列の場合はリンクにしない）
+	Numeric bool   // 右寄せで表示する
+}
+
+// WriteChannelHTML はチャンネル分析結果（検索の分析を含む）を1ファイルで完結するHTMLのダッシュボードとして出力する
+// 表には各ランキングを最大 maxTableRows 件まで含め、グラフには上位 maxChartBars 件を表示する
+func WriteChannelHTML(w io.Writer, title string, meta Metadata, result *service.AnalysisResult, link MessageLinker) error {
+	return dashboardTemplate.Execute(w, newChannelDashboard(title, meta, result, link))
+}
+
+// WriteMultiChannelHTML は複数チャンネル・ワークスペースの分析結果を1ファイルで完結するHTMLのダッシュボードとして出力する
+// 全チャンネルを合算したランキングに、チャンネルごとの投稿数とスタンプ数の表とグラフを加える
+func WriteMultiChannelHTML(w io.Writer, title string, meta Metadata, result *service.MultiChannelResult, link MessageLinker) error {
+	d := newChannelDashboard(title, meta, result.Merged, link)
+
+	channels := dashboardTable{ID: "channels", Title: "チャンネル", Columns: []dashboardColumn{
+		{Label: "順位", Numeric: true}, {Label: "チャンネル"}, {Label: "投稿数", Numeric: true}, {Label: "スタンプ数", Numeric: true},
+	}}
+	var channelBars []dashboardBar
+	for i, activity := range head(result.ChannelRanking(), maxTableRows) {
+		name := "#" + activity.Channel.Name
+		channels.Rows = append(channels.Rows, []dashboardCell{rankCell(i), {Text: name}, countCell(activity.Messages), countCell(activity.Reactions)})
+		if i < maxChartBars {
+			channelBars = append(channelBars, dashboardBar{Label: name, Value: activity.Messages, Filter: name})
+		}
+	}
+
+	d.Summary = append([]dashboardStat{{Label: "チャンネル数", Value: len(result.Channels)}}, d.Summary...)
+	d.Charts = append([]dashboardChart{{Title: "チャンネルごとの投稿数", TableID: channels.ID, Bars: scaleBars(channelBars)}}, d.Charts...)
+	d.Tables = append(d.Tables, channels)
+	return dashboardTemplate.Execute(w, d)
+}
+
+// newChannelDashboard はチャンネル分析結果のダッシュボードのデータを作成する
+func newChannelDashboard(title string, meta Metadata, result *service.AnalysisResult, link MessageLinker) *dashboard {
+	userNames := make(map[string]string, len(result.UserStats))
+	for _, stat := range result.UserStats {
+		userNames[stat.UserID] = stat.UserName
+	}
+	poster := func(userID string) string {
+		if name, ok := userNames[userID]; ok {
+			return name
+		}
+		return userID
+	}
+
+	messages := dashboardTable{ID: "messages", Title: "リアクションがついたメッセージ", Columns: []dashboardColumn{
+		{Label: "順位", Numeric: true}, {Label: "メッセージ"}, {Label: "投稿者"}, {Label: "投稿日時"}, {Label: "リアクション数", Numeric: true},
+	}}
+	for i, stat := range head(result.MessageStats, maxTableRows) {
+		messages.Rows = append(messages.Rows, []dashboardCell{
+			rankCell(i), messageCell(stat.Text, stat.ChannelID, stat.MessageID, link), {Text: poster(stat.UserID)}, timeCell(stat.Timestamp), countCell(stat.Reactions),
+		})
+	}
+
+	users := dashboardTable{ID: "users", Title: "投稿数が多いユーザー", Columns: []dashboardColumn{
+		{Label: "順位", Numeric: true}, {Label: "ユーザー"}, {Label: "投稿数", Numeric: true},
+	}}
+	var userBars []dashboardBar
+	for i, stat := range head(result.UserStats, maxTableRows) {
+		users.Rows = append(users.Rows, []dashboardCell{rankCell(i), {Text: stat.UserName}, countCell(stat.Count)})
+		if i < maxChartBars {
+			userBars = append(userBars, dashboardBar{Label: stat.UserName, Value: stat.Count, Filter: stat.UserName})
+		}
+	}
+
+	threads, threadBars := threadDashboard(result.ThreadStats, poster, link)
+	emoji, emojiBars := emojiDashboard(result.EmojiStats)
+
+	return &dashboard{
+		Title:       title,
+		Period:      FormatDateRange(dateRangeOf(meta.Period)),
+		GeneratedAt: meta.GeneratedAt.Format("2006-01-02 15:04"),
+		Meta:        meta,
+		Summary: []dashboardStat{
+			{Label: "投稿数", Value: result.TotalMessages()},
+			{Label: "スタンプ数", Value: result.TotalReactions()},
+			{Label: "スタンプの種類", Value: len(result.EmojiStats)},
+			{Label: "投稿したユーザー", Value: len(result.UserStats)},
+		},
+		Charts: []dashboardChart{
+			{Title: "スタンプの使用回数", TableID: emoji.ID, Bars: scaleBars(emojiBars)},
+			{Title: "ユーザーごとの投稿数", TableID: users.ID, Bars: scaleBars(userBars)},
+			{Title: "スレッドのコメント数", Bars: scaleBars(threadBars)},
+		},
+		Tables:   []dashboardTable{emoji, messages, users, threads},
+		Warnings: result.Warnings,
+	}
+}
+
+// WriteUserHTML はユーザー分析結果を1ファイルで完結するHTMLのダッシュボードとして出力する
+func WriteUserHTML(w io.Writer, meta Metadata, result *service.UserAnalysisResult, link MessageLinker) error {
+	threads, threadBars := threadDashboard(result.ThreadStats, nil, link)
+	emoji, emojiBars := emojiDashboard(result.ReactionRanking)
+
+	return dashboardTemplate.Execute(w, &dashboard{
+		Title:       "ユーザー分析結果: " + result.UserName,
+		Period:      FormatDateRange(dateRangeOf(meta.Period)),
+		GeneratedAt: meta.GeneratedAt.Format("2006-01-02 15:04"),
+		Meta:        meta,
+		Summary: []dashboardStat{
+			{Label: "投稿総数", Value: result.TotalMessages},
+			{Label: "スタンプ総数", Value: result.TotalReactions},
+			{Label: "スタンプの種類", Value: len(result.ReactionRanking)},
+			{Label: "返信がついたスレッド", Value: len(result.ThreadStats)},
+		},
+		Charts: []dashboardChart{
+			{Title: "投稿についたスタンプ", TableID: emoji.ID, Bars: scaleBars(emojiBars)},
+			{Title: "スレッドのコメント数", Bars: scaleBars(threadBars)},
+		},
+		Tables:   []dashboardTable{emoji, threads},
+		Warnings: result.Warnings,
+	})
+}
+
+// emojiDashboard はスタンプのランキングの表とグラフの棒を作成する
+func emojiDashboard(stats []domain.EmojiCount) (dashboardTable, []dashboardBar) {
+	table := dashboardTable{ID: "emoji", Title: "スタンプ", Columns: []dashboardColumn{
+		{Label: "順位", Numeric: true}, {Label: "スタンプ"}, {Label: "回数", Numeric: true},
+	}}
+	var bars []dashboardBar
+	for i, stat := range head(stats, maxTableRows) {
+		name := ":" + stat.Emoji + ":"
+		table.Rows = append(table.Rows, []dashboardCell{rankCell(i), {Text: name}, countCell(stat.Count)})
+		if i < maxChartBars {
+			bars = append(bars, dashboardBar{Label: name, Value: stat.Count, Filter: name})
+		}
+	}
+	return table, bars
+}
+
+// threadDashboard はスレッドのランキングの表とグラフの棒を作成する
+// poster がnilの場合は投稿者の列を含めない（ユーザー分析）
+func threadDashboard(stats []domain.ThreadStats, poster func(string) string, link MessageLinker) (dashboardTable, []dashboardBar) {
+	table := dashboardTable{ID: "threads", Title: "コメント数が多いスレッド"}
+	table.Columns = append(table.Columns, dashboardColumn{Label: "順位", Numeric: true}, dashboardColumn{Label: "スレッド"})
+	if poster != nil {
+		table.Columns = append(table.Columns, dashboardColumn{Label: "投稿者"})
+	}
+	table.Columns = append(table.Columns, dashboardColumn{Label: "投稿日時"}, dashboardColumn{Label: "コメント数", Numeric: true})
+
+	var bars []dashboardBar
+	for i, stat := range head(stats, maxTableRows) {
+		message := messageCell(stat.Text, stat.ChannelID, stat.MessageID, link)
+		row := []dashboardCell{rankCell(i), message}
+		if poster != nil {
+			row = append(row, dashboardCell{Text: poster(stat.UserID)})
+		}
+		table.Rows = append(table.Rows, append(row, timeCell(stat.Timestamp), countCell(stat.ReplyCount)))
+		if i < maxChartBars {
+			bars = append(bars, dashboardBar{Label: message.Text, Value: stat.ReplyCount, URL: message.URL})
+		}
+	}
+	return table, bars
+}
+
+// scaleBars は最大値を100%として棒の長さ（小数点以下1桁）を設定する
+func scaleBars(bars []dashboardBar) []dashboardBar {
+	maxValue := 0
+	for _, bar := range bars {
+		maxValue = max(maxValue, bar.Value)
+	}
+	for i := range bars {
+		if maxValue > 0 {
+			bars[i].Percent = math.Round(float64(bars[i].Value)*1000/float64(maxValue)) / 10
+		}
+	}
+	return bars
+}
+
+// rankCell は順位のセルを作成する
+func rankCell(i int) dashboardCell {
+	return dashboardCell{Text: strconv.Itoa(i + 1), Numeric: true}
+}
+
+// countCell は件数のセルを作成する
+func countCell(count int) dashboardCell {
+	return dashboardCell{Text: strconv.Itoa(count), Numeric: true}
+}
+
+// messageCell はメッセージのプレビューのセルを作成する（リンクを作成できる場合はメッセージへのリンク）
+func messageCell(text, channelID, ts string, link MessageLinker) dashboardCell {
+	preview := Preview(text)
+	if preview == "" {
+		preview = "（本文なし）"
+	}
+	return dashboardCell{Text: preview, Title: text, URL: messageURL(link, channelID, ts)}
+}
+
+// timeCell は投稿日時のセルを作成する（不明な場合は空欄）
+func timeCell(t time.Time) dashboardCell {
+	if t.IsZero() {
+		return dashboardCell{}
+	}
+	return dashboardCell{Text: t.Format("2006-01-02 15:04")}
+}
+
+// dateRangeOf はメタデータの期間を日付範囲に戻す
+func dateRangeOf(p Period) *domain.DateRange {
+	dateRange := &domain.DateRange{}
+	if p.Start != nil {
+		dateRange.Start = *p.Start
+	}
+	if p.End != nil {
+		dateRange.End = *p.End
+	}
+	return dateRange
+}
@@ -0,0 +1,119 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+func TestWriteChannelHTML(t *testing.T) {
+	result := &service.AnalysisResult{
+		EmojiStats: []domain.EmojiCount{
+			{Emoji: "+1", Count: 40},
+			{Emoji: "eyes", Count: 10},
+		},
+		MessageStats: []domain.MessageReaction{
+			{Text: "<script>alert(1)</script> リリース", Reactions: 15, UserID: "U1", ChannelID: "C1", MessageID: "1700000000.123456",
+				Timestamp: time.Date(2024, 1, 10, 9, 30, 0, 0, time.UTC)},
+		},
+		ThreadStats: []domain.ThreadStats{
+			{Text: "勉強会について", ReplyCount: 23, UserID: "U2", ChannelID: "C1", MessageID: "1700000100.000000"},
+		},
+		UserStats: []domain.UserStats{
+			{UserID: "U1", UserName: "田中太郎", Count: 156},
+		},
+		UserMessageCount: map[string]int{"U1": 156},
+		Warnings:         []string{"スレッド返信の取得に失敗しました"},
+	}
+	link := func(channelID, ts string) string {
+		return "https://example.slack.com/archives/" + channelID + "/p" + strings.Replace(ts, ".", "", 1)
+	}
+	meta := NewMetadata("channel", "v1.2.3", nil, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	if err := WriteChannelHTML(&buf, "#general の分析結果", meta, result, link); err != nil {
+		t.Fatalf("WriteChannelHTML() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"<title>#general の分析結果</title>",
+		"期間: 全期間 / 生成日時: 2024-02-01 12:00",
+		`<span class="value">156</span><span class="label">投稿数</span>`,
+		"<li>スレッド返信の取得に失敗しました</li>",
+		`data-table="emoji" data-filter=":&#43;1:"`,
+		`<span class="bar" style="width: 100%">`,
+		`<span class="bar" style="width: 25%">`,
+		`<a href="https://example.slack.com/archives/C1/p1700000000123456" target="_blank" rel="noopener">&lt;script&gt;alert(1)&lt;/script&gt; リリース</a>`,
+		`<td>田中太郎</td>`,
+		`<td>2024-01-10 09:30</td>`,
+		`<td class="number">15</td>`,
+		`<section class="table" id="threads">`,
+		"v1.2.3 で生成",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q", want)
+		}
+	}
+	for _, unwanted := range []string{"<script>alert(1)", "<link", "src=\"http", "ZgotmplZ"} {
+		if strings.Contains(got, unwanted) {
+			t.Errorf("output contains %q", unwanted)
+		}
+	}
+}
+
+func TestWriteUserHTML(t *testing.T) {
+	result := &service.UserAnalysisResult{
+		UserName:        "田中太郎",
+		TotalMessages:   156,
+		TotalReactions:  342,
+		ReactionRanking: []domain.EmojiCount{{Emoji: "tada", Count: 3}},
+	}
+	meta := NewMetadata("user", "dev", nil, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	if err := WriteUserHTML(&buf, meta, result, nil); err != nil {
+		t.Fatalf("WriteUserHTML() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"<h1>ユーザー分析結果: 田中太郎</h1>",
+		"<td>:tada:</td>",
+		"<p class=\"empty\">該当なし</p>",
+		"<p class=\"empty\" hidden>該当なし</p>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q", want)
+		}
+	}
+	if strings.Contains(got, `class="warnings"`) {
+		t.Errorf("output contains warnings section without warnings")
+	}
+}
+
+func TestWriteMultiChannelHTML(t *testing.T) {
+	meta := NewMetadata("workspace", "v1.2.3", nil, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	if err := WriteMultiChannelHTML(&buf, "ワークスペース全体のランキング（2チャンネル）", meta, newMultiChannelResult(), nil); err != nil {
+		t.Fatalf("WriteMultiChannelHTML() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		`<span class="value">2</span><span class="label">チャンネル数</span>`,
+		`<span class="value">3</span><span class="label">投稿数</span>`,
+		`data-table="channels" data-filter="#team-b"`,
+		`<section class="table" id="channels">`,
+		`<td>#team-a</td>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q", want)
+		}
+	}
+}
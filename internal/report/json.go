@@ -0,0 +1,186 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// SchemaVersion はJSON出力のスキーマのバージョン（docs/json-schema.md）
+// フィールドの削除や意味の変更など、互換性のない変更をした場合に上げる（フィールドの追加では上げない）
+const SchemaVersion = 1
+
+// ToolName はJSON出力のメタデータに含めるツール名
+const ToolName = "slack-reaction"
+
+// Metadata はJSON出力に含める実行時の情報
+type Metadata struct {
+	SchemaVersion int       `json:"schema_version"`
+	Tool          string    `json:"tool"`
+	ToolVersion   string    `json:"tool_version"`
+	Command       string    `json:"command"`
+	GeneratedAt   time.Time `json:"generated_at"`
+	Channel       *Target   `json:"channel,omitempty"` // チャンネル分析の対象
+	User          *Target   `json:"user,omitempty"`    // ユーザー分析の対象
+	Query         string    `json:"query,omitempty"`   // 検索の分析に使用したクエリ
+	Period        Period    `json:"period"`
+}
+
+// NewMetadata はスキーマのバージョンと期間を設定したメタデータを作成する
+func NewMetadata(command, toolVersion string, dateRange *domain.DateRange, generatedAt time.Time) Metadata {
+	return Metadata{
+		SchemaVersion: SchemaVersion,
+		Tool:          ToolName,
+		ToolVersion:   toolVersion,
+		Command:       command,
+		GeneratedAt:   generatedAt,
+		Period:        NewPeriod(dateRange),
+	}
+}
+
+// Target は分析対象のチャンネルまたはユーザー
+type Target struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Period は分析期間（無制限の側は null）
+type Period struct {
+	Start *time.Time `json:"start"`
+	End   *time.Time `json:"end"`
+}
+
+// NewPeriod は期間をJSON出力の形式に変換する
+func NewPeriod(dateRange *domain.DateRange) Period {
+	var p Period
+	if dateRange == nil {
+		return p
+	}
+	if !dateRange.Start.IsZero() {
+		p.Start = &dateRange.Start
+	}
+	if !dateRange.End.IsZero() {
+		p.End = &dateRange.End
+	}
+	return p
+}
+
+// ChannelDocument はチャンネル分析（検索の分析を含む）のJSON出力
+type ChannelDocument struct {
+	Metadata Metadata `json:"metadata"`
+	ChannelRankings
+}
+
+// ChannelRankings はチャンネル分析結果の集計と各ランキング
+type ChannelRankings struct {
+	TotalMessages  int                      `json:"total_messages"`
+	TotalReactions int                      `json:"total_reactions"`
+	Emoji          []domain.EmojiCount      `json:"emoji"`
+	Messages       []domain.MessageReaction `json:"messages"`
+	Threads        []domain.ThreadStats     `json:"threads"`
+	Users          []domain.UserStats       `json:"users"`
+	Warnings       []string                 `json:"warnings"`
+}
+
+// NewChannelDocument はチャンネル分析結果をJSON出力の形式に変換する（各ランキングは表示件数まで）
+func NewChannelDocument(meta Metadata, result *service.AnalysisResult, limits Limits) *ChannelDocument {
+	return &ChannelDocument{Metadata: meta, ChannelRankings: newChannelRankings(result, limits)}
+}
+
+// newChannelRankings はチャンネル分析結果の各ランキングを表示件数までにする
+func newChannelRankings(result *service.AnalysisResult, limits Limits) ChannelRankings {
+	return ChannelRankings{
+		TotalMessages:  result.TotalMessages(),
+		TotalReactions: result.TotalReactions(),
+		Emoji:          nonNil(head(result.EmojiStats, limits.Emoji)),
+		Messages:       nonNil(head(result.MessageStats, limits.Message)),
+		Threads:        nonNil(head(result.ThreadStats, limits.Thread)),
+		Users:          nonNil(head(result.UserStats, limits.User)),
+		Warnings:       nonNil(result.Warnings),
+	}
+}
+
+// MultiChannelDocument は複数チャンネル・ワークスペースの分析のJSON出力
+// 全チャンネルを合算した結果に加えて、チャンネルごとの結果を channels に含める
+type MultiChannelDocument struct {
+	ChannelDocument
+	Channels []ChannelBreakdown `json:"channels"`
+}
+
+// ChannelBreakdown は複数チャンネルの分析のうち、1つのチャンネルの結果
+type ChannelBreakdown struct {
+	Channel Target `json:"channel"`
+	ChannelRankings
+}
+
+// NewMultiChannelDocument は複数チャンネルの分析結果をJSON出力の形式に変換する（各ランキングは表示件数まで）
+// チャンネルごとの結果は分析したチャンネルの順に並べる
+func NewMultiChannelDocument(meta Metadata, result *service.MultiChannelResult, limits Limits) *MultiChannelDocument {
+	doc := &MultiChannelDocument{
+		ChannelDocument: *NewChannelDocument(meta, result.Merged, limits),
+		Channels:        make([]ChannelBreakdown, 0, len(result.Channels)),
+	}
+	for _, ch := range result.Channels {
+		doc.Channels = append(doc.Channels, ChannelBreakdown{
+			Channel:         Target{ID: ch.Channel.ID, Name: ch.Channel.Name},
+			ChannelRankings: newChannelRankings(ch.Result, limits),
+		})
+	}
+	return doc
+}
+
+// UserDocument はユーザー分析のJSON出力
+type UserDocument struct {
+	Metadata       Metadata             `json:"metadata"`
+	TotalMessages  int                  `json:"total_messages"`
+	TotalReactions int                  `json:"total_reactions"`
+	Emoji          []domain.EmojiCount  `json:"emoji"`
+	Threads        []domain.ThreadStats `json:"threads"`
+	Warnings       []string             `json:"warnings"`
+}
+
+// NewUserDocument はユーザー分析結果をJSON出力の形式に変換する（各ランキングは表示件数まで）
+func NewUserDocument(meta Metadata, result *service.UserAnalysisResult, limits Limits) *UserDocument {
+	return &UserDocument{
+		Metadata:       meta,
+		TotalMessages:  result.TotalMessages,
+		TotalReactions: result.TotalReactions,
+		Emoji:          nonNil(head(result.ReactionRanking, limits.Emoji)),
+		Threads:        nonNil(head(result.ThreadStats, limits.Thread)),
+		Warnings:       nonNil(result.Warnings),
+	}
+}
+
+// WriteChannelJSON はチャンネル分析結果をJSON形式で出力する
+func WriteChannelJSON(w io.Writer, meta Metadata, result *service.AnalysisResult, limits Limits) error {
+	return writeJSON(w, NewChannelDocument(meta, result, limits))
+}
+
+// WriteMultiChannelJSON は複数チャンネル・ワークスペースの分析結果をJSON形式で出力する
+func WriteMultiChannelJSON(w io.Writer, meta Metadata, result *service.MultiChannelResult, limits Limits) error {
+	return writeJSON(w, NewMultiChannelDocument(meta, result, limits))
+}
+
+// WriteUserJSON はユーザー分析結果をJSON形式で出力する
+func WriteUserJSON(w io.Writer, meta Metadata, result *service.UserAnalysisResult, limits Limits) error {
+	return writeJSON(w, NewUserDocument(meta, result, limits))
+}
+
+// writeJSON はインデントしたJSONを出力する（HTMLの特殊文字はエスケープしない）
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// nonNil はJSONで null ではなく空配列として出力するため、nilのスライスを空のスライスに置き換える
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,167 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+func TestWriteChannelJSON(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	posted := time.Date(2024, 1, 10, 9, 30, 0, 0, jst)
+	result := &service.AnalysisResult{
+		EmojiStats: []domain.EmojiCount{
+			{Emoji: "+1", Count: 45},
+			{Emoji: "eyes", Count: 32},
+		},
+		MessageStats: []domain.MessageReaction{
+			{Text: "<リリース> & お知らせ", Reactions: 15, Timestamp: posted, UserID: "U1", ChannelID: "C1", MessageID: "1704846600.000100"},
+		},
+		UserStats: []domain.UserStats{
+			{UserID: "U1", UserName: "田中太郎", Count: 156},
+		},
+		UserMessageCount: map[string]int{"U1": 156},
+	}
+	dateRange, err := domain.ParseDateRange("2024-01-01", "", jst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := NewMetadata("channel", "v1.2.3", dateRange, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+	meta.Channel = &Target{ID: "C1", Name: "general"}
+
+	var buf bytes.Buffer
+	if err := WriteChannelJSON(&buf, meta, result, Limits{Emoji: 1, Message: 3, User: 3, Thread: 3}); err != nil {
+		t.Fatalf("WriteChannelJSON() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		`"schema_version": 1`,
+		`"tool": "slack-reaction"`,
+		`"tool_version": "v1.2.3"`,
+		`"command": "channel"`,
+		`"generated_at": "2024-02-01T12:00:00Z"`,
+		`"channel": {
+      "id": "C1",
+      "name": "general"
+    }`,
+		`"period": {
+      "start": "2024-01-01T00:00:00+09:00",
+      "end": null
+    }`,
+		`"total_messages": 156`,
+		`"text": "<リリース> & お知らせ"`,
+		`"timestamp": "2024-01-10T09:30:00+09:00"`,
+		`"message_id": "1704846600.000100"`,
+		`"user_name": "田中太郎"`,
+		`"threads": []`,
+		`"warnings": []`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %s\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "eyes") {
+		t.Errorf("output contains entries beyond the limit\n%s", got)
+	}
+	if strings.Contains(got, `"user":`) || strings.Contains(got, `"query":`) {
+		t.Errorf("output contains fields for other commands\n%s", got)
+	}
+}
+
+func TestWriteUserJSON(t *testing.T) {
+	result := &service.UserAnalysisResult{
+		UserID:         "U1",
+		UserName:       "田中太郎",
+		TotalMessages:  156,
+		TotalReactions: 342,
+		ReactionRanking: []domain.EmojiCount{
+			{Emoji: "+1", Count: 89},
+		},
+		Warnings: []string{"スレッド返信の取得に失敗しました"},
+	}
+	meta := NewMetadata("user", "dev", nil, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+	meta.User = &Target{ID: result.UserID, Name: result.UserName}
+
+	var buf bytes.Buffer
+	if err := WriteUserJSON(&buf, meta, result, DefaultUserLimits); err != nil {
+		t.Fatalf("WriteUserJSON() error = %v", err)
+	}
+
+	var doc UserDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not valid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.Metadata.User == nil || doc.Metadata.User.ID != "U1" {
+		t.Errorf("metadata.user = %+v, want U1", doc.Metadata.User)
+	}
+	if doc.Metadata.Period.Start != nil || doc.Metadata.Period.End != nil {
+		t.Errorf("period = %+v, want unbounded", doc.Metadata.Period)
+	}
+	if doc.TotalMessages != 156 || doc.TotalReactions != 342 {
+		t.Errorf("totals = %d/%d, want 156/342", doc.TotalMessages, doc.TotalReactions)
+	}
+	if len(doc.Emoji) != 1 || doc.Emoji[0] != (domain.EmojiCount{Emoji: "+1", Count: 89}) {
+		t.Errorf("emoji = %+v", doc.Emoji)
+	}
+	if len(doc.Warnings) != 1 {
+		t.Errorf("warnings = %v", doc.Warnings)
+	}
+}
+
+// newMultiChannelResult は2チャンネルの分析結果を作成する
+func newMultiChannelResult() *service.MultiChannelResult {
+	return &service.MultiChannelResult{
+		Merged: &service.AnalysisResult{
+			EmojiStats:       []domain.EmojiCount{{Emoji: "+1", Count: 5}},
+			MessageStats:     []domain.MessageReaction{{Text: "リリースしました", Reactions: 5, UserID: "U1", ChannelID: "C2", MessageID: "1700000000.123456"}},
+			UserStats:        []domain.UserStats{{UserID: "U1", UserName: "田中太郎", Count: 3}},
+			UserMessageCount: map[string]int{"U1": 3},
+		},
+		Channels: []service.ChannelAnalysis{
+			{
+				Channel: &domain.Channel{ID: "C1", Name: "team-a"},
+				Result:  &service.AnalysisResult{UserMessageCount: map[string]int{"U1": 1}},
+			},
+			{
+				Channel: &domain.Channel{ID: "C2", Name: "team-b"},
+				Result: &service.AnalysisResult{
+					EmojiStats:       []domain.EmojiCount{{Emoji: "+1", Count: 5}},
+					UserMessageCount: map[string]int{"U1": 2},
+				},
+			},
+		},
+	}
+}
+
+func TestWriteMultiChannelJSON(t *testing.T) {
+	meta := NewMetadata("channels", "v1.2.3", nil, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+
+	var buf bytes.Buffer
+	if err := WriteMultiChannelJSON(&buf, meta, newMultiChannelResult(), DefaultChannelLimits); err != nil {
+		t.Fatalf("WriteMultiChannelJSON() error = %v", err)
+	}
+
+	var doc MultiChannelDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.Metadata.Command != "channels" || doc.TotalMessages != 3 || doc.TotalReactions != 5 || len(doc.Messages) != 1 {
+		t.Errorf("merged = %+v", doc.ChannelDocument)
+	}
+	if len(doc.Channels) != 2 {
+		t.Fatalf("channels = %d, want 2", len(doc.Channels))
+	}
+	second := doc.Channels[1]
+	if second.Channel != (Target{ID: "C2", Name: "team-b"}) || second.TotalMessages != 2 || second.TotalReactions != 5 {
+		t.Errorf("channels[1] = %+v", second)
+	}
+	if second.Messages == nil || second.Warnings == nil {
+		t.Errorf("channels[1] has null arrays\n%s", buf.String())
+	}
+}
@@ -0,0 +1,215 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// MarkdownOptions はMarkdown形式の出力のオプション
+type MarkdownOptions struct {
+	Link    MessageLinker // メッセージへのリンクを作成する（nilの場合はリンクにしない）
+	Mermaid bool          // スタンプの内訳をMermaidの円グラフで表示する
+}
+
+// WriteChannelMarkdown はチャンネル分析結果（検索の分析を含む）をMarkdown形式で出力する
+func WriteChannelMarkdown(w io.Writer, title string, dateRange *domain.DateRange, result *service.AnalysisResult, limits Limits, opts MarkdownOptions) error {
+	var b strings.Builder
+
+	writeMarkdownHeader(&b, title, dateRange, fmt.Sprintf("投稿数: %d件 / スタンプ数: %d回", result.TotalMessages(), result.TotalReactions()))
+	writeChannelMarkdownSections(&b, result, limits, opts, nil)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMultiChannelMarkdown は複数チャンネル・ワークスペースの分析結果をMarkdown形式で出力する
+// 全チャンネルを合算したランキングの前に、チャンネルごとの投稿数とスタンプ数を表示する
+func WriteMultiChannelMarkdown(w io.Writer, title string, dateRange *domain.DateRange, result *service.MultiChannelResult, limits Limits, opts MarkdownOptions) error {
+	var b strings.Builder
+
+	writeMarkdownHeader(&b, title, dateRange, fmt.Sprintf("%dチャンネル / 投稿数: %d件 / スタンプ数: %d回", len(result.Channels), result.Merged.TotalMessages(), result.Merged.TotalReactions()))
+
+	b.WriteString("## 最も投稿数が多いチャンネル\n\n")
+	var channels [][]string
+	for i, activity := range topChannels(result, limits) {
+		channels = append(channels, []string{fmt.Sprint(i + 1), "#" + escapeMarkdown(activity.Channel.Name), fmt.Sprint(activity.Messages), fmt.Sprint(activity.Reactions)})
+	}
+	writeMarkdownTable(&b, []string{"順位", "チャンネル", "投稿数", "スタンプ数"}, channels)
+
+	writeChannelMarkdownSections(&b, result.Merged, limits, opts, result.ChannelName)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeChannelMarkdownSections はスタンプ・メッセージ・投稿者・スレッドのランキングを書き込む
+// channelName が指定された場合は、メッセージに投稿先のチャンネル名を付記する
+func writeChannelMarkdownSections(b *strings.Builder, result *service.AnalysisResult, limits Limits, opts MarkdownOptions, channelName func(string) string) {
+	userNames := make(map[string]string, len(result.UserStats))
+	for _, stat := range result.UserStats {
+		userNames[stat.UserID] = stat.UserName
+	}
+	poster := func(userID string) string {
+		if name, ok := userNames[userID]; ok {
+			return name
+		}
+		return userID
+	}
+	channel := func(channelID string) string {
+		if channelName == nil || channelID == "" {
+			return ""
+		}
+		return channelName(channelID)
+	}
+
+	writeEmojiMarkdown(b, "最も使用されたスタンプ", result.EmojiStats, result.TotalReactions(), limits.Emoji, opts.Mermaid)
+
+	fmt.Fprintf(b, "## 最もリアクションがついたメッセージ TOP%d\n\n", limits.Message)
+	var messages []markdownExcerpt
+	for _, stat := range head(result.MessageStats, limits.Message) {
+		messages = append(messages, markdownExcerpt{
+			text: stat.Text, summary: fmt.Sprintf("%dリアクション", stat.Reactions), poster: poster(stat.UserID), channel: channel(stat.ChannelID),
+			url: messageURL(opts.Link, stat.ChannelID, stat.MessageID),
+		})
+	}
+	writeExcerpts(b, messages)
+
+	fmt.Fprintf(b, "## 最も投稿数が多いユーザー TOP%d\n\n", limits.User)
+	var users [][]string
+	for i, stat := range head(result.UserStats, limits.User) {
+		users = append(users, []string{fmt.Sprint(i + 1), escapeMarkdown(stat.UserName), fmt.Sprint(stat.Count)})
+	}
+	writeMarkdownTable(b, []string{"順位", "ユーザー", "投稿数"}, users)
+
+	fmt.Fprintf(b, "## 最もスレッドのコメント数が多い投稿 TOP%d\n\n", limits.Thread)
+	var threads []markdownExcerpt
+	for _, stat := range head(result.ThreadStats, limits.Thread) {
+		threads = append(threads, markdownExcerpt{
+			text: stat.Text, summary: fmt.Sprintf("%dコメント", stat.ReplyCount), poster: poster(stat.UserID), channel: channel(stat.ChannelID),
+			url: messageURL(opts.Link, stat.ChannelID, stat.MessageID),
+		})
+	}
+	writeExcerpts(b, threads)
+}
+
+// WriteUserMarkdown はユーザー分析結果をMarkdown形式で出力する
+func WriteUserMarkdown(w io.Writer, dateRange *domain.DateRange, result *service.UserAnalysisResult, limits Limits, opts MarkdownOptions) error {
+	var b strings.Builder
+
+	writeMarkdownHeader(&b, "ユーザー分析結果: "+result.UserName, dateRange, fmt.Sprintf("投稿総数: %d件 / スタンプ総数: %d回", result.TotalMessages, result.TotalReactions))
+
+	fmt.Fprintf(&b, "## 投稿についたコメントが多いスレッド TOP%d\n\n", limits.Thread)
+	var threads []markdownExcerpt
+	for _, stat := range head(result.ThreadStats, limits.Thread) {
+		threads = append(threads, markdownExcerpt{
+			text: stat.Text, summary: fmt.Sprintf("%dコメント", stat.ReplyCount), url: messageURL(opts.Link, stat.ChannelID, stat.MessageID),
+		})
+	}
+	writeExcerpts(&b, threads)
+
+	writeEmojiMarkdown(&b, "投稿についたスタンプ", result.ReactionRanking, result.TotalReactions, limits.Emoji, opts.Mermaid)
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMarkdownHeader はタイトル、期間と概要を書き込む
+func writeMarkdownHeader(b *strings.Builder, title string, dateRange *domain.DateRange, summary string) {
+	fmt.Fprintf(b, "# %s\n\n", escapeMarkdown(title))
+	fmt.Fprintf(b, "- 期間: %s\n", FormatDateRange(dateRange))
+	fmt.Fprintf(b, "- %s\n\n", summary)
+}
+
+// writeEmojiMarkdown はスタンプのランキングを表で書き込む
+// mermaid が指定された場合は、表の前にランキングの上位と「その他」の内訳を円グラフで表示する
+func writeEmojiMarkdown(b *strings.Builder, heading string, stats []domain.EmojiCount, total, limit int, mermaid bool) {
+	fmt.Fprintf(b, "## %s TOP%d\n\n", heading, limit)
+	top := head(stats, limit)
+
+	if mermaid && len(top) > 0 {
+		b.WriteString("```mermaid\npie showData\n")
+		others := total
+		for _, stat := range top {
+			fmt.Fprintf(b, "    %q : %d\n", ":"+stat.Emoji+":", stat.Count)
+			others -= stat.Count
+		}
+		if others > 0 {
+			fmt.Fprintf(b, "    %q : %d\n", "その他", others)
+		}
+		b.WriteString("```\n\n")
+	}
+
+	var rows [][]string
+	for i, stat := range top {
+		rows = append(rows, []string{fmt.Sprint(i + 1), ":" + stat.Emoji + ":", fmt.Sprint(stat.Count)})
+	}
+	writeMarkdownTable(b, []string{"順位", "スタンプ", "回数"}, rows)
+}
+
+// writeMarkdownTable は表を書き込む（行がない場合は「該当なし」）
+func writeMarkdownTable(b *strings.Builder, header []string, rows [][]string) {
+	if len(rows) == 0 {
+		b.WriteString("_該当なし_\n\n")
+		return
+	}
+	b.WriteString("| " + strings.Join(header, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(header)) + "\n")
+	for _, row := range rows {
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+	b.WriteString("\n")
+}
+
+// markdownExcerpt は引用して表示するメッセージ
+type markdownExcerpt struct {
+	text    string
+	summary string // リアクション数・コメント数
+	poster  string // 投稿者（空文字列の場合は表示しない）
+	channel string // 投稿先のチャンネル名（空文字列の場合は表示しない）
+	url     string // メッセージのURL（空文字列の場合はリンクにしない）
+}
+
+// writeExcerpts はメッセージのランキングを、順位・件数・投稿者・リンクとメッセージの引用で書き込む
+func writeExcerpts(b *strings.Builder, excerpts []markdownExcerpt) {
+	if len(excerpts) == 0 {
+		b.WriteString("_該当なし_\n\n")
+		return
+	}
+	for i, excerpt := range excerpts {
+		fmt.Fprintf(b, "%d. **%s**", i+1, excerpt.summary)
+		if excerpt.poster != "" {
+			fmt.Fprintf(b, " — %s", escapeMarkdown(excerpt.poster))
+		}
+		if excerpt.channel != "" {
+			fmt.Fprintf(b, " — #%s", escapeMarkdown(excerpt.channel))
+		}
+		if excerpt.url != "" {
+			fmt.Fprintf(b, " — [Slackで開く](%s)", excerpt.url)
+		}
+		text := escapeMarkdown(Preview(excerpt.text))
+		if text == "" {
+			text = "（本文なし）"
+		}
+		fmt.Fprintf(b, "\n\n   > %s\n\n", text)
+	}
+}
+
+// messageURL はメッセージのURLを返す（リンクを作成できない場合は空文字列）
+func messageURL(link MessageLinker, channelID, ts string) string {
+	if link == nil {
+		return ""
+	}
+	return link(channelID, ts)
+}
+
+// escapeMarkdown はMarkdownの書式や表の区切りとして解釈される文字をエスケープする
+func escapeMarkdown(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+		"<", "&lt;", ">", "&gt;", "|", `\|`, "#", `\#`,
+	).Replace(text)
+}
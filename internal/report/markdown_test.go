@@ -0,0 +1,128 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+func TestWriteChannelMarkdown(t *testing.T) {
+	result := &service.AnalysisResult{
+		EmojiStats: []domain.EmojiCount{
+			{Emoji: "+1", Count: 45},
+			{Emoji: "eyes", Count: 32},
+			{Emoji: "tada", Count: 3},
+		},
+		MessageStats: []domain.MessageReaction{
+			{Text: "*リリース* | 告知", Reactions: 15, UserID: "U1", ChannelID: "C1", MessageID: "1700000000.123456"},
+		},
+		UserStats: []domain.UserStats{
+			{UserID: "U1", UserName: "田中_太郎", Count: 156},
+		},
+		UserMessageCount: map[string]int{"U1": 156},
+	}
+	dateRange, err := domain.ParseDateRange("2024-01-01", "2024-01-31", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	link := func(channelID, ts string) string {
+		return "https://example.slack.com/archives/" + channelID + "/p" + strings.Replace(ts, ".", "", 1)
+	}
+
+	tests := []struct {
+		name     string
+		opts     MarkdownOptions
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "リンクとMermaidあり",
+			opts: MarkdownOptions{Link: link, Mermaid: true},
+			contains: []string{
+				"# \\#general の分析結果\n\n- 期間: 2024-01-01 〜 2024-01-31\n- 投稿数: 156件 / スタンプ数: 80回\n\n",
+				"```mermaid\npie showData\n    \":+1:\" : 45\n    \":eyes:\" : 32\n    \"その他\" : 3\n```\n\n",
+				"| 順位 | スタンプ | 回数 |\n| --- | --- | --- |\n| 1 | :+1: | 45 |\n",
+				"1. **15リアクション** — 田中\\_太郎 — [Slackで開く](https://example.slack.com/archives/C1/p1700000000123456)\n\n   > \\*リリース\\* \\| 告知\n\n",
+				"| 1 | 田中\\_太郎 | 156 |\n",
+				"## 最もスレッドのコメント数が多い投稿 TOP3\n\n_該当なし_\n\n",
+			},
+		},
+		{
+			name:     "リンクなし",
+			opts:     MarkdownOptions{},
+			contains: []string{"1. **15リアクション** — 田中\\_太郎\n\n"},
+			excludes: []string{"```mermaid", "Slackで開く"},
+		},
+	}
+
+	limits := Limits{Emoji: 2, Message: 3, User: 10, Thread: 3}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteChannelMarkdown(&buf, "#general の分析結果", dateRange, result, limits, tt.opts); err != nil {
+				t.Fatalf("WriteChannelMarkdown() error = %v", err)
+			}
+			got := buf.String()
+			for _, want := range tt.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("output does not contain %q\n%s", want, got)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(got, unwanted) {
+					t.Errorf("output contains %q\n%s", unwanted, got)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteUserMarkdown(t *testing.T) {
+	result := &service.UserAnalysisResult{
+		UserName:       "田中太郎",
+		TotalMessages:  156,
+		TotalReactions: 342,
+		ThreadStats: []domain.ThreadStats{
+			{Text: "新機能のリリースについて", ReplyCount: 25, ChannelID: "C1", MessageID: "1.000"},
+		},
+		ReactionRanking: []domain.EmojiCount{{Emoji: "+1", Count: 89}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteUserMarkdown(&buf, nil, result, DefaultUserLimits, MarkdownOptions{}); err != nil {
+		t.Fatalf("WriteUserMarkdown() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"# ユーザー分析結果: 田中太郎\n\n- 期間: 全期間\n- 投稿総数: 156件 / スタンプ総数: 342回\n\n",
+		"1. **25コメント**\n\n   > 新機能のリリースについて\n\n",
+		"| 1 | :+1: | 89 |\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteMultiChannelMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMultiChannelMarkdown(&buf, "2チャンネルの分析結果", nil, newMultiChannelResult(), DefaultChannelLimits, MarkdownOptions{}); err != nil {
+		t.Fatalf("WriteMultiChannelMarkdown() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"- 2チャンネル / 投稿数: 3件 / スタンプ数: 5回\n\n",
+		"## 最も投稿数が多いチャンネル\n\n| 順位 | チャンネル | 投稿数 | スタンプ数 |\n| --- | --- | --- | --- |\n| 1 | #team-b | 2 | 5 |\n| 2 | #team-a | 1 | 0 |\n\n",
+		"1. **5リアクション** — 田中太郎 — #team-b\n\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
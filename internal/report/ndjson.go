@@ -0,0 +1,67 @@
+package report
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// MessageRecord は dump コマンドがNDJSONの1行として出力するメッセージ（docs/dump.md）
+// 全ての行が同じキーを持つよう、値がない場合も省略しない
+type MessageRecord struct {
+	ChannelID      string            `json:"channel_id"`
+	TS             string            `json:"ts"`        // Slackのタイムスタンプ（メッセージのID、例: "1705280000.000100"）
+	Time           time.Time         `json:"time"`      // ts の日時（UTC、ts と同じくマイクロ秒まで）
+	UserID         string            `json:"user_id"`   // 投稿者のユーザーID
+	Text           string            `json:"text"`      // 本文（Slackのmrkdwn形式のまま）
+	IsBot          bool              `json:"is_bot"`    // ボットの投稿か
+	ThreadTS       *string           `json:"thread_ts"` // スレッドの親メッセージの ts（スレッドでない場合は null）
+	IsThreadParent bool              `json:"is_thread_parent"`
+	IsThreadReply  bool              `json:"is_thread_reply"`
+	ReactionCount  int               `json:"reaction_count"` // リアクションの合計数
+	Reactions      []domain.Reaction `json:"reactions"`      // リアクションのない場合は空の配列
+}
+
+// NewMessageRecord はメッセージをNDJSONの1行の形式に変換する
+func NewMessageRecord(msg *domain.Message) MessageRecord {
+	record := MessageRecord{
+		ChannelID:      msg.ChannelID,
+		TS:             msg.ID,
+		Time:           msg.PostedAt().UTC(),
+		UserID:         msg.UserID,
+		Text:           msg.Text,
+		IsBot:          msg.IsBot,
+		IsThreadParent: msg.IsThreadParent(),
+		IsThreadReply:  msg.IsThreadReply(),
+		ReactionCount:  msg.TotalReactionCount(),
+		Reactions:      msg.Reactions,
+	}
+	if msg.ThreadTS != "" {
+		threadTS := msg.ThreadTS
+		record.ThreadTS = &threadTS
+	}
+	if record.Reactions == nil {
+		record.Reactions = []domain.Reaction{}
+	}
+	return record
+}
+
+// MessageWriter はメッセージを1行に1つのJSON（NDJSON）として書き出す
+type MessageWriter struct {
+	enc *json.Encoder
+}
+
+// NewMessageWriter は w に書き出すMessageWriterを作成する
+// 本文のメンション（<@U123>）などを読みやすく保つため、< > & はエスケープしない
+func NewMessageWriter(w io.Writer) *MessageWriter {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &MessageWriter{enc: enc}
+}
+
+// Write はメッセージを1行書き出す
+func (w *MessageWriter) Write(msg *domain.Message) error {
+	return w.enc.Encode(NewMessageRecord(msg))
+}
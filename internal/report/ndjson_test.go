@@ -0,0 +1,77 @@
+package report
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestMessageWriter(t *testing.T) {
+	messages := []*domain.Message{
+		{ID: "1705280000.000100", Text: "<@U2> リリースしました & 確認お願いします", UserID: "U1", ChannelID: "C1",
+			Timestamp: time.Unix(1705280000, 0), ThreadTS: "1705280000.000100",
+			Reactions: []domain.Reaction{{Name: "tada", Count: 3}, {Name: "+1::skin-tone-2", Count: 1}}},
+		{ID: "1705280100.123456", Text: "確認しました", UserID: "U2", ChannelID: "C1",
+			Timestamp: time.Unix(1705280100, 0), ThreadTS: "1705280000.000100"},
+		{ID: "1705280200.000000", Text: "別の話題", UserID: "U3", ChannelID: "C2", Timestamp: time.Unix(1705280200, 0)},
+	}
+
+	var buf bytes.Buffer
+	w := NewMessageWriter(&buf)
+	for _, msg := range messages {
+		if err := w.Write(msg); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != len(messages) {
+		t.Fatalf("got %d lines, want %d\n%s", len(lines), len(messages), buf.String())
+	}
+	// 本文の < > & はエスケープしない
+	if !strings.Contains(lines[0], `"text":"<@U2> リリースしました & 確認お願いします"`) {
+		t.Errorf("line 0 = %s", lines[0])
+	}
+
+	var records []MessageRecord
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record MessageRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("json.Unmarshal(%s) error = %v", scanner.Text(), err)
+		}
+		records = append(records, record)
+	}
+
+	parent, reply, plain := records[0], records[1], records[2]
+	if parent.ChannelID != "C1" || parent.TS != "1705280000.000100" || !parent.IsThreadParent || parent.IsThreadReply {
+		t.Errorf("parent = %+v", parent)
+	}
+	if parent.ReactionCount != 4 || len(parent.Reactions) != 2 || parent.Reactions[1].Name != "+1::skin-tone-2" {
+		t.Errorf("parent reactions = %d %+v", parent.ReactionCount, parent.Reactions)
+	}
+	if want := time.Date(2024, 1, 15, 0, 53, 20, 100_000, time.UTC); !parent.Time.Equal(want) {
+		t.Errorf("parent.Time = %v, want %v", parent.Time, want)
+	}
+	if reply.ThreadTS == nil || *reply.ThreadTS != parent.TS || !reply.IsThreadReply || reply.IsThreadParent {
+		t.Errorf("reply = %+v", reply)
+	}
+	if got := reply.Time.Nanosecond(); got != 123_456_000 {
+		t.Errorf("reply.Time.Nanosecond() = %d, want 123456000", got)
+	}
+	if plain.ThreadTS != nil || plain.IsThreadParent || plain.IsThreadReply {
+		t.Errorf("plain = %+v", plain)
+	}
+	// リアクションのないメッセージも空の配列を出力する
+	if !strings.Contains(lines[2], `"thread_ts":null`) || !strings.Contains(lines[2], `"reactions":[]`) {
+		t.Errorf("line 2 = %s", lines[2])
+	}
+	if !strings.Contains(lines[1], `"time":"2024-01-15T00:55:00.123456Z"`) {
+		t.Errorf("line 1 = %s", lines[1])
+	}
+}
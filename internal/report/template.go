@@ -0,0 +1,297 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/Tattsum/slack-reaction/internal/textwidth"
+)
+
+//go:embed text.tmpl
+var textTemplateSource string
+
+// textTemplate はテキスト形式の出力に使用するデフォルトのテンプレート
+// channel / search / multichannel / workspace / user の各テンプレートを定義する
+var textTemplate = template.Must(template.New("text").Funcs(TemplateFuncs()).Parse(textTemplateSource))
+
+// TemplateData はテンプレートに渡すデータ（docs/template.md）
+type TemplateData struct {
+	Meta         Metadata                    // 実行情報（コマンド、対象、期間など）
+	Title        string                      // 分析結果のタイトル
+	Limits       Limits                      // 各ランキングの表示件数
+	Result       *service.AnalysisResult     // チャンネル分析・検索・複数チャンネル分析（合算）の結果
+	MultiChannel *service.MultiChannelResult // 複数チャンネル分析の結果（channels / workspace のみ）
+	User         *service.UserAnalysisResult // ユーザー分析の結果（user のみ）
+	CrossChannel bool                        // Result が複数チャンネルのメッセージを含む
+	TextOptions                              // グラフの表示（.Charts）と表示幅（.Width）
+
+	name        string // デフォルトのテンプレートのうち出力に使用するテンプレート
+	channelName func(string) string
+}
+
+// NewChannelTemplateData はチャンネル分析結果のテンプレートのデータを作成する
+func NewChannelTemplateData(meta Metadata, title string, result *service.AnalysisResult, limits Limits) *TemplateData {
+	return &TemplateData{Meta: meta, Title: title, Limits: limits, Result: result, name: "channel"}
+}
+
+// NewSearchTemplateData は検索クエリに一致したメッセージの分析結果のテンプレートのデータを作成する
+// channelName が指定された場合は、チャンネルIDからチャンネル名を引けるようにする
+func NewSearchTemplateData(meta Metadata, title string, result *service.AnalysisResult, limits Limits, channelName func(string) string) *TemplateData {
+	return &TemplateData{Meta: meta, Title: title, Limits: limits, Result: result, CrossChannel: channelName != nil, name: "search", channelName: channelName}
+}
+
+// NewMultiChannelTemplateData は複数チャンネルの分析結果のテンプレートのデータを作成する
+func NewMultiChannelTemplateData(meta Metadata, title string, result *service.MultiChannelResult, limits Limits) *TemplateData {
+	return &TemplateData{
+		Meta: meta, Title: title, Limits: limits, Result: result.Merged, MultiChannel: result, CrossChannel: true, name: "multichannel", channelName: result.ChannelName,
+	}
+}
+
+// NewWorkspaceTemplateData はワークスペース全体の分析結果のテンプレートのデータを作成する
+func NewWorkspaceTemplateData(meta Metadata, title string, result *service.MultiChannelResult, limits Limits) *TemplateData {
+	data := NewMultiChannelTemplateData(meta, title, result, limits)
+	data.name = "workspace"
+	return data
+}
+
+// NewUserTemplateData はユーザー分析結果のテンプレートのデータを作成する
+func NewUserTemplateData(meta Metadata, title string, result *service.UserAnalysisResult, limits Limits) *TemplateData {
+	return &TemplateData{Meta: meta, Title: title, Limits: limits, User: result, name: "user"}
+}
+
+// ChannelName はチャンネルIDに対応するチャンネル名を返す（わからない場合はID）
+func (d *TemplateData) ChannelName(channelID string) string {
+	if d.channelName != nil {
+		return d.channelName(channelID)
+	}
+	if d.Meta.Channel != nil && d.Meta.Channel.ID == channelID {
+		return d.Meta.Channel.Name
+	}
+	return channelID
+}
+
+// UserName はユーザーIDに対応するユーザー名を返す（投稿数のランキングにない場合はID）
+func (d *TemplateData) UserName(userID string) string {
+	if d.User != nil && d.User.UserID == userID {
+		return d.User.UserName
+	}
+	if d.Result != nil {
+		for _, stat := range d.Result.UserStats {
+			if stat.UserID == userID {
+				return stat.UserName
+			}
+		}
+	}
+	return userID
+}
+
+// ForChannel は複数チャンネル分析のうち1つのチャンネルの結果を Result とするデータを返す
+func (d *TemplateData) ForChannel(ch service.ChannelAnalysis) *TemplateData {
+	meta := d.Meta
+	meta.Channel = &Target{ID: ch.Channel.ID, Name: ch.Channel.Name}
+	data := NewChannelTemplateData(meta, "#"+ch.Channel.Name+" の分析結果", ch.Result, d.Limits)
+	data.TextOptions = d.TextOptions
+	return data
+}
+
+// Activity は投稿数の推移を返す（user コマンドではユーザー本人の投稿のみ）
+// 期間の単位は分析期間の長さから決める
+func (d *TemplateData) Activity() *Activity {
+	var messages []*domain.Message
+	switch {
+	case d.Result != nil:
+		messages = d.Result.Messages
+	case d.User != nil:
+		for _, msg := range d.User.Messages {
+			if msg.UserID == d.User.UserID {
+				messages = append(messages, msg)
+			}
+		}
+	}
+	return newActivity(messages, dateRangeOf(d.Meta.Period))
+}
+
+// EmojiBars はスタンプのランキングの先頭n件を横棒グラフで表す
+func (d *TemplateData) EmojiBars(n int, stats []domain.EmojiCount) string {
+	return barChart(emojiRows(stats, n), "回", d.lineWidth())
+}
+
+// UserBars は投稿数のランキングの先頭n件を横棒グラフで表す
+func (d *TemplateData) UserBars(n int, stats []domain.UserStats) string {
+	return barChart(userRows(stats, n), "投稿", d.lineWidth())
+}
+
+// MessageLines はリアクションが多いメッセージの先頭n件を、本文を表示幅に収めて1行ずつ表す
+func (d *TemplateData) MessageLines(n int, stats []domain.MessageReaction) string {
+	var rows []chartRow
+	for _, stat := range head(stats, n) {
+		rows = append(rows, chartRow{label: stat.Text, value: stat.Reactions, suffix: d.channelSuffix(stat.ChannelID)})
+	}
+	return messageLines(rows, "回", d.lineWidth())
+}
+
+// ThreadLines は返信が多いスレッドの先頭n件を、本文を表示幅に収めて1行ずつ表す
+func (d *TemplateData) ThreadLines(n int, stats []domain.ThreadStats) string {
+	var rows []chartRow
+	for _, stat := range head(stats, n) {
+		rows = append(rows, chartRow{label: stat.Text, value: stat.ReplyCount, suffix: d.channelSuffix(stat.ChannelID)})
+	}
+	return messageLines(rows, "件", d.lineWidth())
+}
+
+// channelSuffix は CrossChannel の場合に、メッセージに付記する投稿先のチャンネル名を返す
+func (d *TemplateData) channelSuffix(channelID string) string {
+	if !d.CrossChannel || channelID == "" {
+		return ""
+	}
+	return " (#" + d.ChannelName(channelID) + ")"
+}
+
+// TemplateFuncs はテンプレートで使用できるヘルパー関数を返す
+func TemplateFuncs() map[string]any {
+	return map[string]any{
+		"limit":    limitItems,
+		"rank":     func(i int) int { return i + 1 },
+		"preview":  Preview,
+		"truncate": truncate,
+		"emoji":    func(name string) string { return ":" + name + ":" },
+		"number":   formatNumber,
+		"percent":  formatPercent,
+		"mention":  func(userID string) string { return "<@" + userID + ">" },
+		"datetime": formatDateTime,
+		"width":    textwidth.Width,
+		"fit":      func(width int, text string) string { return textwidth.Truncate(text, width) },
+		"pad":      func(width int, text string) string { return textwidth.Pad(text, width) },
+		"sparkline": func(width int, values []int) string {
+			return Sparkline(values, width)
+		},
+	}
+}
+
+// limitItems はスライスの先頭n件を返す（nが0以下の場合は空）
+func limitItems(n int, items any) (any, error) {
+	v := reflect.ValueOf(items)
+	if !v.IsValid() {
+		return nil, nil
+	}
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("limit にはスライスを指定してください: %T", items)
+	}
+	return v.Slice(0, min(max(n, 0), v.Len())).Interface(), nil
+}
+
+// truncate は文字列をn文字までに切り詰める（切り詰めた場合は末尾に ... を付ける）
+func truncate(n int, text string) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
+	}
+	return string(runes[:max(n, 0)]) + "..."
+}
+
+// formatNumber は整数を3桁ごとにカンマで区切る
+func formatNumber(n int) string {
+	digits := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String()
+}
+
+// formatPercent は part の total に対する割合を小数点以下1桁の百分率で表す（total が0の場合は 0.0%）
+func formatPercent(part, total int) string {
+	if total == 0 {
+		return "0.0%"
+	}
+	return strconv.FormatFloat(float64(part)*100/float64(total), 'f', 1, 64) + "%"
+}
+
+// formatDateTime は日時を "2006-01-02 15:04" 形式で表す（不明な場合は空文字列）
+func formatDateTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// Template は利用者が用意した出力のテンプレート
+type Template struct {
+	execute func(w io.Writer, data any) error
+}
+
+// LoadTemplate はテンプレートのファイルを読み込む
+// ファイル名が .html / .htm / .html.tmpl で終わる場合は html/template、それ以外は text/template として解釈する
+// text/template の場合は、デフォルトのテンプレート（"sections" など）を呼び出せる
+func LoadTemplate(path string) (*Template, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("テンプレートの読み込みエラー: %w", err)
+	}
+	name := filepath.Base(path)
+	lower := strings.ToLower(name)
+	html := strings.HasSuffix(lower, ".html") || strings.HasSuffix(lower, ".htm") || strings.HasSuffix(lower, ".html.tmpl")
+	return ParseTemplate(name, string(src), html)
+}
+
+// ParseTemplate はテンプレートを解析する（html がtrueの場合は html/template として解釈する）
+func ParseTemplate(name, src string, html bool) (*Template, error) {
+	if html {
+		t, err := htmltemplate.New(name).Funcs(TemplateFuncs()).Parse(src)
+		if err != nil {
+			return nil, fmt.Errorf("テンプレートの解析エラー: %w", err)
+		}
+		return &Template{execute: t.Execute}, nil
+	}
+
+	t, err := template.Must(textTemplate.Clone()).New(name).Parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("テンプレートの解析エラー: %w", err)
+	}
+	return &Template{execute: t.Execute}, nil
+}
+
+// Execute はテンプレートにデータを適用して出力する
+// 途中でエラーになった場合は何も出力しない
+func (t *Template) Execute(w io.Writer, data *TemplateData) error {
+	var buf bytes.Buffer
+	if err := t.execute(&buf, data); err != nil {
+		return fmt.Errorf("テンプレートの実行エラー: %w", err)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// WriteText はデータの種類に応じたデフォルトのテンプレートでテキスト形式の出力をする
+func WriteText(w io.Writer, data *TemplateData) error {
+	return executeText(w, data.name, data)
+}
+
+// executeText はデフォルトのテンプレートのうち name のテンプレートでテキスト形式の出力をする
+func executeText(w io.Writer, name string, data *TemplateData) error {
+	var buf bytes.Buffer
+	if err := textTemplate.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,126 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+func testTemplateData() *TemplateData {
+	result := &service.AnalysisResult{
+		EmojiStats: []domain.EmojiCount{
+			{Emoji: "+1", Count: 1500},
+			{Emoji: "eyes", Count: 500},
+			{Emoji: "tada", Count: 1},
+		},
+		MessageStats: []domain.MessageReaction{
+			{Text: "<b>新機能</b>のリリースについてのお知らせです", Reactions: 15, UserID: "U1", ChannelID: "C1"},
+		},
+		UserStats:        []domain.UserStats{{UserID: "U1", UserName: "田中太郎", Count: 156}},
+		UserMessageCount: map[string]int{"U1": 156},
+	}
+	meta := NewMetadata("channel", "v1.2.3", nil, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+	meta.Channel = &Target{ID: "C1", Name: "general"}
+	return NewChannelTemplateData(meta, "#general の分析結果", result, Limits{Emoji: 2, Message: 1, User: 1, Thread: 1})
+}
+
+func TestParseTemplate(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		html bool
+		want string
+	}{
+		{
+			name: "ヘルパー関数",
+			src: `{{.Title}} ({{.Meta.Command}})
+{{range $i, $s := limit .Limits.Emoji .Result.EmojiStats}}{{rank $i}}. {{emoji $s.Emoji}} {{number $s.Count}} {{percent $s.Count $.Result.TotalReactions}}
+{{end}}{{range .Result.MessageStats}}{{truncate 10 .Text}} by {{mention .UserID}} {{$.UserName .UserID}} in #{{$.ChannelName .ChannelID}}
+{{end}}`,
+			want: "#general の分析結果 (channel)\n1. :+1: 1,500 75.0%\n2. :eyes: 500 25.0%\n<b>新機能</b>... by <@U1> 田中太郎 in #general\n",
+		},
+		{
+			name: "デフォルトのテンプレートの呼び出し",
+			src:  `# {{.Title}}{{"\n"}}{{template "sections" .}}`,
+			want: "# #general の分析結果\n===== 最も使用されたスタンプ TOP2 =====\n1位: :+1: - 1500回\n",
+		},
+		{
+			name: "HTMLのエスケープ",
+			src:  `<p>{{(index .Result.MessageStats 0).Text}}</p>`,
+			html: true,
+			want: "<p>&lt;b&gt;新機能&lt;/b&gt;のリリースについてのお知らせです</p>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseTemplate("test", tt.src, tt.html)
+			if err != nil {
+				t.Fatalf("ParseTemplate() error = %v", err)
+			}
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, testTemplateData()); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := buf.String(); !strings.HasPrefix(got, tt.want) {
+				t.Errorf("Execute() = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplate_ExecuteError(t *testing.T) {
+	tmpl, err := ParseTemplate("test", `partial{{limit 1 .Title}}`, false)
+	if err != nil {
+		t.Fatalf("ParseTemplate() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, testTemplateData()); err == nil {
+		t.Fatal("Execute() error = nil, want error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("partial output is written: %q", buf.String())
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	dir := t.TempDir()
+	for name, want := range map[string]string{
+		"report.tmpl":      "<b>新機能</b>",
+		"report.html.tmpl": "&lt;b&gt;新機能&lt;/b&gt;",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(`{{truncate 10 (index .Result.MessageStats 0).Text}}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		tmpl, err := LoadTemplate(path)
+		if err != nil {
+			t.Fatalf("LoadTemplate(%s) error = %v", name, err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, testTemplateData()); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if got := buf.String(); got != want+"..." {
+			t.Errorf("%s: Execute() = %q, want %q", name, got, want+"...")
+		}
+	}
+
+	if _, err := LoadTemplate(filepath.Join(dir, "missing.tmpl")); err == nil {
+		t.Error("LoadTemplate() error = nil, want error for missing file")
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	tests := map[int]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567", -1234: "-1,234"}
+	for n, want := range tests {
+		if got := formatNumber(n); got != want {
+			t.Errorf("formatNumber(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
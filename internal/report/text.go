@@ -0,0 +1,81 @@
+package report
+
+import (
+	"io"
+	"strings"
+
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// maxPreviewRunes はメッセージ本文を表示する際の最大文字数
+const maxPreviewRunes = 50
+
+// Limits は各ランキングの表示件数を表す
+type Limits struct {
+	Emoji   int // スタンプランキング
+	Message int // リアクションが多いメッセージランキング
+	User    int // 投稿数ランキング
+	Thread  int // スレッドのコメント数ランキング
+	Channel int // チャンネルの活動量ランキング（ワークスペース分析のみ）
+}
+
+// DefaultChannelLimits はチャンネル分析のデフォルト表示件数（Channel は複数チャンネルを分析する場合のチャンネルランキング）
+var DefaultChannelLimits = Limits{Emoji: 3, Message: 3, User: 10, Thread: 3, Channel: 10}
+
+// DefaultWorkspaceLimits はワークスペース分析のデフォルト表示件数
+var DefaultWorkspaceLimits = Limits{Emoji: 10, Message: 10, User: 10, Thread: 10, Channel: 10}
+
+// DefaultUserLimits はユーザー分析のデフォルト表示件数
+var DefaultUserLimits = Limits{Emoji: 10, Thread: 10}
+
+// WriteChannelText はチャンネル分析結果をテキスト形式で出力する
+func WriteChannelText(w io.Writer, result *service.AnalysisResult, limits Limits) error {
+	return executeText(w, "channel", NewChannelTemplateData(Metadata{}, "", result, limits))
+}
+
+// WriteMultiChannelText は複数チャンネルの分析結果（合算とチャンネルごと）をテキスト形式で出力する
+func WriteMultiChannelText(w io.Writer, result *service.MultiChannelResult, limits Limits) error {
+	return executeText(w, "multichannel", NewMultiChannelTemplateData(Metadata{}, "", result, limits))
+}
+
+// WriteWorkspaceText はワークスペース全体のランキングをテキスト形式で出力する
+func WriteWorkspaceText(w io.Writer, result *service.MultiChannelResult, limits Limits) error {
+	return executeText(w, "workspace", NewWorkspaceTemplateData(Metadata{}, "", result, limits))
+}
+
+// WriteSearchText は検索クエリに一致したメッセージの分析結果をテキスト形式で出力する
+// channelName が指定された場合は、メッセージに投稿先のチャンネル名を付記する
+func WriteSearchText(w io.Writer, query string, result *service.AnalysisResult, limits Limits, channelName func(string) string) error {
+	return executeText(w, "search", NewSearchTemplateData(Metadata{Query: query}, "", result, limits, channelName))
+}
+
+// WriteUserText はユーザー分析結果をテキスト形式で出力する
+func WriteUserText(w io.Writer, result *service.UserAnalysisResult, limits Limits) error {
+	return executeText(w, "user", NewUserTemplateData(Metadata{}, "", result, limits))
+}
+
+// Preview はメッセージ本文を1行に整形し、長い場合は省略する
+func Preview(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= maxPreviewRunes {
+		return text
+	}
+	return string(runes[:maxPreviewRunes]) + "..."
+}
+
+// head はスライスの先頭n件を返す（nが0以下の場合は空）
+func head[T any](items []T, n int) []T {
+	if n <= 0 {
+		return nil
+	}
+	if len(items) > n {
+		return items[:n]
+	}
+	return items
+}
+
+// topChannels はチャンネルの活動量ランキングを表示件数までにする（他のランキングと同じく、表示件数が0の場合は空）
+func topChannels(result *service.MultiChannelResult, limits Limits) []service.ChannelActivity {
+	return head(result.ChannelRanking(), limits.Channel)
+}
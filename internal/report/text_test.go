@@ -0,0 +1,198 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+func TestWriteChannelText(t *testing.T) {
+	result := &service.AnalysisResult{
+		EmojiStats: []domain.EmojiCount{
+			{Emoji: "+1", Count: 45},
+			{Emoji: "eyes", Count: 32},
+			{Emoji: "smile", Count: 28},
+			{Emoji: "tada", Count: 1},
+		},
+		MessageStats: []domain.MessageReaction{
+			{Text: "新機能のリリースについて", Reactions: 15},
+		},
+		UserStats: []domain.UserStats{
+			{UserID: "U1", UserName: "田中太郎", Count: 156},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteChannelText(&buf, result, DefaultChannelLimits); err != nil {
+		t.Fatalf("WriteChannelText() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"===== 最も使用されたスタンプ TOP3 =====\n1位: :+1: - 45回\n2位: :eyes: - 32回\n3位: :smile: - 28回\n\n",
+		"1位: 新機能のリリースについて\nリアクション数: 15\n\n",
+		"1位: 田中太郎 - 156投稿\n",
+		"===== 最もスレッドのコメント数が多い投稿 TOP3 =====\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, ":tada:") {
+		t.Errorf("output contains entries beyond the limit\n%s", got)
+	}
+}
+
+func TestWriteUserText(t *testing.T) {
+	result := &service.UserAnalysisResult{
+		UserName:       "田中太郎",
+		TotalMessages:  156,
+		TotalReactions: 342,
+		ReactionRanking: []domain.EmojiCount{
+			{Emoji: "+1", Count: 89},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteUserText(&buf, result, DefaultUserLimits); err != nil {
+		t.Fatalf("WriteUserText() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"===== ユーザー分析結果: 田中太郎 =====\n投稿総数: 156件\nスタンプ総数: 342回\n",
+		"===== その人の投稿についたスタンプのランキング TOP10 =====\n1位: :+1: - 89回\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestPreview(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "短い本文",
+			text:     "今日のランチ",
+			expected: "今日のランチ",
+		},
+		{
+			name:     "改行を含む本文",
+			text:     "1行目\n2行目",
+			expected: "1行目 2行目",
+		},
+		{
+			name:     "長い本文は省略",
+			text:     strings.Repeat("あ", 60),
+			expected: strings.Repeat("あ", 50) + "...",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Preview(tt.text); got != tt.expected {
+				t.Errorf("Preview(%q) = %q, want %q", tt.text, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestWriteMultiChannelText(t *testing.T) {
+	result := &service.MultiChannelResult{
+		Merged: &service.AnalysisResult{
+			EmojiStats:       []domain.EmojiCount{{Emoji: "+1", Count: 5}},
+			MessageStats:     []domain.MessageReaction{{Text: "リリースしました", Reactions: 5, ChannelID: "C2"}},
+			UserMessageCount: map[string]int{"U1": 2},
+		},
+		Channels: []service.ChannelAnalysis{
+			{
+				Channel: &domain.Channel{ID: "C1", Name: "team-a"},
+				Result:  &service.AnalysisResult{UserMessageCount: map[string]int{"U1": 1}},
+			},
+			{
+				Channel: &domain.Channel{ID: "C2", Name: "team-b"},
+				Result: &service.AnalysisResult{
+					EmojiStats:       []domain.EmojiCount{{Emoji: "+1", Count: 5}},
+					UserMessageCount: map[string]int{"U1": 1},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMultiChannelText(&buf, result, DefaultChannelLimits); err != nil {
+		t.Fatalf("WriteMultiChannelText() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"##### 全2チャンネルの合計 #####\n投稿数: 2件 / スタンプ数: 5回\n",
+		"1位: リリースしました (#team-b)\nリアクション数: 5\n",
+		"#team-a - 1投稿 / 0スタンプ\n#team-b - 1投稿 / 5スタンプ\n",
+		"##### #team-b #####\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteSearchText(t *testing.T) {
+	result := &service.AnalysisResult{
+		EmojiStats:       []domain.EmojiCount{{Emoji: "tada", Count: 4}},
+		MessageStats:     []domain.MessageReaction{{Text: "v1.2.0 をリリースしました", Reactions: 4, ChannelID: "C1"}},
+		UserMessageCount: map[string]int{"U1": 2},
+	}
+	names := map[string]string{"C1": "release"}
+
+	var buf bytes.Buffer
+	if err := WriteSearchText(&buf, `has:link "release"`, result, DefaultChannelLimits, func(id string) string { return names[id] }); err != nil {
+		t.Fatalf("WriteSearchText() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"##### 検索「has:link \"release\"」の分析結果 #####\n投稿数: 2件 / スタンプ数: 4回\n",
+		"1位: v1.2.0 をリリースしました (#release)\nリアクション数: 4\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestWriteWorkspaceText(t *testing.T) {
+	result := &service.MultiChannelResult{
+		Merged: &service.AnalysisResult{
+			UserStats:        []domain.UserStats{{UserID: "U1", UserName: "田中太郎", Count: 3}},
+			UserMessageCount: map[string]int{"U1": 3},
+		},
+		Channels: []service.ChannelAnalysis{
+			{Channel: &domain.Channel{ID: "C1", Name: "quiet"}, Result: &service.AnalysisResult{UserMessageCount: map[string]int{"U1": 1}}},
+			{Channel: &domain.Channel{ID: "C2", Name: "busy"}, Result: &service.AnalysisResult{UserMessageCount: map[string]int{"U1": 2}}},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteWorkspaceText(&buf, result, DefaultWorkspaceLimits); err != nil {
+		t.Fatalf("WriteWorkspaceText() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"##### ワークスペース全体のランキング（2チャンネル） #####\n",
+		"===== 最も投稿数が多いチャンネル TOP10 =====\n1位: #busy - 2投稿 / 0スタンプ\n2位: #quiet - 1投稿 / 0スタンプ\n",
+		"===== 最も投稿数が多いユーザー TOP10 =====\n1位: 田中太郎 - 3投稿\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
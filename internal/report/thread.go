@@ -0,0 +1,131 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/slack-go/slack"
+)
+
+// DefaultThreadLimits はスレッド分析のデフォルト表示件数
+var DefaultThreadLimits = Limits{Emoji: 10, Message: 5, User: 10}
+
+// WriteThreadText はスレッドの分析結果をテキスト形式で出力する
+// opts.Charts の場合は推移をスパークライン、ランキングを横棒グラフで表す
+func WriteThreadText(w io.Writer, result *service.ThreadAnalysisResult, limits Limits, opts TextOptions) error {
+	var b strings.Builder
+
+	b.WriteString("##### スレッドの分析結果 #####\n")
+	fmt.Fprintf(&b, "親メッセージ: %s\n", Preview(result.Parent.Text))
+	fmt.Fprintf(&b, "投稿者: %s / 投稿日時: %s\n", result.ParentUser, result.Parent.Timestamp.Format("2006-01-02 15:04"))
+	if result.Parent.HasReactions() {
+		fmt.Fprintf(&b, "親メッセージのスタンプ: %s\n", formatReactions(result.Parent.Reactions))
+	}
+	fmt.Fprintf(&b, "返信: %d件 / 参加者: %d人 / 返信のスタンプ数: %d回", len(result.Replies), len(result.Participants), result.TotalReactions())
+	if len(result.Replies) > 0 {
+		fmt.Fprintf(&b, " / 最後の返信まで: %s", formatDuration(result.Duration()))
+	}
+	b.WriteString("\n\n")
+
+	fmt.Fprintf(&b, "===== 返信数が多い参加者 TOP%d =====\n", limits.User)
+	if opts.Charts {
+		b.WriteString(barChart(userRows(result.Participants, limits.User), "返信", opts.lineWidth()))
+	} else {
+		for i, stat := range head(result.Participants, limits.User) {
+			fmt.Fprintf(&b, "%d位: %s - %d返信\n", i+1, stat.UserName, stat.Count)
+		}
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "===== 返信についたスタンプ TOP%d =====\n", limits.Emoji)
+	if opts.Charts {
+		b.WriteString(barChart(emojiRows(result.EmojiStats, limits.Emoji), "回", opts.lineWidth()))
+	} else {
+		for i, stat := range head(result.EmojiStats, limits.Emoji) {
+			fmt.Fprintf(&b, "%d位: :%s: - %d回\n", i+1, stat.Emoji, stat.Count)
+		}
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "===== 返信の推移（%s） =====\n", result.Granularity)
+	if opts.Charts {
+		var points []timelinePoint
+		for _, period := range result.Timeline {
+			points = append(points, timelinePoint{start: period.Start, value: period.Replies})
+		}
+		writeSparkline(&b, result.Granularity, points, "件", opts.lineWidth())
+	} else {
+		for _, period := range result.Timeline {
+			fmt.Fprintf(&b, "%s: %d件 (スタンプ %d回)\n", formatPeriod(period.Start, result.Granularity), period.Replies, period.Reactions)
+		}
+	}
+	b.WriteString("\n")
+
+	fmt.Fprintf(&b, "===== 最もリアクションがついた返信 TOP%d =====\n", limits.Message)
+	if opts.Charts {
+		var rows []chartRow
+		for _, stat := range head(result.TopReplies, limits.Message) {
+			rows = append(rows, chartRow{label: stat.Text, value: stat.Reactions})
+		}
+		b.WriteString(messageLines(rows, "回", opts.lineWidth()))
+		b.WriteString("\n")
+	} else {
+		for i, stat := range head(result.TopReplies, limits.Message) {
+			fmt.Fprintf(&b, "%d位: %s\nリアクション数: %d\n\n", i+1, Preview(stat.Text), stat.Reactions)
+		}
+		if len(result.TopReplies) == 0 {
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ThreadBlocks はスレッドの分析結果をBlock Kitのブロックに変換する
+func ThreadBlocks(title string, result *service.ThreadAnalysisResult, limits Limits, link MessageLinker) []slack.Block {
+	parent := result.Parent
+	summary := fmt.Sprintf("%s%s / 返信: %d件 / 参加者: %d人 / 返信のスタンプ数: %d回",
+		messageLink(parent.Text, parent.ChannelID, parent.ID, link), mention(parent.UserID), len(result.Replies), len(result.Participants), result.TotalReactions())
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, title, true, false)),
+		slack.NewContextBlock("", slack.NewTextBlockObject(slack.MarkdownType, summary, false, false)),
+		slack.NewDividerBlock(),
+	}
+
+	var participants []string
+	for i, stat := range head(result.Participants, limits.User) {
+		participants = append(participants, fmt.Sprintf("%d. <@%s> - %d返信", i+1, stat.UserID, stat.Count))
+	}
+	blocks = append(blocks, rankingSection("返信数が多い参加者", participants))
+
+	var emoji []string
+	for i, stat := range head(result.EmojiStats, limits.Emoji) {
+		emoji = append(emoji, fmt.Sprintf("%d. :%s: - %d回", i+1, stat.Emoji, stat.Count))
+	}
+	blocks = append(blocks, rankingSection("返信についたスタンプ", emoji))
+
+	var timeline []string
+	for _, period := range result.Timeline {
+		timeline = append(timeline, fmt.Sprintf("%s - %d件", formatPeriod(period.Start, result.Granularity), period.Replies))
+	}
+	blocks = append(blocks, rankingSection("返信の推移（"+result.Granularity.String()+"）", timeline))
+
+	var replies []string
+	for i, stat := range head(result.TopReplies, limits.Message) {
+		replies = append(replies, fmt.Sprintf("%d. %s%s - %dリアクション", i+1, messageLink(stat.Text, stat.ChannelID, stat.MessageID, link), mention(stat.UserID), stat.Reactions))
+	}
+	return append(blocks, rankingSection("最もリアクションがついた返信", replies))
+}
+
+// formatReactions はリアクションを ":tada: 3 :eyes: 1" の形式で表す
+func formatReactions(reactions []domain.Reaction) string {
+	parts := make([]string, 0, len(reactions))
+	for _, r := range reactions {
+		parts = append(parts, fmt.Sprintf(":%s: %d", r.Name, r.Count))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,90 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/slack-go/slack"
+)
+
+func newThreadResult() *service.ThreadAnalysisResult {
+	base := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	return &service.ThreadAnalysisResult{
+		Parent: &domain.Message{ID: "1704877200.000100", Text: "障害が発生しています", UserID: "U1", ChannelID: "C1", Timestamp: base,
+			Reactions: []domain.Reaction{{Name: "eyes", Count: 4}}},
+		ParentUser: "田中太郎",
+		Replies: []*domain.Message{
+			{ID: "1704877500.000200", Text: "調査します", UserID: "U2", Timestamp: base.Add(5 * time.Minute)},
+			{ID: "1704886200.000300", Text: "復旧しました", UserID: "U2", Timestamp: base.Add(150 * time.Minute)},
+		},
+		Participants: []domain.UserStats{
+			{UserID: "U2", UserName: "佐藤花子", Count: 2},
+			{UserID: "U1", UserName: "田中太郎", Count: 0},
+		},
+		EmojiStats:  []domain.EmojiCount{{Emoji: "tada", Count: 5}},
+		Granularity: service.GranularityHour,
+		Timeline: []service.ReplyPeriod{
+			{Start: base, Replies: 1},
+			{Start: base.Add(time.Hour)},
+			{Start: base.Add(2 * time.Hour), Replies: 1, Reactions: 5},
+		},
+		TopReplies: []domain.MessageReaction{
+			{Text: "復旧しました", Reactions: 5, UserID: "U2", ChannelID: "C1", MessageID: "1704886200.000300"},
+		},
+	}
+}
+
+func TestWriteThreadText(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteThreadText(&buf, newThreadResult(), DefaultThreadLimits, TextOptions{}); err != nil {
+		t.Fatalf("WriteThreadText() error = %v", err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"親メッセージ: 障害が発生しています\n投稿者: 田中太郎 / 投稿日時: 2024-01-10 09:00\n親メッセージのスタンプ: :eyes: 4\n",
+		"返信: 2件 / 参加者: 2人 / 返信のスタンプ数: 5回 / 最後の返信まで: 約2時間30分\n",
+		"1位: 佐藤花子 - 2返信\n2位: 田中太郎 - 0返信\n",
+		"===== 返信の推移（時間別） =====\n2024-01-10 09:00: 1件 (スタンプ 0回)\n2024-01-10 10:00: 0件 (スタンプ 0回)\n",
+		"1位: 復旧しました\nリアクション数: 5\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output does not contain %q\n%s", want, got)
+		}
+	}
+}
+
+func TestThreadBlocks(t *testing.T) {
+	link := func(channelID, ts string) string {
+		return "https://example.slack.com/archives/" + channelID + "/p" + strings.Replace(ts, ".", "", 1)
+	}
+	blocks := ThreadBlocks("スレッドの分析結果", newThreadResult(), DefaultThreadLimits, link)
+	if len(blocks) != 7 {
+		t.Fatalf("len(blocks) = %d, want 7", len(blocks))
+	}
+
+	summary := blocks[1].(*slack.ContextBlock).ContextElements.Elements[0].(*slack.TextBlockObject).Text
+	if want := "<https://example.slack.com/archives/C1/p1704877200000100|障害が発生しています> (<@U1>) / 返信: 2件"; !strings.HasPrefix(summary, want) {
+		t.Errorf("summary = %q, want prefix %q", summary, want)
+	}
+
+	var texts []string
+	for _, block := range blocks[3:] {
+		texts = append(texts, block.(*slack.SectionBlock).Text.Text)
+	}
+	got := strings.Join(texts, "\n")
+	for _, want := range []string{
+		"1. <@U2> - 2返信",
+		"1. :tada: - 5回",
+		"*返信の推移（時間別）*\n2024-01-10 09:00 - 1件",
+		"1. <https://example.slack.com/archives/C1/p1704886200000300|復旧しました> (<@U2>) - 5リアクション",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("blocks do not contain %q\n%s", want, got)
+		}
+	}
+}
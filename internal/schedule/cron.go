@@ -0,0 +1,187 @@
+// Package schedule はcron式に従って定期的な分析ジョブを実行する
+package schedule
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cron は5フィールド（分 時 日 月 曜日）のcron式を表す
+type Cron struct {
+	expr    string
+	minute  uint64
+	hour    uint64
+	day     uint64
+	month   uint64
+	weekday uint64
+	// 日と曜日の両方が指定された場合はどちらか一方に一致すればよい（標準のcronと同じ）
+	dayRestricted     bool
+	weekdayRestricted bool
+}
+
+// cronAliases は @daily などの短縮表記
+var cronAliases = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// monthNames は月のフィールドで使用できる名前
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+// weekdayNames は曜日のフィールドで使用できる名前
+var weekdayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// ParseCron はcron式を解析する
+// 各フィールドは *、数値、範囲（1-5）、リスト（1,3,5）、間隔（*/15、1-5/2）に対応し、
+// 月と曜日は名前（jan、mon など）でも指定できる。曜日の7は日曜日として扱う
+func ParseCron(expr string) (*Cron, error) {
+	spec := strings.TrimSpace(expr)
+	if alias, ok := cronAliases[strings.ToLower(spec)]; ok {
+		spec = alias
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron式は「分 時 日 月 曜日」の5つのフィールドで指定してください: %q", expr)
+	}
+
+	c := &Cron{expr: expr}
+	var err error
+	if c.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron式の分が不正です: %w", err)
+	}
+	if c.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("cron式の時が不正です: %w", err)
+	}
+	if c.day, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("cron式の日が不正です: %w", err)
+	}
+	if c.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return nil, fmt.Errorf("cron式の月が不正です: %w", err)
+	}
+	if c.weekday, err = parseField(fields[4], 0, 7, weekdayNames); err != nil {
+		return nil, fmt.Errorf("cron式の曜日が不正です: %w", err)
+	}
+	if c.weekday&(1<<7) != 0 {
+		c.weekday |= 1 << 0
+	}
+	c.dayRestricted = !strings.HasPrefix(fields[2], "*")
+	c.weekdayRestricted = !strings.HasPrefix(fields[4], "*")
+	return c, nil
+}
+
+// String は解析前のcron式を返す
+func (c *Cron) String() string {
+	return c.expr
+}
+
+// parseField はcron式の1つのフィールドを解析し、一致する値のビット集合を返す
+func parseField(field string, min, max int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("間隔が不正です: %q", part)
+			}
+			rangePart, step = part[:i], n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = min, max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = parseValue(bounds[0], min, max, names); err != nil {
+				return 0, err
+			}
+			if hi, err = parseValue(bounds[1], min, max, names); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, fmt.Errorf("範囲の開始が終了より大きくなっています: %q", part)
+			}
+		default:
+			v, err := parseValue(rangePart, min, max, names)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			if step > 1 {
+				// 5/15 のような指定は 5-max/15 と同じ
+				hi = max
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// parseValue はフィールドの値（数値または名前）を解析する
+func parseValue(s string, min, max int, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("値が不正です: %q", s)
+	}
+	if v < min || v > max {
+		return 0, fmt.Errorf("値 %d は %d から %d の範囲で指定してください", v, min, max)
+	}
+	return v, nil
+}
+
+// Next は t より後で、cron式に一致する最初の時刻を返す（分単位、t のタイムゾーンで評価する）
+// 一致する時刻が見つからない場合（2月30日など）はゼロ値を返す
+func (c *Cron) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !c.matchDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// matchDay は日と曜日のフィールドが t の日付に一致するかどうかを返す
+func (c *Cron) matchDay(t time.Time) bool {
+	day := c.day&(1<<uint(t.Day())) != 0
+	weekday := c.weekday&(1<<uint(t.Weekday())) != 0
+	if c.dayRestricted && c.weekdayRestricted {
+		return day || weekday
+	}
+	return day && weekday
+}
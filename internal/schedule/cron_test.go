@@ -0,0 +1,110 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCron_Next(t *testing.T) {
+	// 2024-01-08 は月曜日
+	base := time.Date(2024, 1, 8, 9, 30, 15, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		expr     string
+		from     time.Time
+		expected time.Time
+	}{
+		{
+			name:     "毎分",
+			expr:     "* * * * *",
+			from:     base,
+			expected: time.Date(2024, 1, 8, 9, 31, 0, 0, time.UTC),
+		},
+		{
+			name:     "毎週月曜日9時（当日は過ぎている）",
+			expr:     "0 9 * * mon",
+			from:     base,
+			expected: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "ちょうど予定時刻の場合は次の予定",
+			expr:     "0 9 * * 1",
+			from:     time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "毎月1日",
+			expr:     "@monthly",
+			from:     base,
+			expected: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "15分おき",
+			expr:     "*/15 * * * *",
+			from:     base,
+			expected: time.Date(2024, 1, 8, 9, 45, 0, 0, time.UTC),
+		},
+		{
+			name:     "平日の範囲とリスト",
+			expr:     "0 8,18 * * 1-5",
+			from:     time.Date(2024, 1, 12, 19, 0, 0, 0, time.UTC),
+			expected: time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "曜日の7は日曜日",
+			expr:     "0 0 * * 7",
+			from:     base,
+			expected: time.Date(2024, 1, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "日と曜日の両方を指定した場合はどちらかに一致",
+			expr:     "0 0 13 * fri",
+			from:     base,
+			expected: time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "うるう日",
+			expr:     "0 0 29 feb *",
+			from:     base,
+			expected: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "存在しない日付",
+			expr:     "0 0 30 2 *",
+			from:     base,
+			expected: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cron, err := ParseCron(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseCron(%q) error = %v", tt.expr, err)
+			}
+			if got := cron.Next(tt.from); !got.Equal(tt.expected) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseCron_Invalid(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"0 9 * * funday",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q) error = nil, want error", expr)
+		}
+	}
+}
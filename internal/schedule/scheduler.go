@@ -0,0 +1,184 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// maxCatchUp は停止中に実行されなかった予定時刻のうち、再開時に実行する最大数（ジョブごと）
+// これより古い予定時刻は実行せずに読み飛ばす
+const maxCatchUp = 10
+
+// pollInterval は次の実行予定時刻を確認する最大の間隔
+// スリープからの復帰や時刻の変更があっても、この間隔で予定時刻に追いつく
+const pollInterval = time.Minute
+
+// Job はスケジュールに従って実行するジョブ
+type Job struct {
+	Name   string
+	Cron   *Cron
+	Period string // 実行予定日の前日までの直近の期間（例: 7d, 1m）
+}
+
+// Runner はジョブを1回実行する
+// at は実行予定時刻、dateRange はその実行で分析する期間
+type Runner func(ctx context.Context, job Job, at time.Time, dateRange *domain.DateRange) error
+
+// Scheduler はcron式に従ってジョブを実行し、実行の記録を保存する
+type Scheduler struct {
+	jobs  []Job
+	state *State
+	run   Runner
+	log   io.Writer
+	now   func() time.Time
+}
+
+// New は新しいSchedulerを作成する
+func New(jobs []Job, state *State, run Runner, log io.Writer) *Scheduler {
+	return &Scheduler{
+		jobs:  jobs,
+		state: state,
+		run:   run,
+		log:   log,
+		now:   time.Now,
+	}
+}
+
+// Run はコンテキストがキャンセルされるまでジョブを実行する
+// 起動時には、前回の記録以降に実行されなかった予定時刻のジョブを順に実行する
+func (s *Scheduler) Run(ctx context.Context) error {
+	if err := s.init(); err != nil {
+		return err
+	}
+	for {
+		if err := s.runDue(ctx); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		wait := pollInterval
+		if next := s.nextRun(); !next.IsZero() {
+			wait = min(wait, next.Sub(s.now()))
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+	}
+}
+
+// init は記録のないジョブ（初めて実行するジョブ）の記録を現在時刻から開始し、次回の実行予定を表示する
+func (s *Scheduler) init() error {
+	now := s.now()
+	for _, job := range s.jobs {
+		if _, ok := s.state.Jobs[job.Name]; !ok {
+			s.state.Jobs[job.Name] = &JobState{LastScheduled: now}
+		}
+		next := job.Cron.Next(s.state.Jobs[job.Name].LastScheduled)
+		s.logf("ジョブ %s を登録しました（%s、次回: %s）", job.Name, job.Cron, formatTime(next))
+	}
+	return s.state.Save()
+}
+
+// runDue は実行予定時刻を過ぎたジョブを実行する
+func (s *Scheduler) runDue(ctx context.Context) error {
+	now := s.now()
+	for _, job := range s.jobs {
+		state := s.state.Jobs[job.Name]
+		due := dueTimes(job.Cron, state.LastScheduled, now)
+		if len(due) > maxCatchUp {
+			s.logf("ジョブ %s: 実行されなかった%d回のうち古い%d回を読み飛ばします", job.Name, len(due), len(due)-maxCatchUp)
+			due = due[len(due)-maxCatchUp:]
+		}
+		for _, at := range due {
+			if err := s.runJob(ctx, job, at, now); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// runJob はジョブを1回実行して記録を保存する
+// 実行中にコンテキストがキャンセルされた場合は記録せず、次回の起動時に改めて実行する
+func (s *Scheduler) runJob(ctx context.Context, job Job, at, now time.Time) error {
+	if job.Cron.Next(at).After(now) {
+		s.logf("ジョブ %s を実行します（予定時刻: %s）", job.Name, formatTime(at))
+	} else {
+		s.logf("ジョブ %s の実行されなかった予定を実行します（予定時刻: %s）", job.Name, formatTime(at))
+	}
+
+	dateRange, err := RollingRange(job.Period, at)
+	if err == nil {
+		err = s.run(ctx, job, at, dateRange)
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	state := &JobState{LastScheduled: at, FinishedAt: s.now(), Status: StatusSucceeded}
+	if err != nil {
+		state.Status = StatusFailed
+		state.Error = err.Error()
+		s.logf("ジョブ %s が失敗しました: %v", job.Name, err)
+	} else {
+		s.logf("ジョブ %s が完了しました", job.Name)
+	}
+	s.state.Jobs[job.Name] = state
+	return s.state.Save()
+}
+
+// nextRun は全ジョブのうち最も早い次回の実行予定時刻を返す
+func (s *Scheduler) nextRun() time.Time {
+	var next time.Time
+	for _, job := range s.jobs {
+		t := job.Cron.Next(s.state.Jobs[job.Name].LastScheduled)
+		if !t.IsZero() && (next.IsZero() || t.Before(next)) {
+			next = t
+		}
+	}
+	return next
+}
+
+// logf は時刻を付けてログを出力する
+func (s *Scheduler) logf(format string, args ...any) {
+	fmt.Fprintf(s.log, "%s %s\n", s.now().Format(time.DateTime), fmt.Sprintf(format, args...))
+}
+
+// dueTimes は last より後で now 以前の実行予定時刻を古い順に返す
+func dueTimes(cron *Cron, last, now time.Time) []time.Time {
+	var due []time.Time
+	for t := cron.Next(last); !t.IsZero() && !t.After(now); t = cron.Next(t) {
+		due = append(due, t)
+	}
+	return due
+}
+
+// RollingRange は実行予定時刻 at の前日の終わりまでの直近 period の期間を返す
+// 例えば毎週月曜日に "7d" で実行すると前週の月曜日から日曜日まで、毎月1日に "1m" で実行すると前月の1か月間になる
+func RollingRange(period string, at time.Time) (*domain.DateRange, error) {
+	midnight := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	dateRange, err := domain.ParseRelativeDateRange(period, midnight)
+	if err != nil {
+		return nil, err
+	}
+	dateRange.End = domain.EndBefore(midnight)
+	return dateRange, nil
+}
+
+// formatTime は実行予定時刻を表示用に整形する
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "なし"
+	}
+	return t.Format("2006-01-02 15:04")
+}
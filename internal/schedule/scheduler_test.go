@@ -0,0 +1,187 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestRollingRange(t *testing.T) {
+	tests := []struct {
+		name          string
+		period        string
+		at            time.Time
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			name:          "毎週月曜日に前週分",
+			period:        "7d",
+			at:            time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 1, 14, 23, 59, 59, 999999000, time.UTC),
+		},
+		{
+			name:          "毎月1日に前月分",
+			period:        "1m",
+			at:            time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+			expectedStart: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2024, 2, 29, 23, 59, 59, 999999000, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RollingRange(tt.period, tt.at)
+			if err != nil {
+				t.Fatalf("RollingRange() error = %v", err)
+			}
+			if !got.Start.Equal(tt.expectedStart) || !got.End.Equal(tt.expectedEnd) {
+				t.Errorf("RollingRange() = %v - %v, want %v - %v", got.Start, got.End, tt.expectedStart, tt.expectedEnd)
+			}
+		})
+	}
+
+	if _, err := RollingRange("7y", time.Now()); err == nil {
+		t.Error("RollingRange(7y) error = nil, want error")
+	}
+}
+
+func TestScheduler_CatchUp(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	cron, err := ParseCron("0 9 * * mon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := Job{Name: "weekly", Cron: cron, Period: "7d"}
+
+	// 前回は 2024-01-01 の実行まで完了しており、2回分の実行を取りこぼしている
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	state.Jobs["weekly"] = &JobState{LastScheduled: time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)}
+
+	var runs []time.Time
+	var ranges []*domain.DateRange
+	runner := func(ctx context.Context, job Job, at time.Time, dateRange *domain.DateRange) error {
+		runs = append(runs, at)
+		ranges = append(ranges, dateRange)
+		if len(runs) == 2 {
+			return errors.New("投稿に失敗しました")
+		}
+		return nil
+	}
+	var log bytes.Buffer
+	s := New([]Job{job}, state, runner, &log)
+	s.now = func() time.Time { return time.Date(2024, 1, 16, 12, 0, 0, 0, time.UTC) }
+
+	if err := s.init(); err != nil {
+		t.Fatalf("init() error = %v", err)
+	}
+	if err := s.runDue(context.Background()); err != nil {
+		t.Fatalf("runDue() error = %v", err)
+	}
+
+	want := []time.Time{
+		time.Date(2024, 1, 8, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC),
+	}
+	if len(runs) != len(want) || !runs[0].Equal(want[0]) || !runs[1].Equal(want[1]) {
+		t.Fatalf("runs = %v, want %v", runs, want)
+	}
+	if wantStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !ranges[0].Start.Equal(wantStart) {
+		t.Errorf("ranges[0].Start = %v, want %v", ranges[0].Start, wantStart)
+	}
+
+	// 記録はファイルに保存され、再読み込みしても最後の実行が残っている
+	loaded, err := LoadState(path)
+	if err != nil {
+		t.Fatalf("LoadState() error = %v", err)
+	}
+	got := loaded.Jobs["weekly"]
+	if got == nil || !got.LastScheduled.Equal(want[1]) || got.Status != StatusFailed || got.Error == "" {
+		t.Errorf("saved state = %+v, want last_scheduled %v with failure", got, want[1])
+	}
+
+	// 実行済みの予定は再実行しない
+	runs = nil
+	if err := s.runDue(context.Background()); err != nil {
+		t.Fatalf("runDue() error = %v", err)
+	}
+	if len(runs) != 0 {
+		t.Errorf("runs after catch-up = %v, want none", runs)
+	}
+}
+
+func TestScheduler_FirstRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	cron, err := ParseCron("0 9 * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := LoadState(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	runner := func(ctx context.Context, job Job, at time.Time, dateRange *domain.DateRange) error {
+		called = true
+		return nil
+	}
+	now := time.Date(2024, 1, 16, 12, 0, 0, 0, time.UTC)
+	var log bytes.Buffer
+	s := New([]Job{{Name: "daily", Cron: cron, Period: "1d"}}, state, runner, &log)
+	s.now = func() time.Time { return now }
+
+	if err := s.init(); err != nil {
+		t.Fatalf("init() error = %v", err)
+	}
+	if err := s.runDue(context.Background()); err != nil {
+		t.Fatalf("runDue() error = %v", err)
+	}
+	// 初めて登録したジョブは過去の予定時刻を実行しない
+	if called {
+		t.Error("runner was called for a job without previous state")
+	}
+	if want := time.Date(2024, 1, 17, 9, 0, 0, 0, time.UTC); !s.nextRun().Equal(want) {
+		t.Errorf("nextRun() = %v, want %v", s.nextRun(), want)
+	}
+}
+
+func TestScheduler_CatchUpLimit(t *testing.T) {
+	cron, err := ParseCron("0 * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := LoadState(filepath.Join(t.TempDir(), "state.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state.Jobs["hourly"] = &JobState{LastScheduled: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+
+	var runs []time.Time
+	runner := func(ctx context.Context, job Job, at time.Time, dateRange *domain.DateRange) error {
+		runs = append(runs, at)
+		return nil
+	}
+	var log bytes.Buffer
+	s := New([]Job{{Name: "hourly", Cron: cron, Period: "1d"}}, state, runner, &log)
+	s.now = func() time.Time { return time.Date(2024, 1, 2, 0, 30, 0, 0, time.UTC) }
+
+	if err := s.runDue(context.Background()); err != nil {
+		t.Fatalf("runDue() error = %v", err)
+	}
+	if len(runs) != maxCatchUp {
+		t.Fatalf("len(runs) = %d, want %d", len(runs), maxCatchUp)
+	}
+	if want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC); !runs[len(runs)-1].Equal(want) {
+		t.Errorf("last run = %v, want %v", runs[len(runs)-1], want)
+	}
+}
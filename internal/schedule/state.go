@@ -0,0 +1,72 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ジョブの実行結果
+const (
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+)
+
+// JobState はジョブの最後の実行の記録
+type JobState struct {
+	LastScheduled time.Time `json:"last_scheduled"`       // 最後に処理した実行予定時刻
+	FinishedAt    time.Time `json:"finished_at,omitzero"` // 実行が終了した時刻（未実行の場合はゼロ値）
+	Status        string    `json:"status,omitempty"`     // 実行結果（succeeded / failed）
+	Error         string    `json:"error,omitempty"`      // 失敗した場合のエラー
+}
+
+// State はジョブごとの最後の実行の記録をファイルに保存する
+// 再起動後も記録から実行されなかった予定時刻を求め、取りこぼしたジョブを実行する
+type State struct {
+	path string
+	Jobs map[string]*JobState `json:"jobs"`
+}
+
+// LoadState は記録ファイルを読み込む（ファイルがない場合は空の記録を返す）
+func LoadState(path string) (*State, error) {
+	state := &State{path: path, Jobs: map[string]*JobState{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("実行記録の読み込みエラー: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("実行記録の解析エラー（%s）: %w", path, err)
+	}
+	if state.Jobs == nil {
+		state.Jobs = map[string]*JobState{}
+	}
+	return state, nil
+}
+
+// Save は記録をファイルに書き込む
+// 書き込み中に停止しても記録が壊れないよう、一時ファイルに書き込んでから置き換える
+func (s *State) Save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(s.path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("実行記録の保存エラー: %w", err)
+		}
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("実行記録の保存エラー: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("実行記録の保存エラー: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// ActivityPeriod は期間ごとの投稿数とリアクション数
+type ActivityPeriod struct {
+	Start     time.Time // 期間の開始日時
+	Messages  int       // 投稿されたメッセージ数
+	Reactions int       // 投稿されたメッセージについたリアクションの総数
+}
+
+// ActivityTimeline はメッセージを投稿日時で期間ごとに集計する
+// 期間の単位は分析期間（指定がない場合は最初と最後の投稿日時）の長さから決め、投稿のない期間も0件として含める
+func ActivityTimeline(messages []*domain.Message, dateRange *domain.DateRange) (Granularity, []ActivityPeriod) {
+	start, end, ok := timelineSpan(messages, dateRange)
+	if !ok {
+		return GranularityDay, nil
+	}
+
+	granularity := granularityFor(end.Sub(start))
+	return granularity, timeline(messages, start, end, granularity,
+		func(start time.Time) ActivityPeriod { return ActivityPeriod{Start: start} },
+		func(period *ActivityPeriod, msg *domain.Message) {
+			period.Messages++
+			period.Reactions += msg.TotalReactionCount()
+		})
+}
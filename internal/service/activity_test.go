@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestActivityTimeline(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	messages := []*domain.Message{
+		{ID: "1", Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, jst), Reactions: []domain.Reaction{{Name: "+1", Count: 2}}},
+		{ID: "2", Timestamp: time.Date(2024, 1, 1, 18, 0, 0, 0, jst)},
+		// JST 2024-01-03 の8時は UTC では前日
+		{ID: "3", Timestamp: time.Date(2024, 1, 2, 23, 0, 0, 0, time.UTC), Reactions: []domain.Reaction{{Name: "eyes", Count: 1}}},
+	}
+	dateRange := &domain.DateRange{
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, jst),
+		End:   time.Date(2024, 1, 4, 23, 59, 59, 0, jst),
+	}
+
+	granularity, timeline := ActivityTimeline(messages, dateRange)
+	if granularity != GranularityDay {
+		t.Errorf("granularity = %v, want %v", granularity, GranularityDay)
+	}
+	want := []ActivityPeriod{
+		{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, jst), Messages: 2, Reactions: 2},
+		{Start: time.Date(2024, 1, 2, 0, 0, 0, 0, jst)},
+		{Start: time.Date(2024, 1, 3, 0, 0, 0, 0, jst), Messages: 1, Reactions: 1},
+		{Start: time.Date(2024, 1, 4, 0, 0, 0, 0, jst)},
+	}
+	if len(timeline) != len(want) {
+		t.Fatalf("len(timeline) = %d, want %d", len(timeline), len(want))
+	}
+	for i := range want {
+		if !timeline[i].Start.Equal(want[i].Start) || timeline[i].Messages != want[i].Messages || timeline[i].Reactions != want[i].Reactions {
+			t.Errorf("timeline[%d] = %+v, want %+v", i, timeline[i], want[i])
+		}
+	}
+
+	if _, timeline := ActivityTimeline(nil, nil); timeline != nil {
+		t.Errorf("ActivityTimeline(nil, nil) = %+v, want nil", timeline)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 
@@ -11,8 +12,11 @@ import (
 
 // Analyzer はチャンネルのメッセージとリアクションを分析するサービス
 type Analyzer struct {
-	messageRepo domain.MessageRepository
-	userRepo    domain.UserRepository
+	messageRepo      domain.MessageRepository
+	userRepo         domain.UserRepository
+	excludedUsers    map[string]bool // 集計から除外するユーザーID
+	excludedChannels map[string]bool // チャンネルを横断する分析から除外するチャンネルID
+	progress         io.Writer       // 進捗表示の出力先
 }
 
 // NewAnalyzer は新しいAnalyzerサービスを作成する
@@ -20,62 +24,133 @@ func NewAnalyzer(messageRepo domain.MessageRepository, userRepo domain.UserRepos
 	return &Analyzer{
 		messageRepo: messageRepo,
 		userRepo:    userRepo,
+		progress:    os.Stdout,
 	}
 }
 
+// SetProgressOutput は進捗表示の出力先を設定する（io.Discardで非表示）
+func (a *Analyzer) SetProgressOutput(w io.Writer) {
+	a.progress = w
+}
+
+// ExcludeUsers は集計から除外するユーザーIDを設定する
+func (a *Analyzer) ExcludeUsers(userIDs []string) {
+	a.excludedUsers = make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		a.excludedUsers[userID] = true
+	}
+}
+
+// ExcludeChannels はチャンネルを横断する分析（ユーザー分析・検索結果の分析）から除外するチャンネルIDを設定する
+func (a *Analyzer) ExcludeChannels(channelIDs []string) {
+	a.excludedChannels = make(map[string]bool, len(channelIDs))
+	for _, channelID := range channelIDs {
+		a.excludedChannels[channelID] = true
+	}
+}
+
+// withoutExcludedChannels は除外するチャンネルに投稿されたメッセージを取り除く
+func (a *Analyzer) withoutExcludedChannels(messages []*domain.Message) []*domain.Message {
+	if len(a.excludedChannels) == 0 {
+		return messages
+	}
+	remaining := make([]*domain.Message, 0, len(messages))
+	for _, msg := range messages {
+		if !a.excludedChannels[msg.ChannelID] {
+			remaining = append(remaining, msg)
+		}
+	}
+	return remaining
+}
+
 // AnalyzeChannel はチャンネルのメッセージとリアクションを分析する
 func (a *Analyzer) AnalyzeChannel(ctx context.Context, channelID string, dateRange *domain.DateRange) (*AnalysisResult, error) {
+	messages, warnings, err := a.fetchChannelMessages(ctx, channelID, dateRange, true)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分析結果を集計
+	fmt.Fprintf(a.progress, "分析結果を集計中... (合計: %dメッセージ)\n", len(messages))
+	result := a.aggregate(messages)
+	result.Warnings = warnings
+
+	// ユーザー名を取得
+	users := a.findUsers(ctx, result)
+	fmt.Fprintf(a.progress, "ユーザー情報取得完了\n")
+
+	// ユーザー統計を作成
+	result.UserStats = a.buildUserStats(result.UserMessageCount, users)
+	fmt.Fprintf(a.progress, "分析完了\n\n")
+
+	return result, nil
+}
+
+// fetchChannelMessages はチャンネルのメッセージとスレッドの返信を取得する
+// チャンネルにも送信された返信は履歴と返信の両方に含まれるため、1件として数える
+// スレッド返信の取得に失敗した場合は警告として返し、処理は続行する
+func (a *Analyzer) fetchChannelMessages(ctx context.Context, channelID string, dateRange *domain.DateRange, verbose bool) ([]*domain.Message, []string, error) {
 	// メッセージを取得
-	fmt.Fprintf(os.Stdout, "メッセージを取得中...\n")
+	if verbose {
+		fmt.Fprintf(a.progress, "メッセージを取得中...\n")
+	}
 	messages, err := a.messageRepo.FindByChannel(ctx, channelID, dateRange)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if verbose {
+		fmt.Fprintf(a.progress, "メッセージ取得完了: %d件\n", len(messages))
 	}
-	fmt.Fprintf(os.Stdout, "メッセージ取得完了: %d件\n", len(messages))
 
 	// スレッドの返信も取得
+	seen := make(map[string]bool, len(messages))
+	for _, msg := range messages {
+		seen[msg.ID] = true
+	}
+	var warnings []string
 	threadCount := 0
 	for i, msg := range messages {
 		if msg.IsThreadParent() {
 			threadCount++
-			if threadCount%10 == 0 || i == len(messages)-1 {
-				fmt.Fprintf(os.Stdout, "スレッドを処理中... (%d/%dスレッド)\n", threadCount, countThreads(messages))
+			if verbose && (threadCount%10 == 0 || i == len(messages)-1) {
+				fmt.Fprintf(a.progress, "スレッドを処理中... (%d/%dスレッド)\n", threadCount, countThreads(messages))
 			}
 			replies, err := a.messageRepo.FindThreadReplies(ctx, channelID, msg.ThreadTS, dateRange)
 			if err != nil {
-				// エラーはログに記録するが、処理は続行
+				// エラーは警告として記録し、処理は続行
+				warnings = append(warnings, fmt.Sprintf("スレッド %s の返信取得に失敗しました: %v", msg.ThreadTS, err))
 				continue
 			}
-			messages = append(messages, replies...)
+			for _, reply := range replies {
+				if !seen[reply.ID] {
+					seen[reply.ID] = true
+					messages = append(messages, reply)
+				}
+			}
 		}
 	}
-	if threadCount > 0 {
-		fmt.Fprintf(os.Stdout, "スレッド処理完了: %dスレッドから追加メッセージを取得\n", threadCount)
+	if verbose && threadCount > 0 {
+		fmt.Fprintf(a.progress, "スレッド処理完了: %dスレッドから追加メッセージを取得\n", threadCount)
 	}
 
-	// 分析結果を集計
-	fmt.Fprintf(os.Stdout, "分析結果を集計中... (合計: %dメッセージ)\n", len(messages))
-	result := a.aggregate(messages)
+	return messages, warnings, nil
+}
 
-	// ユーザー名を取得
+// findUsers は集計結果に含まれるユーザーの情報を取得する
+// 取得に失敗した場合は警告を記録し、空のマップを返す（ユーザーIDで表示される）
+func (a *Analyzer) findUsers(ctx context.Context, result *AnalysisResult) map[string]*domain.User {
 	userIDs := make([]string, 0, len(result.UserMessageCount))
 	for userID := range result.UserMessageCount {
 		userIDs = append(userIDs, userID)
 	}
 
-	fmt.Fprintf(os.Stdout, "ユーザー情報を取得中... (%dユーザー)\n", len(userIDs))
+	fmt.Fprintf(a.progress, "ユーザー情報を取得中... (%dユーザー)\n", len(userIDs))
 	users, err := a.userRepo.FindByIDs(ctx, userIDs)
 	if err != nil {
-		// エラーの場合は空のマップを使用
-		users = make(map[string]*domain.User)
+		result.Warnings = append(result.Warnings, fmt.Sprintf("ユーザー情報の取得に失敗しました: %v", err))
+		return make(map[string]*domain.User)
 	}
-	fmt.Fprintf(os.Stdout, "ユーザー情報取得完了\n")
-
-	// ユーザー統計を作成
-	result.UserStats = a.buildUserStats(result.UserMessageCount, users)
-	fmt.Fprintf(os.Stdout, "分析完了\n\n")
-
-	return result, nil
+	return users
 }
 
 // countThreads はスレッドの親メッセージの数をカウントする
@@ -96,6 +171,26 @@ type AnalysisResult struct {
 	ThreadStats      []domain.ThreadStats
 	UserStats        []domain.UserStats
 	UserMessageCount map[string]int
+	Messages         []*domain.Message // 集計対象となったメッセージ（ボット・除外ユーザーを除く）
+	Warnings         []string          // 処理は続行したが一部のデータを取得できなかった場合の警告
+}
+
+// TotalMessages は集計対象となったメッセージ数（ボットを除く）を返す
+func (r *AnalysisResult) TotalMessages() int {
+	total := 0
+	for _, count := range r.UserMessageCount {
+		total += count
+	}
+	return total
+}
+
+// TotalReactions はリアクションの総数を返す
+func (r *AnalysisResult) TotalReactions() int {
+	total := 0
+	for _, stat := range r.EmojiStats {
+		total += stat.Count
+	}
+	return total
 }
 
 // aggregate はメッセージから統計情報を集計する
@@ -104,14 +199,16 @@ func (a *Analyzer) aggregate(messages []*domain.Message) *AnalysisResult {
 	emojiCount := make(map[string]int, len(messages)/10) // 絵文字の種類はメッセージ数の10%程度と仮定
 	messageReactions := make([]domain.MessageReaction, 0, len(messages)/2) // リアクションがあるメッセージは50%程度と仮定
 	userMessageCount := make(map[string]int, len(messages)/20) // ユーザー数はメッセージ数の5%程度と仮定
-	threadReplyCount := make(map[string]int, len(messages)/10) // スレッドの親メッセージID -> コメント数
-	threadParents := make(map[string]*domain.Message, len(messages)/10) // スレッドの親メッセージID -> 親メッセージ
+	threadReplyCount := make(map[string]int, len(messages)/10) // スレッドキー -> コメント数
+	threadParents := make(map[string]*domain.Message, len(messages)/10) // スレッドキー -> 親メッセージ
+	analyzed := make([]*domain.Message, 0, len(messages))
 
 	for _, msg := range messages {
-		// ボットメッセージをスキップ
-		if msg.IsBot {
+		// ボットメッセージと除外対象ユーザーのメッセージをスキップ
+		if msg.IsBot || a.excludedUsers[msg.UserID] {
 			continue
 		}
+		analyzed = append(analyzed, msg)
 
 		// ユーザーメッセージ数をカウント
 		if msg.UserID != "" {
@@ -129,19 +226,22 @@ func (a *Analyzer) aggregate(messages []*domain.Message) *AnalysisResult {
 			messageReactions = append(messageReactions, domain.MessageReaction{
 				Text:      msg.Text,
 				Reactions: totalReactions,
-				Timestamp: msg.Timestamp.Format("20060102.150405"),
+				Timestamp: msg.Timestamp,
+				UserID:    msg.UserID,
+				ChannelID: msg.ChannelID,
+				MessageID: msg.ID,
 			})
 		}
 
 		// スレッドの親メッセージを記録
+		// 複数チャンネルをまとめて集計する場合に備え、キーにはチャンネルIDを含める
 		if msg.IsThreadParent() {
-			threadParents[msg.ID] = msg
-			threadReplyCount[msg.ID] = 0 // 初期化
+			threadParents[threadKey(msg.ChannelID, msg.ID)] = msg
 		}
 
 		// スレッドの返信をカウント
 		if msg.IsThreadReply() {
-			threadReplyCount[msg.ThreadTS]++
+			threadReplyCount[threadKey(msg.ChannelID, msg.ThreadTS)]++
 		}
 	}
 
@@ -161,13 +261,16 @@ func (a *Analyzer) aggregate(messages []*domain.Message) *AnalysisResult {
 
 	// スレッドのコメント数ランキングを作成
 	threadStats := make([]domain.ThreadStats, 0, len(threadParents))
-	for threadID, parentMsg := range threadParents {
-		replyCount := threadReplyCount[threadID]
+	for key, parentMsg := range threadParents {
+		replyCount := threadReplyCount[key]
 		if replyCount > 0 {
 			threadStats = append(threadStats, domain.ThreadStats{
 				Text:       parentMsg.Text,
 				ReplyCount: replyCount,
-				Timestamp:  parentMsg.Timestamp.Format("20060102.150405"),
+				Timestamp:  parentMsg.Timestamp,
+				UserID:     parentMsg.UserID,
+				ChannelID:  parentMsg.ChannelID,
+				MessageID:  parentMsg.ID,
 			})
 		}
 	}
@@ -181,9 +284,15 @@ func (a *Analyzer) aggregate(messages []*domain.Message) *AnalysisResult {
 		MessageStats:     messageReactions,
 		ThreadStats:      threadStats,
 		UserMessageCount: userMessageCount,
+		Messages:         analyzed,
 	}
 }
 
+// threadKey はチャンネルIDとスレッドのタイムスタンプからスレッドを一意に識別するキーを作成する
+func threadKey(channelID, threadTS string) string {
+	return channelID + "/" + threadTS
+}
+
 // buildUserStats はユーザー統計を作成する
 func (a *Analyzer) buildUserStats(userMessageCount map[string]int, users map[string]*domain.User) []domain.UserStats {
 	userStats := make([]domain.UserStats, 0, len(userMessageCount))
@@ -216,35 +325,56 @@ type UserAnalysisResult struct {
 	TotalReactions   int
 	ThreadStats      []domain.ThreadStats
 	ReactionRanking  []domain.EmojiCount
+	Messages         []*domain.Message // 分析で参照したメッセージ（本人の投稿と、その投稿についたスレッド返信）
+	Warnings         []string          // 処理は続行したが一部のデータを取得できなかった場合の警告
 }
 
 // AnalyzeUser は指定されたユーザーのメッセージとリアクションを全チャンネルから分析する
 func (a *Analyzer) AnalyzeUser(ctx context.Context, userName string, dateRange *domain.DateRange) (*UserAnalysisResult, error) {
 	// ユーザー名からユーザーIDを取得
-	fmt.Fprintf(os.Stdout, "ユーザー '%s' を検索中...\n", userName)
+	fmt.Fprintf(a.progress, "ユーザー '%s' を検索中...\n", userName)
 	user, err := a.userRepo.FindByName(ctx, userName)
 	if err != nil {
 		return nil, err
 	}
-	fmt.Fprintf(os.Stdout, "ユーザーID: %s\n", user.ID)
+	fmt.Fprintf(a.progress, "ユーザーID: %s\n", user.ID)
+	return a.analyzeUser(ctx, user, dateRange)
+}
+
+// AnalyzeUserByID はユーザーIDで指定されたユーザーを分析する
+// 名前での検索を行わないため、名前が重複するユーザーと取り違えることがない
+func (a *Analyzer) AnalyzeUserByID(ctx context.Context, userID string, dateRange *domain.DateRange) (*UserAnalysisResult, error) {
+	users, err := a.userRepo.FindByIDs(ctx, []string{userID})
+	if err != nil {
+		return nil, err
+	}
+	user, ok := users[userID]
+	if !ok {
+		return nil, &domain.NotFoundError{Kind: "ユーザー", Name: userID}
+	}
+	return a.analyzeUser(ctx, user, dateRange)
+}
 
+// analyzeUser はユーザーのメッセージを全チャンネルから取得して分析する
+func (a *Analyzer) analyzeUser(ctx context.Context, user *domain.User, dateRange *domain.DateRange) (*UserAnalysisResult, error) {
 	// そのユーザーのメッセージを全チャンネルから取得
-	fmt.Fprintf(os.Stdout, "メッセージを取得中（全チャンネル横断）...\n")
+	fmt.Fprintf(a.progress, "メッセージを取得中（全チャンネル横断）...\n")
 	messages, err := a.messageRepo.FindByUser(ctx, user.ID, dateRange)
 	if err != nil {
 		return nil, err
 	}
-	fmt.Fprintf(os.Stdout, "メッセージ取得完了: %d件\n", len(messages))
+	messages = a.withoutExcludedChannels(messages)
+	fmt.Fprintf(a.progress, "メッセージ取得完了: %d件\n", len(messages))
 
 	// そのユーザーの投稿についたスレッド返信とリアクションを集計
-	fmt.Fprintf(os.Stdout, "スレッド返信とリアクションを集計中...\n")
+	fmt.Fprintf(a.progress, "スレッド返信とリアクションを集計中...\n")
 	result := a.aggregateUserMessages(ctx, messages, user.ID, dateRange)
 
 	result.UserID = user.ID
 	result.UserName = user.GetDisplayName()
 	result.TotalMessages = len(messages)
 
-	fmt.Fprintf(os.Stdout, "分析完了\n\n")
+	fmt.Fprintf(a.progress, "分析完了\n\n")
 	return result, nil
 }
 
@@ -272,24 +402,28 @@ func (a *Analyzer) aggregateUserMessages(ctx context.Context, userMessages []*do
 	}
 
 	// 各スレッドの返信を取得してコメント数をカウント
+	var warnings []string
+	var threadReplies []*domain.Message
 	threadCount := 0
 	for threadID, parentMsg := range threadParents {
 		threadCount++
 		if threadCount%10 == 0 {
-			fmt.Fprintf(os.Stdout, "スレッドを処理中... (%d/%dスレッド)\n", threadCount, len(threadParents))
+			fmt.Fprintf(a.progress, "スレッドを処理中... (%d/%dスレッド)\n", threadCount, len(threadParents))
 		}
 
 		replies, err := a.messageRepo.FindThreadReplies(ctx, parentMsg.ChannelID, threadID, dateRange)
 		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("スレッド %s の返信取得に失敗しました: %v", threadID, err))
 			continue
 		}
 
-		// 返信数をカウント（親メッセージ自体は除外）
+		// 返信数をカウント（親メッセージ自体と除外対象ユーザーの返信は除外）
 		replyCount := 0
 		for _, reply := range replies {
 			// 親メッセージ以外の返信をカウント
-			if reply.ID != threadID {
+			if reply.ID != threadID && !a.excludedUsers[reply.UserID] {
 				replyCount++
+				threadReplies = append(threadReplies, reply)
 			}
 		}
 		threadReplyCount[threadID] = replyCount
@@ -303,7 +437,10 @@ func (a *Analyzer) aggregateUserMessages(ctx context.Context, userMessages []*do
 			threadStats = append(threadStats, domain.ThreadStats{
 				Text:       parentMsg.Text,
 				ReplyCount: replyCount,
-				Timestamp:  parentMsg.Timestamp.Format("20060102.150405"),
+				Timestamp:  parentMsg.Timestamp,
+				UserID:     parentMsg.UserID,
+				ChannelID:  parentMsg.ChannelID,
+				MessageID:  parentMsg.ID,
 			})
 		}
 	}
@@ -312,6 +449,11 @@ func (a *Analyzer) aggregateUserMessages(ctx context.Context, userMessages []*do
 		return threadStats[i].ReplyCount > threadStats[j].ReplyCount
 	})
 
+	// 分析で参照したメッセージ（本人の投稿とスレッド返信）
+	analyzed := make([]*domain.Message, 0, len(userMessages)+len(threadReplies))
+	analyzed = append(analyzed, userMessages...)
+	analyzed = append(analyzed, threadReplies...)
+
 	// スタンプのランキングを作成
 	reactionRanking := make([]domain.EmojiCount, 0, len(emojiCount))
 	for emoji, count := range emojiCount {
@@ -325,5 +467,7 @@ func (a *Analyzer) aggregateUserMessages(ctx context.Context, userMessages []*do
 		TotalReactions:  totalReactions,
 		ThreadStats:     threadStats,
 		ReactionRanking: reactionRanking,
+		Messages:        analyzed,
+		Warnings:        warnings,
 	}
 }
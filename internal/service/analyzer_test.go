@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -11,15 +12,33 @@ import (
 
 // mockMessageRepository はMessageRepositoryのモック実装
 type mockMessageRepository struct {
-	messages []*domain.Message
-	err      error
+	messages    []*domain.Message
+	err         error
+	channelErrs map[string]error  // チャンネルごとのエラー
+	broadcasts  []*domain.Message // チャンネルにも送信された返信（履歴にも含める）
+	searchWarns []string
 }
 
 func (m *mockMessageRepository) FindByChannel(ctx context.Context, channelID string, dateRange *domain.DateRange) ([]*domain.Message, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
-	return m.messages, nil
+	if err := m.channelErrs[channelID]; err != nil {
+		return nil, err
+	}
+	// 指定されたチャンネルのスレッド返信以外のメッセージを返す
+	channelMessages := make([]*domain.Message, 0)
+	for _, msg := range m.messages {
+		if msg.ChannelID == channelID && !msg.IsThreadReply() {
+			channelMessages = append(channelMessages, msg)
+		}
+	}
+	for _, msg := range m.broadcasts {
+		if msg.ChannelID == channelID {
+			channelMessages = append(channelMessages, msg)
+		}
+	}
+	return channelMessages, nil
 }
 
 func (m *mockMessageRepository) FindThreadReplies(ctx context.Context, channelID string, threadTS string, dateRange *domain.DateRange) ([]*domain.Message, error) {
@@ -29,13 +48,30 @@ func (m *mockMessageRepository) FindThreadReplies(ctx context.Context, channelID
 	// スレッドの返信をフィルタリング
 	replies := make([]*domain.Message, 0)
 	for _, msg := range m.messages {
-		if msg.ThreadTS == threadTS && msg.IsThreadReply() {
+		if msg.ChannelID == channelID && msg.ThreadTS == threadTS && msg.IsThreadReply() {
 			replies = append(replies, msg)
 		}
 	}
 	return replies, nil
 }
 
+func (m *mockMessageRepository) FindThread(ctx context.Context, channelID string, threadTS string, dateRange *domain.DateRange) (*domain.Message, []*domain.Message, error) {
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+	var parent *domain.Message
+	for _, msg := range m.messages {
+		if msg.ChannelID == channelID && msg.ID == threadTS {
+			parent = msg
+		}
+	}
+	if parent == nil {
+		return nil, nil, &domain.NotFoundError{Kind: "スレッドの親メッセージ", Name: threadTS}
+	}
+	replies, _ := m.FindThreadReplies(ctx, channelID, threadTS, dateRange)
+	return parent, replies, nil
+}
+
 func (m *mockMessageRepository) FindByUser(ctx context.Context, userID string, dateRange *domain.DateRange) ([]*domain.Message, error) {
 	if m.err != nil {
 		return nil, m.err
@@ -50,6 +86,20 @@ func (m *mockMessageRepository) FindByUser(ctx context.Context, userID string, d
 	return userMessages, nil
 }
 
+func (m *mockMessageRepository) FindBySearch(ctx context.Context, query string, dateRange *domain.DateRange, excludedChannels map[string]bool) ([]*domain.Message, []string, error) {
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+	// テキストにクエリを含むメッセージを検索結果とする
+	matches := make([]*domain.Message, 0)
+	for _, msg := range m.messages {
+		if strings.Contains(msg.Text, query) && !excludedChannels[msg.ChannelID] {
+			matches = append(matches, msg)
+		}
+	}
+	return matches, m.searchWarns, nil
+}
+
 // mockUserRepository はUserRepositoryのモック実装
 type mockUserRepository struct {
 	users map[string]*domain.User
@@ -257,3 +307,43 @@ func TestAnalyzer_AnalyzeChannel(t *testing.T) {
 		})
 	}
 }
+
+func TestAnalyzer_ExcludeUsers(t *testing.T) {
+	now := time.Now()
+	messages := []*domain.Message{
+		{ID: "1", Text: "通常メッセージ", UserID: "U1", ChannelID: "C1", Timestamp: now, ThreadTS: "1",
+			Reactions: []domain.Reaction{{Name: "thumbsup", Count: 2}}},
+		{ID: "2", Text: "除外ユーザーの返信", UserID: "U2", ChannelID: "C1", Timestamp: now, ThreadTS: "1"},
+		{ID: "3", Text: "除外ユーザーの投稿", UserID: "U2", ChannelID: "C1", Timestamp: now,
+			Reactions: []domain.Reaction{{Name: "eyes", Count: 5}}},
+	}
+	users := map[string]*domain.User{
+		"U1": {ID: "U1", Name: "User1"},
+		"U2": {ID: "U2", Name: "deploy-bot"},
+	}
+
+	analyzer := NewAnalyzer(&mockMessageRepository{messages: messages}, &mockUserRepository{users: users})
+	analyzer.ExcludeUsers([]string{"U2"})
+
+	result, err := analyzer.AnalyzeChannel(context.Background(), "C1", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeChannel() error = %v", err)
+	}
+	if len(result.UserStats) != 1 || result.UserStats[0].UserID != "U1" {
+		t.Errorf("UserStats = %+v, want only U1", result.UserStats)
+	}
+	if len(result.EmojiStats) != 1 || result.EmojiStats[0].Emoji != "thumbsup" {
+		t.Errorf("EmojiStats = %+v, want only thumbsup", result.EmojiStats)
+	}
+	if len(result.ThreadStats) != 0 {
+		t.Errorf("ThreadStats = %+v, want none (only reply is from excluded user)", result.ThreadStats)
+	}
+
+	userResult, err := analyzer.AnalyzeUser(context.Background(), "User1", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeUser() error = %v", err)
+	}
+	if len(userResult.ThreadStats) != 0 {
+		t.Errorf("user ThreadStats = %+v, want none", userResult.ThreadStats)
+	}
+}
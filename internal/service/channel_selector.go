@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// SelectChannels はチャンネル名またはglobパターン（例: "team-*"）に一致するチャンネルを返す
+// パターンを含まない名前は完全一致で検索し、見つからない場合はエラーを返す
+func SelectChannels(ctx context.Context, channelRepo domain.ChannelRepository, patterns []string) ([]*domain.Channel, error) {
+	if len(patterns) == 0 {
+		return nil, fmt.Errorf("チャンネル名またはパターンを指定してください")
+	}
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("チャンネルのパターンが不正です: %s", pattern)
+		}
+	}
+
+	allChannels, err := channelRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]*domain.Channel)
+	for _, pattern := range patterns {
+		matched := false
+		for _, channel := range allChannels {
+			if ok, _ := path.Match(pattern, channel.Name); ok {
+				selected[channel.ID] = channel
+				matched = true
+			}
+		}
+		if !matched {
+			if isGlobPattern(pattern) {
+				return nil, &domain.NoMatchError{Pattern: pattern}
+			}
+			return nil, &domain.NotFoundError{Kind: "チャンネル", Name: pattern}
+		}
+	}
+
+	channels := make([]*domain.Channel, 0, len(selected))
+	for _, channel := range selected {
+		channels = append(channels, channel)
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Name < channels[j].Name
+	})
+	return channels, nil
+}
+
+// ExcludeChannels はチャンネル名・IDまたはglobパターンに一致するチャンネルを除外する
+func ExcludeChannels(channels []*domain.Channel, patterns []string) []*domain.Channel {
+	if len(patterns) == 0 {
+		return channels
+	}
+
+	remaining := make([]*domain.Channel, 0, len(channels))
+	for _, channel := range channels {
+		if !matchChannel(channel, patterns) {
+			remaining = append(remaining, channel)
+		}
+	}
+	return remaining
+}
+
+// ExcludedChannelIDs はチャンネル名・IDまたはglobパターンに一致するチャンネルのIDを返す
+func ExcludedChannelIDs(channels []*domain.Channel, patterns []string) []string {
+	var channelIDs []string
+	for _, channel := range channels {
+		if matchChannel(channel, patterns) {
+			channelIDs = append(channelIDs, channel.ID)
+		}
+	}
+	return channelIDs
+}
+
+// matchChannel はチャンネルがいずれかのパターン（チャンネル名・IDまたはglobパターン）に一致するかを返す
+func matchChannel(channel *domain.Channel, patterns []string) bool {
+	for _, pattern := range patterns {
+		if pattern == channel.ID {
+			return true
+		}
+		if ok, _ := path.Match(pattern, channel.Name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// isGlobPattern はglobのメタ文字を含むかどうかを返す
+func isGlobPattern(pattern string) bool {
+	return strings.ContainsAny(pattern, "*?[")
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// mockChannelRepository はChannelRepositoryのモック実装
+type mockChannelRepository struct {
+	channels []*domain.Channel
+	err      error
+}
+
+func (m *mockChannelRepository) FindByName(ctx context.Context, name string) (*domain.Channel, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, channel := range m.channels {
+		if channel.Name == name {
+			return channel, nil
+		}
+	}
+	return nil, fmt.Errorf("チャンネル '%s' が見つかりません", name)
+}
+
+func (m *mockChannelRepository) FindAll(ctx context.Context) ([]*domain.Channel, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.channels, nil
+}
+
+func TestSelectChannels(t *testing.T) {
+	repo := &mockChannelRepository{
+		channels: []*domain.Channel{
+			{ID: "C1", Name: "team-b"},
+			{ID: "C2", Name: "team-a"},
+			{ID: "C3", Name: "general"},
+			{ID: "C4", Name: "random"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "globパターン",
+			patterns: []string{"team-*"},
+			expected: []string{"team-a", "team-b"},
+		},
+		{
+			name:     "名前とパターンの組み合わせ（重複は除外）",
+			patterns: []string{"general", "team-*", "team-a"},
+			expected: []string{"general", "team-a", "team-b"},
+		},
+		{
+			name:     "存在しないチャンネル",
+			patterns: []string{"unknown"},
+			wantErr:  true,
+		},
+		{
+			name:     "一致しないパターン",
+			patterns: []string{"dev-*"},
+			wantErr:  true,
+		},
+		{
+			name:     "不正なパターン",
+			patterns: []string{"team-["},
+			wantErr:  true,
+		},
+		{
+			name:     "指定なし",
+			patterns: nil,
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			channels, err := SelectChannels(context.Background(), repo, tt.patterns)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SelectChannels() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			names := make([]string, 0, len(channels))
+			for _, channel := range channels {
+				names = append(names, channel.Name)
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("SelectChannels() = %v, want %v", names, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExcludeChannels(t *testing.T) {
+	channels := []*domain.Channel{
+		{ID: "C1", Name: "team-a"},
+		{ID: "C2", Name: "team-random"},
+		{ID: "C3", Name: "general"},
+	}
+
+	remaining := ExcludeChannels(channels, []string{"team-random", "gen*"})
+	if len(remaining) != 1 || remaining[0].Name != "team-a" {
+		t.Errorf("ExcludeChannels() = %+v, want [team-a]", remaining)
+	}
+
+	if got := ExcludeChannels(channels, nil); len(got) != len(channels) {
+		t.Errorf("ExcludeChannels(nil) returned %d channels, want %d", len(got), len(channels))
+	}
+
+	if got := ExcludedChannelIDs(channels, []string{"C1", "gen*"}); !reflect.DeepEqual(got, []string{"C1", "C3"}) {
+		t.Errorf("ExcludedChannelIDs() = %v, want [C1 C3]", got)
+	}
+}
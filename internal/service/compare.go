@@ -0,0 +1,170 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// Delta は比較元と比較先の期間における1項目の値の変化を表す
+type Delta struct {
+	Key     string // 項目を識別するキー（絵文字名、ユーザーIDなど）
+	Label   string // 表示名
+	Base    int    // 比較元の期間の値
+	Current int    // 比較先の期間の値
+}
+
+// Change は値の増減（比較先 - 比較元）を返す
+func (d Delta) Change() int {
+	return d.Current - d.Base
+}
+
+// Percent は比較元に対する増減率（%）を返す
+// 比較元が0の場合は増減率を計算できないため false を返す
+func (d Delta) Percent() (float64, bool) {
+	if d.Base == 0 {
+		return 0, false
+	}
+	return float64(d.Change()) / float64(d.Base) * 100, true
+}
+
+// IsNew は比較先の期間で新たに現れた項目かどうかを返す
+func (d Delta) IsNew() bool {
+	return d.Base == 0 && d.Current > 0
+}
+
+// IsDropped は比較先の期間で現れなくなった項目かどうかを返す
+func (d Delta) IsDropped() bool {
+	return d.Base > 0 && d.Current == 0
+}
+
+// Comparison は2つの期間の分析結果の比較を表す
+type Comparison struct {
+	BaseRange    *domain.DateRange
+	CurrentRange *domain.DateRange
+	Summary      []Delta // メッセージ数・リアクション数などの全体の指標
+	Threads      []Delta // スレッドに関する指標
+	Emoji        []Delta // スタンプごとの使用回数（増減の大きい順）
+	Users        []Delta // 投稿者ごとの投稿数（増減の大きい順、ユーザー分析では空）
+}
+
+// CompareChannelResults はチャンネル分析の結果を2つの期間で比較する
+func CompareChannelResults(base, current *AnalysisResult, baseRange, currentRange *domain.DateRange) *Comparison {
+	baseThreads, currentThreads := summarizeThreads(base.ThreadStats), summarizeThreads(current.ThreadStats)
+
+	userNames := make(map[string]string)
+	for _, stats := range [][]domain.UserStats{base.UserStats, current.UserStats} {
+		for _, stat := range stats {
+			userNames[stat.UserID] = stat.UserName
+		}
+	}
+
+	return &Comparison{
+		BaseRange:    baseRange,
+		CurrentRange: currentRange,
+		Summary: []Delta{
+			{Key: "messages", Label: "メッセージ数", Base: base.TotalMessages(), Current: current.TotalMessages()},
+			{Key: "reactions", Label: "リアクション数", Base: base.TotalReactions(), Current: current.TotalReactions()},
+			{Key: "users", Label: "投稿者数", Base: len(base.UserMessageCount), Current: len(current.UserMessageCount)},
+		},
+		Threads: compareThreadSummaries(baseThreads, currentThreads),
+		Emoji:   compareCounts(emojiCounts(base.EmojiStats), emojiCounts(current.EmojiStats), emojiLabel),
+		Users: compareCounts(base.UserMessageCount, current.UserMessageCount, func(userID string) string {
+			if name, ok := userNames[userID]; ok {
+				return name
+			}
+			return userID
+		}),
+	}
+}
+
+// CompareUserResults はユーザー分析の結果を2つの期間で比較する
+func CompareUserResults(base, current *UserAnalysisResult, baseRange, currentRange *domain.DateRange) *Comparison {
+	baseThreads, currentThreads := summarizeThreads(base.ThreadStats), summarizeThreads(current.ThreadStats)
+
+	return &Comparison{
+		BaseRange:    baseRange,
+		CurrentRange: currentRange,
+		Summary: []Delta{
+			{Key: "messages", Label: "投稿数", Base: base.TotalMessages, Current: current.TotalMessages},
+			{Key: "reactions", Label: "スタンプ数", Base: base.TotalReactions, Current: current.TotalReactions},
+		},
+		Threads: compareThreadSummaries(baseThreads, currentThreads),
+		Emoji:   compareCounts(emojiCounts(base.ReactionRanking), emojiCounts(current.ReactionRanking), emojiLabel),
+	}
+}
+
+// threadSummary はスレッドに関する指標
+type threadSummary struct {
+	threads    int // 返信のあるスレッド数
+	replies    int // 返信の総数
+	maxReplies int // 1スレッドの最大返信数
+}
+
+// summarizeThreads はスレッドの統計から指標を集計する
+func summarizeThreads(stats []domain.ThreadStats) threadSummary {
+	var s threadSummary
+	for _, stat := range stats {
+		s.threads++
+		s.replies += stat.ReplyCount
+		s.maxReplies = max(s.maxReplies, stat.ReplyCount)
+	}
+	return s
+}
+
+// compareThreadSummaries はスレッドの指標を比較する
+func compareThreadSummaries(base, current threadSummary) []Delta {
+	return []Delta{
+		{Key: "threads", Label: "スレッド数", Base: base.threads, Current: current.threads},
+		{Key: "replies", Label: "スレッド返信数", Base: base.replies, Current: current.replies},
+		{Key: "max_replies", Label: "最大返信数", Base: base.maxReplies, Current: current.maxReplies},
+	}
+}
+
+// emojiCounts は絵文字の統計をマップに変換する
+func emojiCounts(stats []domain.EmojiCount) map[string]int {
+	counts := make(map[string]int, len(stats))
+	for _, stat := range stats {
+		counts[stat.Emoji] = stat.Count
+	}
+	return counts
+}
+
+// emojiLabel は絵文字名を表示名に変換する
+func emojiLabel(emoji string) string {
+	return ":" + emoji + ":"
+}
+
+// compareCounts は2つの期間の項目ごとの値を比較し、増減の大きい順に並べる
+// 増減が同じ場合は比較先の値が大きい順、さらにキーの順とする
+func compareCounts(base, current map[string]int, label func(key string) string) []Delta {
+	deltas := make([]Delta, 0, len(current))
+	for key, count := range current {
+		deltas = append(deltas, Delta{Key: key, Label: label(key), Base: base[key], Current: count})
+	}
+	for key, count := range base {
+		if _, ok := current[key]; !ok {
+			deltas = append(deltas, Delta{Key: key, Label: label(key), Base: count})
+		}
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		ci, cj := abs(deltas[i].Change()), abs(deltas[j].Change())
+		if ci != cj {
+			return ci > cj
+		}
+		if deltas[i].Current != deltas[j].Current {
+			return deltas[i].Current > deltas[j].Current
+		}
+		return deltas[i].Key < deltas[j].Key
+	})
+	return deltas
+}
+
+// abs は整数の絶対値を返す
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
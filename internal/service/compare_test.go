@@ -0,0 +1,109 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestDelta(t *testing.T) {
+	tests := []struct {
+		name        string
+		delta       Delta
+		wantChange  int
+		wantPercent float64
+		wantOK      bool
+		wantNew     bool
+		wantDropped bool
+	}{
+		{name: "増加", delta: Delta{Base: 40, Current: 50}, wantChange: 10, wantPercent: 25, wantOK: true},
+		{name: "減少", delta: Delta{Base: 50, Current: 40}, wantChange: -10, wantPercent: -20, wantOK: true},
+		{name: "新規", delta: Delta{Base: 0, Current: 3}, wantChange: 3, wantNew: true},
+		{name: "消滅", delta: Delta{Base: 3, Current: 0}, wantChange: -3, wantPercent: -100, wantOK: true, wantDropped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.delta.Change(); got != tt.wantChange {
+				t.Errorf("Change() = %d, want %d", got, tt.wantChange)
+			}
+			percent, ok := tt.delta.Percent()
+			if ok != tt.wantOK || percent != tt.wantPercent {
+				t.Errorf("Percent() = %v, %v, want %v, %v", percent, ok, tt.wantPercent, tt.wantOK)
+			}
+			if got := tt.delta.IsNew(); got != tt.wantNew {
+				t.Errorf("IsNew() = %v, want %v", got, tt.wantNew)
+			}
+			if got := tt.delta.IsDropped(); got != tt.wantDropped {
+				t.Errorf("IsDropped() = %v, want %v", got, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestCompareChannelResults(t *testing.T) {
+	base := &AnalysisResult{
+		EmojiStats:       []domain.EmojiCount{{Emoji: "+1", Count: 10}, {Emoji: "eyes", Count: 4}, {Emoji: "old", Count: 2}},
+		ThreadStats:      []domain.ThreadStats{{ReplyCount: 5}, {ReplyCount: 1}},
+		UserStats:        []domain.UserStats{{UserID: "U1", UserName: "田中太郎", Count: 8}, {UserID: "U2", UserName: "佐藤花子", Count: 2}},
+		UserMessageCount: map[string]int{"U1": 8, "U2": 2},
+	}
+	current := &AnalysisResult{
+		EmojiStats:       []domain.EmojiCount{{Emoji: "+1", Count: 15}, {Emoji: "eyes", Count: 4}, {Emoji: "new", Count: 1}},
+		ThreadStats:      []domain.ThreadStats{{ReplyCount: 3}},
+		UserStats:        []domain.UserStats{{UserID: "U1", UserName: "田中太郎", Count: 12}, {UserID: "U3", UserName: "鈴木一郎", Count: 3}},
+		UserMessageCount: map[string]int{"U1": 12, "U3": 3},
+	}
+
+	cmp := CompareChannelResults(base, current, nil, nil)
+
+	wantSummary := map[string][2]int{"messages": {10, 15}, "reactions": {16, 20}, "users": {2, 2}}
+	for _, d := range cmp.Summary {
+		if want := wantSummary[d.Key]; d.Base != want[0] || d.Current != want[1] {
+			t.Errorf("Summary[%s] = %d -> %d, want %d -> %d", d.Key, d.Base, d.Current, want[0], want[1])
+		}
+	}
+	wantThreads := map[string][2]int{"threads": {2, 1}, "replies": {6, 3}, "max_replies": {5, 3}}
+	for _, d := range cmp.Threads {
+		if want := wantThreads[d.Key]; d.Base != want[0] || d.Current != want[1] {
+			t.Errorf("Threads[%s] = %d -> %d, want %d -> %d", d.Key, d.Base, d.Current, want[0], want[1])
+		}
+	}
+
+	wantEmoji := []string{"+1", "old", "new", "eyes"}
+	if len(cmp.Emoji) != len(wantEmoji) {
+		t.Fatalf("len(Emoji) = %d, want %d", len(cmp.Emoji), len(wantEmoji))
+	}
+	for i, key := range wantEmoji {
+		if cmp.Emoji[i].Key != key {
+			t.Errorf("Emoji[%d] = %s, want %s", i, cmp.Emoji[i].Key, key)
+		}
+	}
+	if !cmp.Emoji[1].IsDropped() || !cmp.Emoji[2].IsNew() {
+		t.Errorf("expected :old: to be dropped and :new: to be new, got %+v", cmp.Emoji)
+	}
+
+	wantUsers := []string{"田中太郎", "鈴木一郎", "佐藤花子"}
+	for i, name := range wantUsers {
+		if cmp.Users[i].Label != name {
+			t.Errorf("Users[%d] = %s, want %s", i, cmp.Users[i].Label, name)
+		}
+	}
+}
+
+func TestCompareUserResults(t *testing.T) {
+	base := &UserAnalysisResult{TotalMessages: 10, TotalReactions: 5, ReactionRanking: []domain.EmojiCount{{Emoji: "+1", Count: 5}}}
+	current := &UserAnalysisResult{TotalMessages: 5, TotalReactions: 8, ReactionRanking: []domain.EmojiCount{{Emoji: "+1", Count: 8}}}
+
+	cmp := CompareUserResults(base, current, nil, nil)
+
+	if got := cmp.Summary[0]; got.Base != 10 || got.Current != 5 {
+		t.Errorf("Summary[0] = %+v, want 10 -> 5", got)
+	}
+	if len(cmp.Users) != 0 {
+		t.Errorf("Users should be empty for user comparison, got %+v", cmp.Users)
+	}
+	if len(cmp.Emoji) != 1 || cmp.Emoji[0].Change() != 3 {
+		t.Errorf("Emoji = %+v, want +1 with change 3", cmp.Emoji)
+	}
+}
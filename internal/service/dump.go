@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// Dumper はリポジトリから取得したメッセージを集計せずにそのまま出力先に渡すサービス
+// メッセージは取得するたびに渡すため、全件をメモリに保持しない
+type Dumper struct {
+	messageRepo domain.MessageRepository
+	progress    io.Writer // 進捗表示の出力先
+	threads     bool      // スレッドの返信も取得するか
+}
+
+// NewDumper は新しいDumperサービスを作成する
+func NewDumper(messageRepo domain.MessageRepository) *Dumper {
+	return &Dumper{
+		messageRepo: messageRepo,
+		progress:    io.Discard,
+		threads:     true,
+	}
+}
+
+// SetProgressOutput は進捗表示の出力先を設定する
+func (d *Dumper) SetProgressOutput(w io.Writer) {
+	d.progress = w
+}
+
+// SetThreads はチャンネルのスレッドの返信も取得するかを設定する（デフォルトは取得する）
+func (d *Dumper) SetThreads(threads bool) {
+	d.threads = threads
+}
+
+// DumpResult は出力したメッセージの件数と、取得できなかったデータの警告を表す
+type DumpResult struct {
+	Messages int
+	Warnings []string
+}
+
+// DumpChannels はチャンネルのメッセージを取得した順に emit に渡す
+// スレッドの親メッセージの直後に、そのスレッドの返信を渡す
+// チャンネルやスレッドの取得に失敗した場合は警告として記録し、処理は続行する
+// 全てのチャンネルの取得に失敗した場合と、emit がエラーを返した場合はエラーを返す
+func (d *Dumper) DumpChannels(ctx context.Context, channels []*domain.Channel, dateRange *domain.DateRange, emit func(*domain.Message) error) (*DumpResult, error) {
+	result := &DumpResult{}
+	var firstErr error
+	fetched := 0
+	for i, channel := range channels {
+		fmt.Fprintf(d.progress, "[%d/%d] #%s のメッセージを取得中...\n", i+1, len(channels), channel.Name)
+		messages, err := d.messageRepo.FindByChannel(ctx, channel.ID, dateRange)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf("#%s のメッセージの取得に失敗しました: %v", channel.Name, err))
+			continue
+		}
+		fetched++
+
+		for _, msg := range messages {
+			if err := d.emit(result, msg, emit); err != nil {
+				return result, err
+			}
+			if !d.threads || !msg.IsThreadParent() {
+				continue
+			}
+			replies, err := d.messageRepo.FindThreadReplies(ctx, channel.ID, msg.ThreadTS, dateRange)
+			if err != nil {
+				if ctx.Err() != nil {
+					return result, ctx.Err()
+				}
+				result.Warnings = append(result.Warnings, fmt.Sprintf("#%s のスレッド %s の返信取得に失敗しました: %v", channel.Name, msg.ThreadTS, err))
+				continue
+			}
+			for _, reply := range replies {
+				if err := d.emit(result, reply, emit); err != nil {
+					return result, err
+				}
+			}
+		}
+	}
+	if fetched == 0 && firstErr != nil {
+		return result, firstErr
+	}
+	fmt.Fprintf(d.progress, "出力完了: %dメッセージ\n", result.Messages)
+	return result, nil
+}
+
+// DumpUser はユーザーのメッセージ（全チャンネル横断）を emit に渡す
+func (d *Dumper) DumpUser(ctx context.Context, userID string, dateRange *domain.DateRange, emit func(*domain.Message) error) (*DumpResult, error) {
+	fmt.Fprintf(d.progress, "メッセージを取得中（全チャンネル横断）...\n")
+	messages, err := d.messageRepo.FindByUser(ctx, userID, dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DumpResult{}
+	for _, msg := range messages {
+		if err := d.emit(result, msg, emit); err != nil {
+			return result, err
+		}
+	}
+	fmt.Fprintf(d.progress, "出力完了: %dメッセージ\n", result.Messages)
+	return result, nil
+}
+
+// emit はメッセージを出力先に渡し、件数を数える
+func (d *Dumper) emit(result *DumpResult, msg *domain.Message, emit func(*domain.Message) error) error {
+	if err := emit(msg); err != nil {
+		return err
+	}
+	result.Messages++
+	return nil
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestDumper_DumpChannels(t *testing.T) {
+	now := time.Now()
+	messages := []*domain.Message{
+		{ID: "1", UserID: "U1", ChannelID: "C1", Timestamp: now, ThreadTS: "1"},
+		{ID: "2", UserID: "U2", ChannelID: "C1", Timestamp: now},
+		{ID: "3", UserID: "U2", ChannelID: "C1", Timestamp: now, ThreadTS: "1"},
+		{ID: "4", UserID: "U1", ChannelID: "C2", Timestamp: now},
+	}
+	channels := []*domain.Channel{{ID: "C1", Name: "team-a"}, {ID: "C2", Name: "team-b"}}
+
+	tests := []struct {
+		name    string
+		threads bool
+		want    []string // channelID/ID の順
+	}{
+		{name: "スレッドの返信は親メッセージの直後", threads: true, want: []string{"C1/1", "C1/3", "C1/2", "C2/4"}},
+		{name: "スレッドの返信を取得しない", threads: false, want: []string{"C1/1", "C1/2", "C2/4"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dumper := NewDumper(&mockMessageRepository{messages: messages})
+			dumper.SetThreads(tt.threads)
+			var got []string
+			result, err := dumper.DumpChannels(context.Background(), channels, nil, func(msg *domain.Message) error {
+				got = append(got, msg.ChannelID+"/"+msg.ID)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("DumpChannels() error = %v", err)
+			}
+			if len(got) != len(tt.want) || result.Messages != len(tt.want) {
+				t.Fatalf("DumpChannels() = %v (%d messages), want %v", got, result.Messages, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("message[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestDumper_DumpChannels_Errors(t *testing.T) {
+	messages := []*domain.Message{{ID: "1", ChannelID: "C2"}, {ID: "2", ChannelID: "C2"}}
+	channels := []*domain.Channel{{ID: "C1", Name: "team-a"}, {ID: "C2", Name: "team-b"}}
+	fetchErr := errors.New("not_in_channel")
+
+	// 一部のチャンネルの取得に失敗した場合は警告として続行する
+	repo := &mockMessageRepository{messages: messages, channelErrs: map[string]error{"C1": fetchErr}}
+	result, err := NewDumper(repo).DumpChannels(context.Background(), channels, nil, func(*domain.Message) error { return nil })
+	if err != nil {
+		t.Fatalf("DumpChannels() error = %v", err)
+	}
+	if result.Messages != 2 || len(result.Warnings) != 1 {
+		t.Errorf("DumpChannels() = %+v, want 2 messages and 1 warning", result)
+	}
+
+	// 全てのチャンネルの取得に失敗した場合はエラー
+	repo = &mockMessageRepository{err: fetchErr}
+	if _, err := NewDumper(repo).DumpChannels(context.Background(), channels, nil, func(*domain.Message) error { return nil }); !errors.Is(err, fetchErr) {
+		t.Errorf("DumpChannels() error = %v, want %v", err, fetchErr)
+	}
+
+	// 出力先のエラーでは中断する
+	writeErr := errors.New("broken pipe")
+	repo = &mockMessageRepository{messages: messages}
+	result, err = NewDumper(repo).DumpChannels(context.Background(), channels, nil, func(*domain.Message) error { return writeErr })
+	if !errors.Is(err, writeErr) || result.Messages != 0 {
+		t.Errorf("DumpChannels() = %+v, %v, want %v", result, err, writeErr)
+	}
+}
+
+func TestDumper_DumpUser(t *testing.T) {
+	messages := []*domain.Message{
+		{ID: "1", UserID: "U1", ChannelID: "C1"},
+		{ID: "2", UserID: "U2", ChannelID: "C1"},
+		{ID: "3", UserID: "U1", ChannelID: "C2", ThreadTS: "1"},
+	}
+	var got []string
+	result, err := NewDumper(&mockMessageRepository{messages: messages}).DumpUser(context.Background(), "U1", nil, func(msg *domain.Message) error {
+		got = append(got, msg.ID)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("DumpUser() error = %v", err)
+	}
+	if result.Messages != 2 || len(got) != 2 || got[0] != "1" || got[1] != "3" {
+		t.Errorf("DumpUser() = %v (%d messages), want [1 3]", got, result.Messages)
+	}
+}
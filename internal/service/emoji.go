@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// Granularity は推移を集計する期間の単位
+type Granularity int
+
+const (
+	GranularityDay   Granularity = iota // 日別
+	GranularityWeek                     // 週別（月曜始まり）
+	GranularityMonth                    // 月別
+	GranularityHour                     // 時間別
+)
+
+// String は期間の単位の表示名を返す
+func (g Granularity) String() string {
+	switch g {
+	case GranularityWeek:
+		return "週別"
+	case GranularityMonth:
+		return "月別"
+	case GranularityHour:
+		return "時間別"
+	default:
+		return "日別"
+	}
+}
+
+// 推移を集計する期間の単位を切り替える期間の長さ
+const (
+	maxHourlySpan = 48 * time.Hour       // これ以下は時間別（スレッドの推移のみ）
+	maxDailySpan  = 31 * 24 * time.Hour  // これ以下は日別
+	maxWeeklySpan = 182 * 24 * time.Hour // これ以下は週別、超える場合は月別
+)
+
+// EmojiPeriod は期間ごとのスタンプの使用回数
+type EmojiPeriod struct {
+	Start    time.Time // 期間の開始日時
+	Count    int       // スタンプの数
+	Messages int       // スタンプがついたメッセージ数
+}
+
+// EmojiAnalysisResult は1つのスタンプの使われ方の分析結果を表す
+type EmojiAnalysisResult struct {
+	Emoji       string                   // 絵文字名（コロンなし）
+	Total       int                      // スタンプの総数
+	Messages    int                      // スタンプがついたメッセージ数
+	Granularity Granularity              // 推移を集計した期間の単位
+	Timeline    []EmojiPeriod            // 期間ごとの推移（メッセージの投稿日時で集計、使用のない期間も含む）
+	Channels    []ChannelActivity        // スタンプが多いチャンネル（Reactions はスタンプの数、Messages はついたメッセージ数）
+	Posters     []domain.UserStats       // スタンプを多く受け取った投稿者（Count はスタンプの数）
+	TopMessages []domain.MessageReaction // スタンプが多くついたメッセージ（Reactions はスタンプの数）
+	Warnings    []string                 // 処理は続行したが一部のデータを取得できなかった場合の警告
+}
+
+// ChannelName はチャンネルIDに対応するチャンネル名を返す（見つからない場合はID）
+func (r *EmojiAnalysisResult) ChannelName(channelID string) string {
+	for _, activity := range r.Channels {
+		if activity.Channel.ID == channelID {
+			return activity.Channel.Name
+		}
+	}
+	return channelID
+}
+
+// AnalyzeEmoji は複数チャンネルのメッセージについた1つのスタンプの使われ方を分析する
+// emoji には絵文字名を指定する（":tada:" のようにコロンで囲んでもよい。肌の色のバリエーションはまとめて集計する）
+func (a *Analyzer) AnalyzeEmoji(ctx context.Context, channels []*domain.Channel, emoji string, dateRange *domain.DateRange) (*EmojiAnalysisResult, error) {
+	result, err := a.AnalyzeChannels(ctx, channels, dateRange)
+	if err != nil {
+		return nil, err
+	}
+	return emojiUsage(result, domain.NormalizeEmojiName(emoji), dateRange), nil
+}
+
+// emojiUsage は分析結果のメッセージから、1つのスタンプのチャンネル・投稿者・メッセージ・期間ごとの数を集計する
+// メッセージは AnalyzeChannels の集計対象（ボット・除外ユーザーを除く）と同じ
+func emojiUsage(result *MultiChannelResult, emoji string, dateRange *domain.DateRange) *EmojiAnalysisResult {
+	usage := &EmojiAnalysisResult{Emoji: emoji, Warnings: result.Merged.Warnings}
+	channelUsage := make(map[string]*ChannelActivity)
+	posterCount := make(map[string]int)
+	var used []*domain.Message
+
+	for _, msg := range result.Merged.Messages {
+		count := msg.ReactionCount(emoji)
+		if count == 0 {
+			continue
+		}
+		used = append(used, msg)
+		usage.Total += count
+		usage.Messages++
+
+		activity, ok := channelUsage[msg.ChannelID]
+		if !ok {
+			activity = &ChannelActivity{Channel: &domain.Channel{ID: msg.ChannelID, Name: result.ChannelName(msg.ChannelID)}}
+			channelUsage[msg.ChannelID] = activity
+		}
+		activity.Messages++
+		activity.Reactions += count
+
+		if msg.UserID != "" {
+			posterCount[msg.UserID] += count
+		}
+
+		usage.TopMessages = append(usage.TopMessages, domain.MessageReaction{
+			Text:      msg.Text,
+			Reactions: count,
+			Timestamp: msg.Timestamp,
+			UserID:    msg.UserID,
+			ChannelID: msg.ChannelID,
+			MessageID: msg.ID,
+		})
+	}
+
+	// チャンネルは指定された順に並べてから、スタンプの数でソートする（同数の場合は指定された順）
+	for _, ch := range result.Channels {
+		if activity, ok := channelUsage[ch.Channel.ID]; ok {
+			activity.Channel = ch.Channel
+			usage.Channels = append(usage.Channels, *activity)
+			delete(channelUsage, ch.Channel.ID) // 重複して指定されたチャンネル
+		}
+	}
+	sort.SliceStable(usage.Channels, func(i, j int) bool {
+		return usage.Channels[i].Reactions > usage.Channels[j].Reactions
+	})
+
+	userNames := make(map[string]string, len(result.Merged.UserStats))
+	for _, stat := range result.Merged.UserStats {
+		userNames[stat.UserID] = stat.UserName
+	}
+	for userID, count := range posterCount {
+		name := userNames[userID]
+		if name == "" {
+			name = userID
+		}
+		usage.Posters = append(usage.Posters, domain.UserStats{UserID: userID, UserName: name, Count: count})
+	}
+	sort.Slice(usage.Posters, func(i, j int) bool {
+		if usage.Posters[i].Count != usage.Posters[j].Count {
+			return usage.Posters[i].Count > usage.Posters[j].Count
+		}
+		return usage.Posters[i].UserName < usage.Posters[j].UserName
+	})
+
+	sort.SliceStable(usage.TopMessages, func(i, j int) bool {
+		return usage.TopMessages[i].Reactions > usage.TopMessages[j].Reactions
+	})
+
+	usage.Granularity, usage.Timeline = emojiTimeline(used, emoji, dateRange)
+	return usage
+}
+
+// emojiTimeline はスタンプがついたメッセージを投稿日時で期間ごとに集計する
+// Slack APIではリアクションがついた日時を取得できないため、メッセージの投稿日時を使用する
+// 期間の単位は分析期間（指定がない場合は最初と最後の使用日時）の長さから決め、使用のない期間も0件として含める
+func emojiTimeline(messages []*domain.Message, emoji string, dateRange *domain.DateRange) (Granularity, []EmojiPeriod) {
+	start, end, ok := timelineSpan(messages, dateRange)
+	if !ok {
+		return GranularityDay, nil
+	}
+
+	granularity := granularityFor(end.Sub(start))
+	return granularity, timeline(messages, start, end, granularity,
+		func(start time.Time) EmojiPeriod { return EmojiPeriod{Start: start} },
+		func(period *EmojiPeriod, msg *domain.Message) {
+			period.Count += msg.ReactionCount(emoji)
+			period.Messages++
+		})
+}
+
+// timeline はメッセージを投稿日時で start から end までの granularity ごとの期間に振り分け、add で集計する
+// 期間の区切りは start のタイムゾーンで求め、メッセージのない期間も newPeriod で作成した値として含める
+func timeline[T any](messages []*domain.Message, start, end time.Time, granularity Granularity, newPeriod func(start time.Time) T, add func(period *T, msg *domain.Message)) []T {
+	location := start.Location()
+	var periods []T
+	index := make(map[int64]int)
+	for period := periodStart(start, granularity); !period.After(end); period = nextPeriod(period, granularity) {
+		index[period.Unix()] = len(periods)
+		periods = append(periods, newPeriod(period))
+	}
+	for _, msg := range messages {
+		if i, ok := index[periodStart(msg.Timestamp.In(location), granularity).Unix()]; ok {
+			add(&periods[i], msg)
+		}
+	}
+	return periods
+}
+
+// timelineSpan は推移を集計する期間を返す
+// 分析期間の指定がない側は、メッセージの最初または最後の投稿日時とする（期間を決められない場合は ok がfalse）
+func timelineSpan(messages []*domain.Message, dateRange *domain.DateRange) (start, end time.Time, ok bool) {
+	if dateRange != nil {
+		start, end = dateRange.Start, dateRange.End
+	}
+	for _, msg := range messages {
+		if dateRange == nil || dateRange.Start.IsZero() {
+			if start.IsZero() || msg.Timestamp.Before(start) {
+				start = msg.Timestamp
+			}
+		}
+		if dateRange == nil || dateRange.End.IsZero() {
+			if msg.Timestamp.After(end) {
+				end = msg.Timestamp
+			}
+		}
+	}
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// granularityFor は推移を集計する期間の長さから、日別・週別・月別のいずれかの単位を返す
+func granularityFor(span time.Duration) Granularity {
+	switch {
+	case span <= maxDailySpan:
+		return GranularityDay
+	case span <= maxWeeklySpan:
+		return GranularityWeek
+	default:
+		return GranularityMonth
+	}
+}
+
+// periodStart は日時を含む期間の開始日時（その時刻の0分・その日・その週の月曜日・その月の1日の0時）を返す
+func periodStart(t time.Time, granularity Granularity) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch granularity {
+	case GranularityHour:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	case GranularityWeek:
+		offset := (int(day.Weekday()) + 6) % 7 // 月曜日からの日数
+		return day.AddDate(0, 0, -offset)
+	case GranularityMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	default:
+		return day
+	}
+}
+
+// nextPeriod は次の期間の開始日時を返す
+func nextPeriod(start time.Time, granularity Granularity) time.Time {
+	switch granularity {
+	case GranularityHour:
+		return start.Add(time.Hour)
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestAnalyzer_AnalyzeEmoji(t *testing.T) {
+	day := func(d int) time.Time {
+		return time.Date(2024, 1, d, 12, 0, 0, 0, time.UTC)
+	}
+	messages := []*domain.Message{
+		{ID: "1", Text: "リリースしました", UserID: "U1", ChannelID: "C1", Timestamp: day(1),
+			Reactions: []domain.Reaction{{Name: "tada", Count: 3}, {Name: "eyes", Count: 1}}},
+		{ID: "2", Text: "入社しました", UserID: "U2", ChannelID: "C2", Timestamp: day(3),
+			Reactions: []domain.Reaction{{Name: "tada", Count: 5}}},
+		{ID: "3", Text: "v2もリリース", UserID: "U1", ChannelID: "C1", Timestamp: day(3),
+			Reactions: []domain.Reaction{{Name: "tada", Count: 1}}},
+		{ID: "4", Text: "確認します", UserID: "U3", ChannelID: "C2", Timestamp: day(2),
+			Reactions: []domain.Reaction{{Name: "eyes", Count: 2}}},
+	}
+	users := map[string]*domain.User{
+		"U1": {ID: "U1", Name: "User1"},
+		"U2": {ID: "U2", Name: "User2"},
+		"U3": {ID: "U3", Name: "User3"},
+	}
+	channels := []*domain.Channel{
+		{ID: "C1", Name: "dev"},
+		{ID: "C2", Name: "general"},
+		{ID: "C1", Name: "dev"}, // 重複指定
+	}
+
+	analyzer := NewAnalyzer(&mockMessageRepository{messages: messages}, &mockUserRepository{users: users})
+	analyzer.SetProgressOutput(io.Discard)
+	result, err := analyzer.AnalyzeEmoji(context.Background(), channels, ":tada:", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeEmoji() error = %v", err)
+	}
+
+	if result.Emoji != "tada" || result.Total != 9 || result.Messages != 3 {
+		t.Errorf("Emoji/Total/Messages = %s/%d/%d, want tada/9/3", result.Emoji, result.Total, result.Messages)
+	}
+
+	// チャンネルはスタンプの数の多い順（重複指定は1つにまとめる）
+	if len(result.Channels) != 2 || result.Channels[0].Channel.Name != "general" || result.Channels[0].Reactions != 5 ||
+		result.Channels[1].Channel.Name != "dev" || result.Channels[1].Reactions != 4 || result.Channels[1].Messages != 2 {
+		t.Errorf("Channels = %+v, want general=5, dev=4 (2 messages)", result.Channels)
+	}
+
+	// 投稿者はスタンプを受け取った数の多い順（スタンプを受け取っていない投稿者は含まない）
+	if len(result.Posters) != 2 || result.Posters[0].UserName != "User2" || result.Posters[0].Count != 5 ||
+		result.Posters[1].UserName != "User1" || result.Posters[1].Count != 4 {
+		t.Errorf("Posters = %+v, want User2=5, User1=4", result.Posters)
+	}
+
+	if len(result.TopMessages) != 3 || result.TopMessages[0].Text != "入社しました" || result.TopMessages[0].Reactions != 5 {
+		t.Errorf("TopMessages = %+v, want 入社しました=5 first", result.TopMessages)
+	}
+	if got := result.ChannelName("C2"); got != "general" {
+		t.Errorf("ChannelName(C2) = %q, want general", got)
+	}
+
+	// 最初と最後の使用日の間は日別で、使用のない日も含む
+	if result.Granularity != GranularityDay {
+		t.Errorf("Granularity = %v, want %v", result.Granularity, GranularityDay)
+	}
+	wantCounts := []int{3, 0, 6}
+	if len(result.Timeline) != len(wantCounts) {
+		t.Fatalf("Timeline = %+v, want %d days", result.Timeline, len(wantCounts))
+	}
+	for i, want := range wantCounts {
+		if result.Timeline[i].Count != want {
+			t.Errorf("Timeline[%d].Count = %d, want %d", i, result.Timeline[i].Count, want)
+		}
+	}
+}
+
+func TestEmojiTimeline_Granularity(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	messages := []*domain.Message{
+		// 2024-01-10（水）の JST 8時は UTC では前日
+		{ID: "1", Timestamp: time.Date(2024, 1, 9, 23, 0, 0, 0, time.UTC), Reactions: []domain.Reaction{{Name: "tada", Count: 2}}},
+		{ID: "2", Timestamp: time.Date(2024, 3, 20, 3, 0, 0, 0, time.UTC), Reactions: []domain.Reaction{{Name: "tada::skin-tone-2", Count: 1}}},
+	}
+
+	tests := []struct {
+		name            string
+		dateRange       *domain.DateRange
+		wantGranularity Granularity
+		wantPeriods     int
+		wantFirst       time.Time
+	}{
+		{
+			name: "半年以内は週別",
+			dateRange: &domain.DateRange{
+				Start: time.Date(2024, 1, 1, 0, 0, 0, 0, jst),
+				End:   time.Date(2024, 3, 31, 23, 59, 59, 0, jst),
+			},
+			wantGranularity: GranularityWeek,
+			wantPeriods:     13,
+			wantFirst:       time.Date(2024, 1, 1, 0, 0, 0, 0, jst),
+		},
+		{
+			name: "半年を超える場合は月別",
+			dateRange: &domain.DateRange{
+				Start: time.Date(2023, 10, 15, 0, 0, 0, 0, jst),
+				End:   time.Date(2024, 4, 30, 23, 59, 59, 0, jst),
+			},
+			wantGranularity: GranularityMonth,
+			wantPeriods:     7,
+			wantFirst:       time.Date(2023, 10, 1, 0, 0, 0, 0, jst),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			granularity, timeline := emojiTimeline(messages, "tada", tt.dateRange)
+			if granularity != tt.wantGranularity {
+				t.Errorf("granularity = %v, want %v", granularity, tt.wantGranularity)
+			}
+			if len(timeline) != tt.wantPeriods {
+				t.Fatalf("len(timeline) = %d, want %d", len(timeline), tt.wantPeriods)
+			}
+			if !timeline[0].Start.Equal(tt.wantFirst) {
+				t.Errorf("timeline[0].Start = %v, want %v", timeline[0].Start, tt.wantFirst)
+			}
+			total := 0
+			for _, period := range timeline {
+				total += period.Count
+			}
+			if total != 3 {
+				t.Errorf("total count = %d, want 3", total)
+			}
+		})
+	}
+}
@@ -0,0 +1,288 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// Slack APIのメソッドと、そのレート制限のティアにおける1分あたりの呼び出し回数
+// （https://api.slack.com/apis/rate-limits。Tier 2: 20回/分、Tier 3: 50回/分）
+const (
+	MethodConversationsHistory = "conversations.history" // Tier 3
+	MethodConversationsReplies = "conversations.replies" // Tier 3
+	MethodSearchMessages       = "search.messages"       // Tier 2
+)
+
+// ratePerMinute はメソッドごとの1分あたりの呼び出し回数の上限
+var ratePerMinute = map[string]int{
+	MethodConversationsHistory: 50,
+	MethodConversationsReplies: 50,
+	MethodSearchMessages:       20,
+}
+
+// ページあたりの取得件数（MessageRepository と同じ値）
+const (
+	historyPageSize = 1000
+	searchPageSize  = 100
+)
+
+// DefaultSampleChannels は見積もりで履歴をサンプリングするチャンネル数のデフォルト値
+const DefaultSampleChannels = 20
+
+// sampleLimit はサンプリングで取得する1チャンネルあたりのメッセージ数
+const sampleLimit = 200
+
+// HistorySampler は見積もりのためにメッセージ履歴の一部を取得する
+type HistorySampler interface {
+	SampleHistory(ctx context.Context, channelID string, dateRange *domain.DateRange, limit int) (*domain.HistorySample, error)
+	SampleUserSearch(ctx context.Context, userID string, dateRange *domain.DateRange) (*domain.SearchSample, error)
+}
+
+// APICalls はAPIメソッドごとの呼び出し回数の見積もり
+type APICalls struct {
+	Method    string
+	Calls     int
+	PerMinute int // レート制限（1分あたりの呼び出し回数）
+}
+
+// Duration はレート制限の上限で呼び出した場合の所要時間を返す
+func (c APICalls) Duration() time.Duration {
+	if c.PerMinute <= 0 {
+		return 0
+	}
+	return time.Duration(float64(c.Calls) / float64(c.PerMinute) * float64(time.Minute))
+}
+
+// Estimate は分析に必要なAPI呼び出し回数と所要時間の見積もり
+type Estimate struct {
+	Channels        int // 履歴を取得するチャンネル数
+	SampledChannels int // 履歴をサンプリングしたチャンネル数
+	Messages        int // 推定メッセージ数
+	ThreadParents   int // 推定スレッド数（返信のある親メッセージ）
+	Calls           []APICalls
+	Notes           []string // 見積もりの前提や精度に関する補足
+}
+
+// TotalCalls はAPI呼び出し回数の合計を返す
+func (e *Estimate) TotalCalls() int {
+	total := 0
+	for _, c := range e.Calls {
+		total += c.Calls
+	}
+	return total
+}
+
+// Duration は全てのAPI呼び出しをレート制限の上限で順に実行した場合の所要時間を返す
+func (e *Estimate) Duration() time.Duration {
+	var total time.Duration
+	for _, c := range e.Calls {
+		total += c.Duration()
+	}
+	return total
+}
+
+// Estimator はチャンネルの履歴をサンプリングして、分析に必要なAPI呼び出し回数を見積もる
+type Estimator struct {
+	sampler    HistorySampler
+	maxSamples int
+}
+
+// NewEstimator は新しいEstimatorを作成する
+// maxSamples は履歴をサンプリングするチャンネル数の上限（0以下の場合は DefaultSampleChannels）
+func NewEstimator(sampler HistorySampler, maxSamples int) *Estimator {
+	if maxSamples <= 0 {
+		maxSamples = DefaultSampleChannels
+	}
+	return &Estimator{
+		sampler:    sampler,
+		maxSamples: maxSamples,
+	}
+}
+
+// channelVolume はチャンネルの期間内のメッセージ数とスレッド数の推定値
+type channelVolume struct {
+	messages      float64
+	threadParents float64
+}
+
+// EstimateChannels はチャンネル分析（channel / channels / workspace）の見積もりを作成する
+// 全チャンネルの履歴と、各スレッドの返信を取得する呼び出し回数を求める
+func (e *Estimator) EstimateChannels(ctx context.Context, channels []*domain.Channel, dateRange *domain.DateRange) (*Estimate, error) {
+	volumes, sampled, err := e.sampleVolumes(ctx, channels, dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate := &Estimate{Channels: len(channels), SampledChannels: sampled}
+	var historyCalls, messages, threadParents float64
+	for _, v := range volumes {
+		historyCalls += historyPages(v.messages)
+		messages += v.messages
+		threadParents += v.threadParents
+	}
+	estimate.Messages = int(math.Round(messages))
+	estimate.ThreadParents = int(math.Round(threadParents))
+	estimate.Calls = []APICalls{
+		newAPICalls(MethodConversationsHistory, historyCalls),
+		newAPICalls(MethodConversationsReplies, threadParents),
+	}
+	if sampled < len(channels) {
+		estimate.Notes = append(estimate.Notes, fmt.Sprintf("%dチャンネルのうち%dチャンネルの履歴をサンプリングし、残りはその平均で推定しました", len(channels), sampled))
+	}
+	return estimate, nil
+}
+
+// EstimateUser はユーザー分析（user）の見積もりを作成する
+// Search APIを使用できる場合は検索と投稿のあるチャンネルの履歴、使用できない場合は全チャンネル横断方式の呼び出し回数を求める
+// channels には FindAll で取得した全チャンネルを指定する（参加していないチャンネルも1回ずつ呼び出される）
+func (e *Estimator) EstimateUser(ctx context.Context, userID string, channels []*domain.Channel, dateRange *domain.DateRange) (*Estimate, error) {
+	var members []*domain.Channel
+	for _, channel := range channels {
+		if channel.IsMember {
+			members = append(members, channel)
+		}
+	}
+
+	search, searchErr := e.sampler.SampleUserSearch(ctx, userID, dateRange)
+	if searchErr != nil {
+		return e.estimateUserFallback(ctx, channels, members, dateRange, searchErr)
+	}
+
+	// Search APIで見つかった投稿のあるチャンネルのみ、リアクションを補完するために履歴を取得する
+	pages := math.Max(1, math.Ceil(float64(search.Total)/searchPageSize))
+	postedChannels := search.Channels
+	if !search.Complete {
+		postedChannels = min(len(members), search.Channels*int(pages))
+	}
+	volumes, sampled, err := e.sampleVolumes(ctx, members, dateRange)
+	if err != nil {
+		return nil, err
+	}
+	average := averageVolume(volumes)
+
+	estimate := &Estimate{Channels: postedChannels, SampledChannels: sampled, Messages: search.Total}
+	threadRatio := 0.0
+	if average.messages > 0 {
+		threadRatio = average.threadParents / average.messages
+	}
+	threadParents := float64(search.Total) * threadRatio
+	estimate.ThreadParents = int(math.Round(threadParents))
+	estimate.Calls = []APICalls{
+		newAPICalls(MethodSearchMessages, pages),
+		newAPICalls(MethodConversationsHistory, float64(postedChannels)*historyPages(average.messages)),
+		newAPICalls(MethodConversationsReplies, threadParents),
+	}
+	if !search.Complete {
+		estimate.Notes = append(estimate.Notes, fmt.Sprintf("投稿のあるチャンネル数は検索結果の最初の%d件から推定した上限です", searchPageSize))
+	}
+	estimate.Notes = append(estimate.Notes, "スレッドの数は、サンプリングしたチャンネルのスレッドの割合から推定しました")
+	return estimate, nil
+}
+
+// estimateUserFallback はSearch APIを使用できない場合（全チャンネル横断方式）の見積もりを作成する
+func (e *Estimator) estimateUserFallback(ctx context.Context, channels, members []*domain.Channel, dateRange *domain.DateRange, searchErr error) (*Estimate, error) {
+	estimate, err := e.EstimateChannels(ctx, members, dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	// 参加していないチャンネルも履歴の取得を試みて1回ずつ失敗する
+	if nonMembers := len(channels) - len(members); nonMembers > 0 {
+		estimate.Calls[0].Calls += nonMembers
+		estimate.Notes = append(estimate.Notes, fmt.Sprintf("参加していない%dチャンネルも1回ずつ呼び出されます", nonMembers))
+	}
+	estimate.Channels = len(channels)
+	// 返信を取得するのはユーザーが投稿したスレッドのみだが、その数は事前に分からないため上限として全スレッドを数える
+	estimate.Notes = append(estimate.Notes,
+		fmt.Sprintf("Search APIを使用できないため、全チャンネル横断方式で見積もりました（%v）", searchErr),
+		"conversations.replies はユーザーが投稿したスレッドのみ呼び出すため、見積もりは上限です",
+	)
+	return estimate, nil
+}
+
+// sampleVolumes はチャンネルの履歴をサンプリングし、全チャンネルの期間内のメッセージ数を推定する
+// サンプリングしなかったチャンネルは、サンプリングしたチャンネルの平均とする
+func (e *Estimator) sampleVolumes(ctx context.Context, channels []*domain.Channel, dateRange *domain.DateRange) ([]channelVolume, int, error) {
+	volumes := make([]channelVolume, len(channels))
+	if len(channels) == 0 {
+		return volumes, 0, nil
+	}
+
+	// チャンネル一覧の全体から均等な間隔でサンプリングする
+	samples := min(len(channels), e.maxSamples)
+	sampled := make([]bool, len(channels))
+	var total channelVolume
+	for i := 0; i < samples; i++ {
+		index := i * len(channels) / samples
+		channel := channels[index]
+		sample, err := e.sampler.SampleHistory(ctx, channel.ID, dateRange, sampleLimit)
+		if err != nil {
+			return nil, 0, fmt.Errorf("チャンネル '%s' の履歴のサンプリングに失敗しました: %w", channel.Name, err)
+		}
+		volumes[index] = extrapolate(sample, rangeStart(dateRange, channel))
+		sampled[index] = true
+		total.messages += volumes[index].messages
+		total.threadParents += volumes[index].threadParents
+	}
+
+	average := channelVolume{messages: total.messages / float64(samples), threadParents: total.threadParents / float64(samples)}
+	for i := range volumes {
+		if !sampled[i] {
+			volumes[i] = average
+		}
+	}
+	return volumes, samples, nil
+}
+
+// extrapolate はサンプリングした最新のページのメッセージの密度から、期間の開始までのメッセージ数を推定する
+func extrapolate(sample *domain.HistorySample, start time.Time) channelVolume {
+	volume := channelVolume{messages: float64(sample.Messages), threadParents: float64(sample.ThreadParents)}
+	if !sample.HasMore || start.IsZero() {
+		return volume
+	}
+	span := sample.Newest.Sub(sample.Oldest)
+	remaining := sample.Oldest.Sub(start)
+	if span <= 0 || remaining <= 0 {
+		return volume
+	}
+	factor := 1 + float64(remaining)/float64(span)
+	volume.messages *= factor
+	volume.threadParents *= factor
+	return volume
+}
+
+// rangeStart は推定に使用する期間の開始日時を返す（開始日の指定がない場合はチャンネルの作成日時）
+func rangeStart(dateRange *domain.DateRange, channel *domain.Channel) time.Time {
+	if dateRange != nil && !dateRange.Start.IsZero() {
+		return dateRange.Start
+	}
+	return channel.Created
+}
+
+// averageVolume はチャンネルあたりの平均のメッセージ数とスレッド数を返す
+func averageVolume(volumes []channelVolume) channelVolume {
+	var total channelVolume
+	if len(volumes) == 0 {
+		return total
+	}
+	for _, v := range volumes {
+		total.messages += v.messages
+		total.threadParents += v.threadParents
+	}
+	n := float64(len(volumes))
+	return channelVolume{messages: total.messages / n, threadParents: total.threadParents / n}
+}
+
+// historyPages はメッセージ数から履歴の取得に必要なページ数（最低1回）を返す
+func historyPages(messages float64) float64 {
+	return math.Max(1, math.Ceil(messages/historyPageSize))
+}
+
+// newAPICalls は推定した呼び出し回数を整数に丸めてAPICallsを作成する
+func newAPICalls(method string, calls float64) APICalls {
+	return APICalls{Method: method, Calls: int(math.Ceil(calls)), PerMinute: ratePerMinute[method]}
+}
@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// mockHistorySampler はHistorySamplerのモック実装
+type mockHistorySampler struct {
+	samples   map[string]*domain.HistorySample
+	search    *domain.SearchSample
+	searchErr error
+	calls     int
+}
+
+func (m *mockHistorySampler) SampleHistory(ctx context.Context, channelID string, dateRange *domain.DateRange, limit int) (*domain.HistorySample, error) {
+	m.calls++
+	sample, ok := m.samples[channelID]
+	if !ok {
+		return nil, errors.New("channel_not_found")
+	}
+	return sample, nil
+}
+
+func (m *mockHistorySampler) SampleUserSearch(ctx context.Context, userID string, dateRange *domain.DateRange) (*domain.SearchSample, error) {
+	return m.search, m.searchErr
+}
+
+// findCalls はメソッドの呼び出し回数の見積もりを返す
+func findCalls(t *testing.T, estimate *Estimate, method string) APICalls {
+	t.Helper()
+	for _, c := range estimate.Calls {
+		if c.Method == method {
+			return c
+		}
+	}
+	t.Fatalf("calls for %s not found in %+v", method, estimate.Calls)
+	return APICalls{}
+}
+
+func TestEstimator_EstimateChannels(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sampler := &mockHistorySampler{
+		samples: map[string]*domain.HistorySample{
+			// 最新200件が1日分で、期間の開始まで残り9日分ある → 2000件、スレッド100件と推定
+			"C1": {Messages: 200, ThreadParents: 10, HasMore: true, Oldest: start.AddDate(0, 0, 9), Newest: start.AddDate(0, 0, 10)},
+			// 期間内の全件を取得済み
+			"C2": {Messages: 50, ThreadParents: 4},
+		},
+	}
+	channels := []*domain.Channel{{ID: "C1", Name: "general"}, {ID: "C2", Name: "random"}}
+	dateRange := &domain.DateRange{Start: start, End: start.AddDate(0, 0, 10)}
+
+	estimate, err := NewEstimator(sampler, 0).EstimateChannels(context.Background(), channels, dateRange)
+	if err != nil {
+		t.Fatalf("EstimateChannels() error = %v", err)
+	}
+
+	if estimate.Messages != 2050 || estimate.ThreadParents != 104 {
+		t.Errorf("Messages, ThreadParents = %d, %d, want 2050, 104", estimate.Messages, estimate.ThreadParents)
+	}
+	// C1: 2000件 → 2ページ、C2: 1ページ
+	if got := findCalls(t, estimate, MethodConversationsHistory); got.Calls != 3 || got.PerMinute != 50 {
+		t.Errorf("history calls = %+v, want 3 calls at 50/min", got)
+	}
+	if got := findCalls(t, estimate, MethodConversationsReplies); got.Calls != 104 {
+		t.Errorf("replies calls = %d, want 104", got.Calls)
+	}
+	if want := time.Duration(107.0 / 50 * float64(time.Minute)); estimate.Duration() != want {
+		t.Errorf("Duration() = %v, want %v", estimate.Duration(), want)
+	}
+}
+
+func TestEstimator_EstimateChannels_SamplesLimit(t *testing.T) {
+	sampler := &mockHistorySampler{samples: map[string]*domain.HistorySample{}}
+	var channels []*domain.Channel
+	for _, id := range []string{"C1", "C2", "C3", "C4"} {
+		channels = append(channels, &domain.Channel{ID: id, Name: id})
+		sampler.samples[id] = &domain.HistorySample{Messages: 10, ThreadParents: 2}
+	}
+
+	estimate, err := NewEstimator(sampler, 2).EstimateChannels(context.Background(), channels, nil)
+	if err != nil {
+		t.Fatalf("EstimateChannels() error = %v", err)
+	}
+	if sampler.calls != 2 || estimate.SampledChannels != 2 {
+		t.Errorf("sampled %d channels (SampledChannels=%d), want 2", sampler.calls, estimate.SampledChannels)
+	}
+	// サンプリングしなかったチャンネルは平均で推定する
+	if estimate.Messages != 40 || estimate.ThreadParents != 8 {
+		t.Errorf("Messages, ThreadParents = %d, %d, want 40, 8", estimate.Messages, estimate.ThreadParents)
+	}
+	if len(estimate.Notes) == 0 {
+		t.Error("Notes is empty, want a note about sampling")
+	}
+}
+
+func TestEstimator_EstimateUser(t *testing.T) {
+	channels := []*domain.Channel{
+		{ID: "C1", Name: "general", IsMember: true},
+		{ID: "C2", Name: "random", IsMember: true},
+		{ID: "C3", Name: "secret", IsMember: false},
+	}
+	samples := map[string]*domain.HistorySample{
+		"C1": {Messages: 100, ThreadParents: 10},
+		"C2": {Messages: 100, ThreadParents: 10},
+	}
+
+	t.Run("Search API", func(t *testing.T) {
+		sampler := &mockHistorySampler{samples: samples, search: &domain.SearchSample{Total: 250, Channels: 1}}
+		estimate, err := NewEstimator(sampler, 0).EstimateUser(context.Background(), "U1", channels, nil)
+		if err != nil {
+			t.Fatalf("EstimateUser() error = %v", err)
+		}
+		if got := findCalls(t, estimate, MethodSearchMessages); got.Calls != 3 || got.PerMinute != 20 {
+			t.Errorf("search calls = %+v, want 3 calls at 20/min", got)
+		}
+		// 投稿のあるチャンネル（最初のページの1チャンネル × 3ページ、参加しているチャンネル数が上限）
+		if got := findCalls(t, estimate, MethodConversationsHistory); got.Calls != 2 {
+			t.Errorf("history calls = %d, want 2", got.Calls)
+		}
+		// 250件 × スレッドの割合 10%
+		if got := findCalls(t, estimate, MethodConversationsReplies); got.Calls != 25 {
+			t.Errorf("replies calls = %d, want 25", got.Calls)
+		}
+	})
+
+	t.Run("全チャンネル横断方式", func(t *testing.T) {
+		sampler := &mockHistorySampler{samples: samples, searchErr: errors.New("not_allowed_token_type")}
+		estimate, err := NewEstimator(sampler, 0).EstimateUser(context.Background(), "U1", channels, nil)
+		if err != nil {
+			t.Fatalf("EstimateUser() error = %v", err)
+		}
+		// 参加している2チャンネル + 参加していないチャンネルの1回
+		if got := findCalls(t, estimate, MethodConversationsHistory); got.Calls != 3 {
+			t.Errorf("history calls = %d, want 3", got.Calls)
+		}
+		if estimate.Channels != 3 {
+			t.Errorf("Channels = %d, want 3", estimate.Channels)
+		}
+		for _, c := range estimate.Calls {
+			if c.Method == MethodSearchMessages {
+				t.Errorf("fallback estimate contains search.messages calls: %+v", c)
+			}
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// maxConcurrentChannels はチャンネルのメッセージを並行取得する最大数（レート制限を考慮）
+const maxConcurrentChannels = 10
+
+// ChannelAnalysis はチャンネルごとの分析結果を表す
+type ChannelAnalysis struct {
+	Channel *domain.Channel
+	Result  *AnalysisResult
+}
+
+// MultiChannelResult は複数チャンネルの分析結果を表す
+type MultiChannelResult struct {
+	Merged   *AnalysisResult   // 全チャンネルをまとめて集計した結果
+	Channels []ChannelAnalysis // チャンネルごとの結果（指定された順）
+	Failed   []*domain.Channel // 取得に失敗して分析から除外したチャンネル（指定された順）
+}
+
+// ChannelName はチャンネルIDに対応するチャンネル名を返す（見つからない場合はID）
+func (r *MultiChannelResult) ChannelName(channelID string) string {
+	for _, ch := range r.Channels {
+		if ch.Channel.ID == channelID {
+			return ch.Channel.Name
+		}
+	}
+	return channelID
+}
+
+// channelFetch はチャンネルごとのメッセージ取得結果
+type channelFetch struct {
+	messages []*domain.Message
+	warnings []string
+	err      error
+}
+
+// AnalyzeChannels は複数チャンネルのメッセージを並行取得し、合算結果とチャンネルごとの結果を返す
+// 取得に失敗したチャンネルは警告として記録して除外する（すべて失敗した場合はエラー）
+func (a *Analyzer) AnalyzeChannels(ctx context.Context, channels []*domain.Channel, dateRange *domain.DateRange) (*MultiChannelResult, error) {
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("分析対象のチャンネルがありません")
+	}
+
+	fmt.Fprintf(a.progress, "%dチャンネルのメッセージを取得中（最大%d並行）...\n", len(channels), maxConcurrentChannels)
+	fetches := make([]channelFetch, len(channels))
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	semaphore := make(chan struct{}, maxConcurrentChannels)
+	processed := 0
+
+	for i, channel := range channels {
+		wg.Add(1)
+		go func(i int, ch *domain.Channel) {
+			defer wg.Done()
+			semaphore <- struct{}{}        // セマフォを取得
+			defer func() { <-semaphore }() // セマフォを解放
+
+			messages, warnings, err := a.fetchChannelMessages(ctx, ch.ID, dateRange, false)
+			fetches[i] = channelFetch{messages: messages, warnings: warnings, err: err}
+
+			mu.Lock()
+			processed++
+			fmt.Fprintf(a.progress, "進捗: %d/%dチャンネル取得完了 (#%s: %d件)\n", processed, len(channels), ch.Name, len(messages))
+			mu.Unlock()
+		}(i, channel)
+	}
+	wg.Wait()
+
+	// 同じチャンネルが重複して指定された場合に備えて、重複を除いて全メッセージをまとめる
+	var (
+		allMessages []*domain.Message
+		warnings    []string
+		failed      []*domain.Channel
+		firstErr    error
+		analyses    = make([]ChannelAnalysis, 0, len(channels))
+		seen        = make(map[string]bool)
+	)
+	for i, channel := range channels {
+		fetch := fetches[i]
+		if fetch.err != nil {
+			if firstErr == nil {
+				firstErr = fetch.err
+			}
+			warnings = append(warnings, fmt.Sprintf("チャンネル #%s を分析から除外しました: %v", channel.Name, fetch.err))
+			failed = append(failed, channel)
+			continue
+		}
+		for _, warning := range fetch.warnings {
+			warnings = append(warnings, fmt.Sprintf("#%s: %s", channel.Name, warning))
+		}
+
+		channelMessages := make([]*domain.Message, 0, len(fetch.messages))
+		for _, msg := range fetch.messages {
+			key := threadKey(msg.ChannelID, msg.ID)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			channelMessages = append(channelMessages, msg)
+		}
+		allMessages = append(allMessages, channelMessages...)
+
+		result := a.aggregate(channelMessages)
+		result.Warnings = fetch.warnings
+		analyses = append(analyses, ChannelAnalysis{Channel: channel, Result: result})
+	}
+	if len(analyses) == 0 {
+		return nil, firstErr
+	}
+
+	// 合算結果はチャンネルごとの結果を足し合わせず、全メッセージから一度だけ集計する
+	fmt.Fprintf(a.progress, "分析結果を集計中... (合計: %dメッセージ)\n", len(allMessages))
+	merged := a.aggregate(allMessages)
+	merged.Warnings = warnings
+
+	// ユーザー情報は全チャンネル分をまとめて一度だけ取得する
+	users := a.findUsers(ctx, merged)
+	fmt.Fprintf(a.progress, "ユーザー情報取得完了\n")
+	merged.UserStats = a.buildUserStats(merged.UserMessageCount, users)
+	for _, analysis := range analyses {
+		analysis.Result.UserStats = a.buildUserStats(analysis.Result.UserMessageCount, users)
+	}
+	fmt.Fprintf(a.progress, "分析完了\n\n")
+
+	return &MultiChannelResult{
+		Merged:   merged,
+		Channels: analyses,
+		Failed:   failed,
+	}, nil
+}
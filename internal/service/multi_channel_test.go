@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestAnalyzer_AnalyzeChannels(t *testing.T) {
+	now := time.Now()
+	messages := []*domain.Message{
+		{ID: "1", Text: "C1のスレッド親", UserID: "U1", ChannelID: "C1", Timestamp: now, ThreadTS: "1",
+			Reactions: []domain.Reaction{{Name: "thumbsup", Count: 3}}},
+		{ID: "2", Text: "C1の返信", UserID: "U2", ChannelID: "C1", Timestamp: now, ThreadTS: "1"},
+		// C2のメッセージはC1と同じタイムスタンプを持つが別のメッセージ
+		{ID: "1", Text: "C2のメッセージ", UserID: "U1", ChannelID: "C2", Timestamp: now,
+			Reactions: []domain.Reaction{{Name: "thumbsup", Count: 2}, {Name: "eyes", Count: 1}}},
+	}
+	users := map[string]*domain.User{
+		"U1": {ID: "U1", Name: "User1"},
+		"U2": {ID: "U2", Name: "User2"},
+	}
+	channels := []*domain.Channel{
+		{ID: "C1", Name: "team-a"},
+		{ID: "C2", Name: "team-b"},
+		{ID: "C1", Name: "team-a"}, // 重複指定
+	}
+
+	analyzer := NewAnalyzer(&mockMessageRepository{messages: messages}, &mockUserRepository{users: users})
+	result, err := analyzer.AnalyzeChannels(context.Background(), channels, nil)
+	if err != nil {
+		t.Fatalf("AnalyzeChannels() error = %v", err)
+	}
+
+	// 合算結果は重複なく集計される
+	if got := result.Merged.TotalMessages(); got != 3 {
+		t.Errorf("Merged.TotalMessages() = %d, want 3", got)
+	}
+	if got := result.Merged.TotalReactions(); got != 6 {
+		t.Errorf("Merged.TotalReactions() = %d, want 6", got)
+	}
+	if len(result.Merged.EmojiStats) == 0 || result.Merged.EmojiStats[0].Emoji != "thumbsup" || result.Merged.EmojiStats[0].Count != 5 {
+		t.Errorf("Merged.EmojiStats = %+v, want thumbsup=5 first", result.Merged.EmojiStats)
+	}
+	if len(result.Merged.ThreadStats) != 1 || result.Merged.ThreadStats[0].ReplyCount != 1 {
+		t.Errorf("Merged.ThreadStats = %+v, want one thread with 1 reply", result.Merged.ThreadStats)
+	}
+	if len(result.Merged.UserStats) != 2 || result.Merged.UserStats[0].UserName != "User1" || result.Merged.UserStats[0].Count != 2 {
+		t.Errorf("Merged.UserStats = %+v, want User1=2 first", result.Merged.UserStats)
+	}
+
+	// チャンネルごとの結果
+	if len(result.Channels) != 3 {
+		t.Fatalf("len(Channels) = %d, want 3", len(result.Channels))
+	}
+	if got := result.Channels[0].Result.TotalMessages(); got != 2 {
+		t.Errorf("team-a TotalMessages() = %d, want 2", got)
+	}
+	if got := result.Channels[1].Result.TotalReactions(); got != 3 {
+		t.Errorf("team-b TotalReactions() = %d, want 3", got)
+	}
+	if got := result.Channels[2].Result.TotalMessages(); got != 0 {
+		t.Errorf("duplicated team-a TotalMessages() = %d, want 0", got)
+	}
+	if got := result.ChannelName("C2"); got != "team-b" {
+		t.Errorf("ChannelName(C2) = %q, want team-b", got)
+	}
+}
+
+// TestAnalyzer_BroadcastReply はチャンネルにも送信された返信を、単一・複数チャンネルの分析のどちらでも1件として数えることを確認する
+func TestAnalyzer_BroadcastReply(t *testing.T) {
+	now := time.Now()
+	parent := &domain.Message{ID: "1", UserID: "U1", ChannelID: "C1", Timestamp: now, ThreadTS: "1"}
+	broadcast := &domain.Message{ID: "2", UserID: "U2", ChannelID: "C1", Timestamp: now, ThreadTS: "1",
+		Reactions: []domain.Reaction{{Name: "eyes", Count: 2}}}
+	msgRepo := &mockMessageRepository{messages: []*domain.Message{parent, broadcast}, broadcasts: []*domain.Message{broadcast}}
+	analyzer := NewAnalyzer(msgRepo, &mockUserRepository{})
+
+	single, err := analyzer.AnalyzeChannel(context.Background(), "C1", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeChannel() error = %v", err)
+	}
+	multi, err := analyzer.AnalyzeChannels(context.Background(), []*domain.Channel{{ID: "C1", Name: "team-a"}}, nil)
+	if err != nil {
+		t.Fatalf("AnalyzeChannels() error = %v", err)
+	}
+	for name, result := range map[string]*AnalysisResult{"AnalyzeChannel": single, "AnalyzeChannels": multi.Merged} {
+		if got := result.TotalMessages(); got != 2 {
+			t.Errorf("%s TotalMessages() = %d, want 2", name, got)
+		}
+		if got := result.TotalReactions(); got != 2 {
+			t.Errorf("%s TotalReactions() = %d, want 2", name, got)
+		}
+		if len(result.ThreadStats) != 1 || result.ThreadStats[0].ReplyCount != 1 {
+			t.Errorf("%s ThreadStats = %+v, want one thread with 1 reply", name, result.ThreadStats)
+		}
+	}
+}
+
+func TestAnalyzer_AnalyzeChannels_Errors(t *testing.T) {
+	messages := []*domain.Message{
+		{ID: "1", UserID: "U1", ChannelID: "C1", Timestamp: time.Now()},
+	}
+	notInChannel := errors.New("not_in_channel")
+
+	t.Run("一部のチャンネルが失敗", func(t *testing.T) {
+		msgRepo := &mockMessageRepository{messages: messages, channelErrs: map[string]error{"C2": notInChannel}}
+		analyzer := NewAnalyzer(msgRepo, &mockUserRepository{})
+		result, err := analyzer.AnalyzeChannels(context.Background(), []*domain.Channel{{ID: "C1", Name: "a"}, {ID: "C2", Name: "b"}}, nil)
+		if err != nil {
+			t.Fatalf("AnalyzeChannels() error = %v", err)
+		}
+		if len(result.Channels) != 1 {
+			t.Errorf("len(Channels) = %d, want 1", len(result.Channels))
+		}
+		if len(result.Merged.Warnings) != 1 {
+			t.Errorf("Merged.Warnings = %v, want 1 warning", result.Merged.Warnings)
+		}
+		if len(result.Failed) != 1 || result.Failed[0].ID != "C2" {
+			t.Errorf("Failed = %+v, want C2", result.Failed)
+		}
+	})
+
+	t.Run("すべてのチャンネルが失敗", func(t *testing.T) {
+		msgRepo := &mockMessageRepository{messages: messages, channelErrs: map[string]error{"C1": notInChannel}}
+		analyzer := NewAnalyzer(msgRepo, &mockUserRepository{})
+		_, err := analyzer.AnalyzeChannels(context.Background(), []*domain.Channel{{ID: "C1", Name: "a"}}, nil)
+		if !errors.Is(err, notInChannel) {
+			t.Errorf("AnalyzeChannels() error = %v, want %v", err, notInChannel)
+		}
+	})
+
+	t.Run("チャンネル指定なし", func(t *testing.T) {
+		analyzer := NewAnalyzer(&mockMessageRepository{}, &mockUserRepository{})
+		if _, err := analyzer.AnalyzeChannels(context.Background(), nil, nil); err == nil {
+			t.Error("AnalyzeChannels() error = nil, want error")
+		}
+	})
+}
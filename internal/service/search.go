@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// AnalyzeSearch はSlackの検索クエリに一致するメッセージとリアクションを分析する
+// 検索に一致したスレッドの親メッセージは、チャンネル分析と同様にスレッドの返信も集計に含める
+func (a *Analyzer) AnalyzeSearch(ctx context.Context, query string, dateRange *domain.DateRange) (*AnalysisResult, error) {
+	fmt.Fprintf(a.progress, "メッセージを検索中... (%s)\n", query)
+	// 除外するチャンネルのメッセージは、リアクション情報を補完する前に取り除かれる
+	matches, warnings, err := a.messageRepo.FindBySearch(ctx, query, dateRange, a.excludedChannels)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(a.progress, "検索完了: %d件\n", len(matches))
+
+	messages, replyWarnings := a.appendThreadReplies(ctx, matches, dateRange)
+	warnings = append(warnings, replyWarnings...)
+
+	fmt.Fprintf(a.progress, "分析結果を集計中... (合計: %dメッセージ)\n", len(messages))
+	result := a.aggregate(messages)
+	result.Warnings = warnings
+
+	users := a.findUsers(ctx, result)
+	fmt.Fprintf(a.progress, "ユーザー情報取得完了\n")
+	result.UserStats = a.buildUserStats(result.UserMessageCount, users)
+	fmt.Fprintf(a.progress, "分析完了\n\n")
+
+	return result, nil
+}
+
+// appendThreadReplies は検索結果のスレッドの親メッセージについた返信を追加する
+// 検索に一致した返信は重複して追加しない。返信の取得に失敗した場合は警告として返し、処理は続行する
+func (a *Analyzer) appendThreadReplies(ctx context.Context, matches []*domain.Message, dateRange *domain.DateRange) ([]*domain.Message, []string) {
+	seen := make(map[string]bool, len(matches))
+	for _, msg := range matches {
+		seen[threadKey(msg.ChannelID, msg.ID)] = true
+	}
+
+	messages := matches
+	var warnings []string
+	threads := countThreads(matches)
+	threadCount := 0
+	for _, msg := range matches {
+		if !msg.IsThreadParent() {
+			continue
+		}
+		threadCount++
+		if threadCount%10 == 0 || threadCount == threads {
+			fmt.Fprintf(a.progress, "スレッドを処理中... (%d/%dスレッド)\n", threadCount, threads)
+		}
+		replies, err := a.messageRepo.FindThreadReplies(ctx, msg.ChannelID, msg.ThreadTS, dateRange)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("スレッド %s の返信取得に失敗しました: %v", msg.ThreadTS, err))
+			continue
+		}
+		for _, reply := range replies {
+			key := threadKey(reply.ChannelID, reply.ID)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			messages = append(messages, reply)
+		}
+	}
+	return messages, warnings
+}
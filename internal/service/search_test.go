@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestAnalyzer_AnalyzeSearch(t *testing.T) {
+	now := time.Now()
+	messages := []*domain.Message{
+		{ID: "1", Text: "release v1", UserID: "U1", ChannelID: "C1", Timestamp: now, ThreadTS: "1",
+			Reactions: []domain.Reaction{{Name: "tada", Count: 3}}},
+		{ID: "2", Text: "おめでとう", UserID: "U2", ChannelID: "C1", Timestamp: now, ThreadTS: "1"},
+		{ID: "3", Text: "release notes", UserID: "U3", ChannelID: "C1", Timestamp: now, ThreadTS: "1",
+			Reactions: []domain.Reaction{{Name: "eyes", Count: 1}}},
+		{ID: "4", Text: "release v2", UserID: "U2", ChannelID: "C2", Timestamp: now,
+			Reactions: []domain.Reaction{{Name: "tada", Count: 2}}},
+		{ID: "5", Text: "無関係", UserID: "U4", ChannelID: "C2", Timestamp: now,
+			Reactions: []domain.Reaction{{Name: "smile", Count: 5}}},
+	}
+	analyzer := NewAnalyzer(&mockMessageRepository{messages: messages}, &mockUserRepository{})
+	analyzer.SetProgressOutput(io.Discard)
+
+	result, err := analyzer.AnalyzeSearch(context.Background(), "release", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeSearch() error = %v", err)
+	}
+
+	// 検索に一致した3件と、一致したスレッドの返信1件（一致した返信は重複させない）
+	if got := result.TotalMessages(); got != 4 {
+		t.Errorf("TotalMessages() = %d, want 4", got)
+	}
+	if got := result.TotalReactions(); got != 6 {
+		t.Errorf("TotalReactions() = %d, want 6", got)
+	}
+	if len(result.EmojiStats) == 0 || result.EmojiStats[0].Emoji != "tada" || result.EmojiStats[0].Count != 5 {
+		t.Errorf("EmojiStats = %+v, want tada:5 first", result.EmojiStats)
+	}
+	if len(result.ThreadStats) != 1 || result.ThreadStats[0].ReplyCount != 2 {
+		t.Errorf("ThreadStats = %+v, want 1 thread with 2 replies", result.ThreadStats)
+	}
+	if len(result.UserStats) != 3 {
+		t.Errorf("UserStats length = %d, want 3", len(result.UserStats))
+	}
+}
+
+// TestAnalyzer_ExcludeChannels は除外したチャンネルのメッセージをユーザー分析と検索結果の分析に含めないことを確認する
+func TestAnalyzer_ExcludeChannels(t *testing.T) {
+	now := time.Now()
+	messages := []*domain.Message{
+		{ID: "1", Text: "release v1", UserID: "U1", ChannelID: "C1", Timestamp: now,
+			Reactions: []domain.Reaction{{Name: "tada", Count: 3}}},
+		{ID: "2", Text: "release v2", UserID: "U1", ChannelID: "C2", Timestamp: now,
+			Reactions: []domain.Reaction{{Name: "eyes", Count: 1}}},
+	}
+	users := map[string]*domain.User{"U1": {ID: "U1", Name: "User1"}}
+	analyzer := NewAnalyzer(&mockMessageRepository{messages: messages}, &mockUserRepository{users: users})
+	analyzer.SetProgressOutput(io.Discard)
+	analyzer.ExcludeChannels([]string{"C2"})
+
+	result, err := analyzer.AnalyzeSearch(context.Background(), "release", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeSearch() error = %v", err)
+	}
+	if got := result.TotalReactions(); got != 3 {
+		t.Errorf("search TotalReactions() = %d, want 3", got)
+	}
+
+	userResult, err := analyzer.AnalyzeUser(context.Background(), "User1", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeUser() error = %v", err)
+	}
+	if userResult.TotalMessages != 1 || userResult.TotalReactions != 3 {
+		t.Errorf("user TotalMessages = %d, TotalReactions = %d, want 1, 3", userResult.TotalMessages, userResult.TotalReactions)
+	}
+}
+
+// TestAnalyzer_AnalyzeSearch_FetchWarnings は検索結果の補完に失敗したチャンネルの警告を分析結果に含めることを確認する
+func TestAnalyzer_AnalyzeSearch_FetchWarnings(t *testing.T) {
+	messages := []*domain.Message{
+		{ID: "1", Text: "release v1", UserID: "U1", ChannelID: "C1", Timestamp: time.Now()},
+	}
+	msgRepo := &mockMessageRepository{messages: messages, searchWarns: []string{"チャンネル C1 の履歴を取得できませんでした"}}
+	analyzer := NewAnalyzer(msgRepo, &mockUserRepository{})
+	analyzer.SetProgressOutput(io.Discard)
+
+	result, err := analyzer.AnalyzeSearch(context.Background(), "release", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeSearch() error = %v", err)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0] != "チャンネル C1 の履歴を取得できませんでした" {
+		t.Errorf("Warnings = %v, want the C1 warning", result.Warnings)
+	}
+}
+
+func TestAnalyzer_AnalyzeSearch_Error(t *testing.T) {
+	searchErr := errors.New("missing_scope")
+	analyzer := NewAnalyzer(&mockMessageRepository{err: searchErr}, &mockUserRepository{})
+	analyzer.SetProgressOutput(io.Discard)
+
+	if _, err := analyzer.AnalyzeSearch(context.Background(), "release", nil); !errors.Is(err, searchErr) {
+		t.Errorf("AnalyzeSearch() error = %v, want %v", err, searchErr)
+	}
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// ReplyPeriod は期間ごとのスレッドの返信数
+type ReplyPeriod struct {
+	Start     time.Time // 期間の開始日時
+	Replies   int       // 返信数
+	Reactions int       // 返信についたリアクション数
+}
+
+// ThreadAnalysisResult は1つのスレッドの分析結果を表す
+type ThreadAnalysisResult struct {
+	Parent       *domain.Message
+	ParentUser   string                   // 親メッセージの投稿者の表示名
+	Replies      []*domain.Message        // 返信（投稿日時順、ボット・除外ユーザーを除く）
+	Participants []domain.UserStats       // 参加者ごとの返信数（親メッセージの投稿者は返信がなくても含む）
+	EmojiStats   []domain.EmojiCount      // 全返信についたスタンプ
+	Granularity  Granularity              // 推移を集計した期間の単位
+	Timeline     []ReplyPeriod            // 親メッセージの投稿から最後の返信までの推移（返信のない期間も含む）
+	TopReplies   []domain.MessageReaction // リアクションが多い返信
+	Warnings     []string                 // 処理は続行したが一部のデータを取得できなかった場合の警告
+}
+
+// TotalReactions は全返信についたリアクションの総数を返す
+func (r *ThreadAnalysisResult) TotalReactions() int {
+	total := 0
+	for _, stat := range r.EmojiStats {
+		total += stat.Count
+	}
+	return total
+}
+
+// Duration は親メッセージの投稿から最後の返信までの時間を返す（返信がない場合は0）
+func (r *ThreadAnalysisResult) Duration() time.Duration {
+	if len(r.Replies) == 0 {
+		return 0
+	}
+	return r.Replies[len(r.Replies)-1].Timestamp.Sub(r.Parent.Timestamp)
+}
+
+// AnalyzeThread は1つのスレッドの親メッセージと返信を分析する
+// threadTS にはスレッドの親メッセージのタイムスタンプを指定する
+func (a *Analyzer) AnalyzeThread(ctx context.Context, channelID, threadTS string, dateRange *domain.DateRange) (*ThreadAnalysisResult, error) {
+	fmt.Fprintf(a.progress, "スレッドを取得中...\n")
+	parent, replies, err := a.messageRepo.FindThread(ctx, channelID, threadTS, dateRange)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(a.progress, "スレッド取得完了: 返信%d件\n", len(replies))
+
+	result := &ThreadAnalysisResult{Parent: parent}
+	for _, reply := range replies {
+		if reply.IsBot || a.excludedUsers[reply.UserID] {
+			continue
+		}
+		result.Replies = append(result.Replies, reply)
+	}
+	sort.SliceStable(result.Replies, func(i, j int) bool {
+		return result.Replies[i].Timestamp.Before(result.Replies[j].Timestamp)
+	})
+
+	replyCount := make(map[string]int)
+	if parent.UserID != "" {
+		replyCount[parent.UserID] = 0
+	}
+	emojiCount := make(map[string]int)
+	for _, reply := range result.Replies {
+		if reply.UserID != "" {
+			replyCount[reply.UserID]++
+		}
+		for _, reaction := range reply.Reactions {
+			emojiCount[reaction.Name] += reaction.Count
+		}
+		if total := reply.TotalReactionCount(); total > 0 {
+			result.TopReplies = append(result.TopReplies, domain.MessageReaction{
+				Text:      reply.Text,
+				Reactions: total,
+				Timestamp: reply.Timestamp,
+				UserID:    reply.UserID,
+				ChannelID: reply.ChannelID,
+				MessageID: reply.ID,
+			})
+		}
+	}
+
+	for emoji, count := range emojiCount {
+		result.EmojiStats = append(result.EmojiStats, domain.EmojiCount{Emoji: emoji, Count: count})
+	}
+	sort.Slice(result.EmojiStats, func(i, j int) bool {
+		if result.EmojiStats[i].Count != result.EmojiStats[j].Count {
+			return result.EmojiStats[i].Count > result.EmojiStats[j].Count
+		}
+		return result.EmojiStats[i].Emoji < result.EmojiStats[j].Emoji
+	})
+	// 同数の場合は先に投稿された返信を上位にする
+	sort.SliceStable(result.TopReplies, func(i, j int) bool {
+		return result.TopReplies[i].Reactions > result.TopReplies[j].Reactions
+	})
+
+	userIDs := make([]string, 0, len(replyCount))
+	for userID := range replyCount {
+		userIDs = append(userIDs, userID)
+	}
+	fmt.Fprintf(a.progress, "ユーザー情報を取得中... (%dユーザー)\n", len(userIDs))
+	users, err := a.userRepo.FindByIDs(ctx, userIDs)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("ユーザー情報の取得に失敗しました: %v", err))
+		users = make(map[string]*domain.User)
+	}
+	result.Participants = a.buildUserStats(replyCount, users)
+	sort.SliceStable(result.Participants, func(i, j int) bool {
+		if result.Participants[i].Count != result.Participants[j].Count {
+			return result.Participants[i].Count > result.Participants[j].Count
+		}
+		return result.Participants[i].UserName < result.Participants[j].UserName
+	})
+	result.ParentUser = parent.UserID
+	if user := users[parent.UserID]; user != nil {
+		result.ParentUser = user.GetDisplayName()
+	} else if parent.IsBot && parent.UserID == "" {
+		result.ParentUser = "ボット"
+	}
+
+	result.Granularity, result.Timeline = replyTimeline(parent, result.Replies)
+	fmt.Fprintf(a.progress, "分析完了\n\n")
+	return result, nil
+}
+
+// replyTimeline は返信を投稿日時で期間ごとに集計する
+// 期間の単位は親メッセージの投稿から最後の返信までの長さから決める（2日以内は時間別）
+func replyTimeline(parent *domain.Message, replies []*domain.Message) (Granularity, []ReplyPeriod) {
+	if len(replies) == 0 {
+		return GranularityHour, nil
+	}
+	start := parent.Timestamp
+	end := replies[len(replies)-1].Timestamp
+	if end.Before(start) {
+		start = replies[0].Timestamp
+	}
+
+	granularity := GranularityHour
+	if span := end.Sub(start); span > maxHourlySpan {
+		granularity = granularityFor(span)
+	}
+
+	return granularity, timeline(replies, start, end, granularity,
+		func(start time.Time) ReplyPeriod { return ReplyPeriod{Start: start} },
+		func(period *ReplyPeriod, reply *domain.Message) {
+			period.Replies++
+			period.Reactions += reply.TotalReactionCount()
+		})
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestAnalyzer_AnalyzeThread(t *testing.T) {
+	base := time.Date(2024, 1, 10, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return base.Add(time.Duration(minutes) * time.Minute)
+	}
+	messages := []*domain.Message{
+		{ID: "100.0", Text: "障害が発生しています", UserID: "U1", ChannelID: "C1", Timestamp: at(0), ThreadTS: "100.0",
+			Reactions: []domain.Reaction{{Name: "eyes", Count: 4}}},
+		{ID: "100.3", Text: "復旧しました", UserID: "U2", ChannelID: "C1", Timestamp: at(150), ThreadTS: "100.0",
+			Reactions: []domain.Reaction{{Name: "tada", Count: 5}, {Name: "pray", Count: 2}}},
+		{ID: "100.1", Text: "調査します", UserID: "U2", ChannelID: "C1", Timestamp: at(5), ThreadTS: "100.0",
+			Reactions: []domain.Reaction{{Name: "pray", Count: 1}}},
+		{ID: "100.2", Text: "ログを確認しました", UserID: "U3", ChannelID: "C1", Timestamp: at(30), ThreadTS: "100.0"},
+		{ID: "100.4", Text: "除外ユーザーの返信", UserID: "U9", ChannelID: "C1", Timestamp: at(160), ThreadTS: "100.0",
+			Reactions: []domain.Reaction{{Name: "tada", Count: 10}}},
+		// 別のスレッド
+		{ID: "200.1", Text: "別スレッドの返信", UserID: "U3", ChannelID: "C1", Timestamp: at(10), ThreadTS: "200.0"},
+	}
+	users := map[string]*domain.User{
+		"U1": {ID: "U1", Name: "User1"},
+		"U2": {ID: "U2", Name: "User2"},
+		"U3": {ID: "U3", Name: "User3"},
+	}
+
+	analyzer := NewAnalyzer(&mockMessageRepository{messages: messages}, &mockUserRepository{users: users})
+	analyzer.SetProgressOutput(io.Discard)
+	analyzer.ExcludeUsers([]string{"U9"})
+	result, err := analyzer.AnalyzeThread(context.Background(), "C1", "100.0", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeThread() error = %v", err)
+	}
+
+	if result.Parent.Text != "障害が発生しています" || result.ParentUser != "User1" {
+		t.Errorf("Parent = %q (%s), want the parent message by User1", result.Parent.Text, result.ParentUser)
+	}
+
+	// 返信は投稿日時順（除外ユーザーを除く）
+	wantReplies := []string{"100.1", "100.2", "100.3"}
+	if len(result.Replies) != len(wantReplies) {
+		t.Fatalf("len(Replies) = %d, want %d", len(result.Replies), len(wantReplies))
+	}
+	for i, id := range wantReplies {
+		if result.Replies[i].ID != id {
+			t.Errorf("Replies[%d].ID = %s, want %s", i, result.Replies[i].ID, id)
+		}
+	}
+	if got := result.Duration(); got != 150*time.Minute {
+		t.Errorf("Duration() = %v, want 2h30m", got)
+	}
+
+	// 参加者は返信数の多い順で、返信のない親メッセージの投稿者も含む
+	wantParticipants := []domain.UserStats{
+		{UserID: "U2", UserName: "User2", Count: 2},
+		{UserID: "U3", UserName: "User3", Count: 1},
+		{UserID: "U1", UserName: "User1", Count: 0},
+	}
+	if len(result.Participants) != len(wantParticipants) {
+		t.Fatalf("Participants = %+v, want %+v", result.Participants, wantParticipants)
+	}
+	for i, want := range wantParticipants {
+		if result.Participants[i] != want {
+			t.Errorf("Participants[%d] = %+v, want %+v", i, result.Participants[i], want)
+		}
+	}
+
+	// スタンプは返信についたもののみ（親メッセージの :eyes: は含まない）
+	if result.TotalReactions() != 8 || len(result.EmojiStats) != 2 || result.EmojiStats[0].Emoji != "tada" || result.EmojiStats[1].Count != 3 {
+		t.Errorf("EmojiStats = %+v, want tada=5, pray=3", result.EmojiStats)
+	}
+	if len(result.TopReplies) != 2 || result.TopReplies[0].Text != "復旧しました" || result.TopReplies[0].Reactions != 7 {
+		t.Errorf("TopReplies = %+v, want 復旧しました=7 first", result.TopReplies)
+	}
+
+	// 2日以内のスレッドは時間別（09時台〜11時台）
+	if result.Granularity != GranularityHour {
+		t.Errorf("Granularity = %v, want %v", result.Granularity, GranularityHour)
+	}
+	wantTimeline := []int{2, 0, 1}
+	if len(result.Timeline) != len(wantTimeline) {
+		t.Fatalf("Timeline = %+v, want %d hours", result.Timeline, len(wantTimeline))
+	}
+	for i, want := range wantTimeline {
+		if result.Timeline[i].Replies != want {
+			t.Errorf("Timeline[%d].Replies = %d, want %d", i, result.Timeline[i].Replies, want)
+		}
+	}
+}
+
+func TestAnalyzer_AnalyzeThread_NotFound(t *testing.T) {
+	analyzer := NewAnalyzer(&mockMessageRepository{}, &mockUserRepository{})
+	analyzer.SetProgressOutput(io.Discard)
+
+	_, err := analyzer.AnalyzeThread(context.Background(), "C1", "100.0", nil)
+	var notFound *domain.NotFoundError
+	if !errors.As(err, &notFound) {
+		t.Errorf("AnalyzeThread() error = %v, want NotFoundError", err)
+	}
+}
+
+func TestAnalyzer_AnalyzeThread_BotParent(t *testing.T) {
+	messages := []*domain.Message{
+		{ID: "100.0", Text: "デプロイしました", ChannelID: "C1", IsBot: true, ThreadTS: "100.0"},
+		{ID: "100.1", Text: "確認しました", UserID: "U1", ChannelID: "C1", ThreadTS: "100.0"},
+	}
+	analyzer := NewAnalyzer(&mockMessageRepository{messages: messages}, &mockUserRepository{users: map[string]*domain.User{"U1": {ID: "U1", Name: "User1"}}})
+	analyzer.SetProgressOutput(io.Discard)
+
+	result, err := analyzer.AnalyzeThread(context.Background(), "C1", "100.0", nil)
+	if err != nil {
+		t.Fatalf("AnalyzeThread() error = %v", err)
+	}
+	if result.ParentUser != "ボット" || len(result.Replies) != 1 {
+		t.Errorf("ParentUser = %q, Replies = %d, want ボット and 1 reply", result.ParentUser, len(result.Replies))
+	}
+}
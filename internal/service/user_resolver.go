@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// ResolveUserIDs はユーザー名・表示名・実名またはユーザーIDのリストをユーザーIDに解決する
+// ユーザー一覧は一度だけ取得し、いずれにも一致しない名前がある場合はエラーを返す
+func ResolveUserIDs(ctx context.Context, userRepo domain.UserRepository, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	users, err := userRepo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, 0, len(names))
+	for _, name := range names {
+		user := findUser(users, name)
+		if user == nil {
+			return nil, &domain.NotFoundError{Kind: "ユーザー", Name: name}
+		}
+		userIDs = append(userIDs, user.ID)
+	}
+	return userIDs, nil
+}
+
+// findUser はIDまたは名前・表示名・実名（大文字小文字を区別しない）が一致するユーザーを返す
+func findUser(users map[string]*domain.User, name string) *domain.User {
+	if user, ok := users[name]; ok {
+		return user
+	}
+	for _, user := range users {
+		if strings.EqualFold(user.Name, name) ||
+			strings.EqualFold(user.DisplayName, name) ||
+			strings.EqualFold(user.RealName, name) {
+			return user
+		}
+	}
+	return nil
+}
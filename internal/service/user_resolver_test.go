@@ -0,0 +1,53 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestResolveUserIDs(t *testing.T) {
+	repo := &mockUserRepository{
+		users: map[string]*domain.User{
+			"U1": {ID: "U1", Name: "deploy-bot"},
+			"U2": {ID: "U2", Name: "taro", DisplayName: "田中太郎"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		names    []string
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "名前・表示名・IDで指定",
+			names:    []string{"Deploy-Bot", "田中太郎", "U2"},
+			expected: []string{"U1", "U2", "U2"},
+		},
+		{
+			name:     "指定なし",
+			names:    nil,
+			expected: nil,
+		},
+		{
+			name:    "存在しないユーザー",
+			names:   []string{"unknown"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveUserIDs(context.Background(), repo, tt.names)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveUserIDs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ResolveUserIDs() = %v, want %v", got, tt.expected)
+			}
+		})
+	}
+}
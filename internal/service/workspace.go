@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// ChannelActivity はチャンネルごとの活動量を表す
+type ChannelActivity struct {
+	Channel   *domain.Channel
+	Messages  int
+	Reactions int
+}
+
+// WorkspaceChannels はワークスペースのチャンネルのうち、トークンのユーザーが参加しているものを返す
+// 参加していないチャンネルは履歴を取得できないため除外し、その数を返す
+func WorkspaceChannels(ctx context.Context, channelRepo domain.ChannelRepository) ([]*domain.Channel, int, error) {
+	allChannels, err := channelRepo.FindAll(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	channels := make([]*domain.Channel, 0, len(allChannels))
+	for _, channel := range allChannels {
+		if channel.IsMember {
+			channels = append(channels, channel)
+		}
+	}
+	if len(channels) == 0 {
+		return nil, len(allChannels), fmt.Errorf("参加しているチャンネルがありません（全%dチャンネル）", len(allChannels))
+	}
+
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Name < channels[j].Name
+	})
+	return channels, len(allChannels) - len(channels), nil
+}
+
+// ChannelRanking はチャンネルを投稿数の多い順に並べた活動量ランキングを返す
+func (r *MultiChannelResult) ChannelRanking() []ChannelActivity {
+	ranking := make([]ChannelActivity, 0, len(r.Channels))
+	for _, ch := range r.Channels {
+		ranking = append(ranking, ChannelActivity{
+			Channel:   ch.Channel,
+			Messages:  ch.Result.TotalMessages(),
+			Reactions: ch.Result.TotalReactions(),
+		})
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		return ranking[i].Messages > ranking[j].Messages
+	})
+	return ranking
+}
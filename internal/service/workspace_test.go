@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func TestWorkspaceChannels(t *testing.T) {
+	repo := &mockChannelRepository{
+		channels: []*domain.Channel{
+			{ID: "C1", Name: "random", IsMember: true},
+			{ID: "C2", Name: "announce", IsMember: false},
+			{ID: "C3", Name: "general", IsMember: true},
+		},
+	}
+
+	channels, skipped, err := WorkspaceChannels(context.Background(), repo)
+	if err != nil {
+		t.Fatalf("WorkspaceChannels() error = %v", err)
+	}
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1", skipped)
+	}
+	if len(channels) != 2 || channels[0].Name != "general" || channels[1].Name != "random" {
+		t.Errorf("WorkspaceChannels() = %+v, want [general random]", channels)
+	}
+
+	// 参加しているチャンネルがない場合はエラー
+	repo = &mockChannelRepository{channels: []*domain.Channel{{ID: "C1", Name: "random"}}}
+	if _, _, err := WorkspaceChannels(context.Background(), repo); err == nil {
+		t.Error("WorkspaceChannels() error = nil, want error")
+	}
+}
+
+func TestMultiChannelResult_ChannelRanking(t *testing.T) {
+	result := &MultiChannelResult{
+		Channels: []ChannelAnalysis{
+			{Channel: &domain.Channel{ID: "C1", Name: "quiet"}, Result: &AnalysisResult{UserMessageCount: map[string]int{"U1": 1}}},
+			{Channel: &domain.Channel{ID: "C2", Name: "busy"}, Result: &AnalysisResult{
+				UserMessageCount: map[string]int{"U1": 3, "U2": 2},
+				EmojiStats:       []domain.EmojiCount{{Emoji: "tada", Count: 4}},
+			}},
+		},
+	}
+
+	ranking := result.ChannelRanking()
+	if len(ranking) != 2 {
+		t.Fatalf("len(ChannelRanking()) = %d, want 2", len(ranking))
+	}
+	if ranking[0].Channel.Name != "busy" || ranking[0].Messages != 5 || ranking[0].Reactions != 4 {
+		t.Errorf("ranking[0] = %+v, want busy with 5 messages and 4 reactions", ranking[0])
+	}
+}
@@ -0,0 +1,100 @@
+package slashcommand
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+// Usage はスラッシュコマンドの使い方
+const Usage = "使い方: `/reactions [#チャンネル | @ユーザー] [last-30d | YYYY-MM-DD..YYYY-MM-DD] [public]`\n" +
+	"• 対象を省略するとコマンドを実行したチャンネルを分析します\n" +
+	"• 期間は `last-7d`（日）、`last-2w`（週）、`last-1m`（月）または `2024-01-01..2024-01-31` で指定します（省略時は全期間）\n" +
+	"• `public` を指定すると結果をチャンネルの全員に表示します"
+
+var (
+	// channelMention はSlackがエスケープしたチャンネル参照（<#C123|general> または <#C123>）
+	channelMention = regexp.MustCompile(`^<#([A-Z0-9]+)(?:\|([^>]*))?>$`)
+	// userMention はSlackがエスケープしたユーザー参照（<@U123|taro> または <@U123>）
+	userMention = regexp.MustCompile(`^<@([A-Z0-9]+)(?:\|([^>]*))?>$`)
+)
+
+// Args はスラッシュコマンドの引数を解析した結果
+type Args struct {
+	ChannelID   string // チャンネル参照で指定された場合のチャンネルID
+	ChannelName string // チャンネル名（#general 形式で指定された場合など）
+	UserID      string // ユーザー参照で指定された場合のユーザーID
+	UserName    string // ユーザー名（@taro 形式で指定された場合など）
+	DateRange   *domain.DateRange
+	Public      bool // 結果をチャンネルの全員に表示する
+	Help        bool // 使い方の表示
+}
+
+// HasChannel はチャンネルが指定されたかどうかを返す
+func (a *Args) HasChannel() bool {
+	return a.ChannelID != "" || a.ChannelName != ""
+}
+
+// HasUser はユーザーが指定されたかどうかを返す
+func (a *Args) HasUser() bool {
+	return a.UserID != "" || a.UserName != ""
+}
+
+// ParseArgs はスラッシュコマンドのテキストを解析する
+// 相対期間は now を基準とし、日付は now のタイムゾーンで解釈する
+func ParseArgs(text string, now time.Time) (*Args, error) {
+	args := &Args{DateRange: &domain.DateRange{}}
+	hasPeriod := false
+	setPeriod := func(dateRange *domain.DateRange, err error) error {
+		if err != nil {
+			return err
+		}
+		if hasPeriod {
+			return fmt.Errorf("期間は1つだけ指定してください")
+		}
+		hasPeriod = true
+		args.DateRange = dateRange
+		return nil
+	}
+
+	for _, token := range strings.Fields(text) {
+		var err error
+		switch m1, m2 := channelMention.FindStringSubmatch(token), userMention.FindStringSubmatch(token); {
+		case token == "help":
+			args.Help = true
+		case token == "public":
+			args.Public = true
+		case m1 != nil:
+			err = args.setTarget(func() { args.ChannelID, args.ChannelName = m1[1], m1[2] })
+		case m2 != nil:
+			err = args.setTarget(func() { args.UserID, args.UserName = m2[1], m2[2] })
+		case strings.HasPrefix(token, "#") && len(token) > 1:
+			err = args.setTarget(func() { args.ChannelName = token[1:] })
+		case strings.HasPrefix(token, "@") && len(token) > 1:
+			err = args.setTarget(func() { args.UserName = token[1:] })
+		case strings.HasPrefix(token, "last-"):
+			err = setPeriod(domain.ParseRelativeDateRange(strings.TrimPrefix(token, "last-"), now))
+		case strings.Contains(token, ".."):
+			start, end, _ := strings.Cut(token, "..")
+			err = setPeriod(domain.ParseDateRange(start, end, now.Location()))
+		default:
+			err = fmt.Errorf("解釈できない引数です: %s", token)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return args, nil
+}
+
+// setTarget は分析対象を設定する（チャンネルとユーザーは合わせて1つまで）
+func (a *Args) setTarget(set func()) error {
+	if a.HasChannel() || a.HasUser() {
+		return fmt.Errorf("分析対象のチャンネルまたはユーザーは1つだけ指定してください")
+	}
+	set()
+	return nil
+}
@@ -0,0 +1,98 @@
+package slashcommand
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseArgs(t *testing.T) {
+	now := time.Date(2024, 3, 31, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		text      string
+		want      Args
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			name: "引数なし",
+			text: "",
+		},
+		{
+			name:      "エスケープされたチャンネルと相対期間",
+			text:      "<#C0123|general> last-30d",
+			want:      Args{ChannelID: "C0123", ChannelName: "general"},
+			wantStart: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC),
+			wantEnd:   now,
+		},
+		{
+			name: "チャンネル名",
+			text: "#random public",
+			want: Args{ChannelName: "random", Public: true},
+		},
+		{
+			name: "エスケープされたユーザー（名前なし）",
+			text: "<@U0123>",
+			want: Args{UserID: "U0123"},
+		},
+		{
+			name:      "ユーザー名と日付範囲",
+			text:      "@taro 2024-01-01..2024-01-31",
+			want:      Args{UserName: "taro"},
+			wantStart: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 1, 31, 23, 59, 59, 999999000, time.UTC),
+		},
+		{
+			name:      "開始日のみ",
+			text:      "2024-03-01..",
+			wantStart: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "ヘルプ",
+			text: "help",
+			want: Args{Help: true},
+		},
+		{
+			name:    "対象を複数指定",
+			text:    "#general @taro",
+			wantErr: true,
+		},
+		{
+			name:    "期間を複数指定",
+			text:    "last-7d last-30d",
+			wantErr: true,
+		},
+		{
+			name:    "期間の単位が不正",
+			text:    "last-30y",
+			wantErr: true,
+		},
+		{
+			name:    "解釈できない引数",
+			text:    "general",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseArgs(tt.text, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseArgs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.ChannelID != tt.want.ChannelID || got.ChannelName != tt.want.ChannelName ||
+				got.UserID != tt.want.UserID || got.UserName != tt.want.UserName ||
+				got.Public != tt.want.Public || got.Help != tt.want.Help {
+				t.Errorf("ParseArgs() = %+v, want %+v", got, tt.want)
+			}
+			if !got.DateRange.Start.Equal(tt.wantStart) || !got.DateRange.End.Equal(tt.wantEnd) {
+				t.Errorf("DateRange = %v 〜 %v, want %v 〜 %v", got.DateRange.Start, got.DateRange.End, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
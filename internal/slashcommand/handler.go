@@ -0,0 +1,233 @@
+// Package slashcommand はSlackのスラッシュコマンドから分析を実行するHTTPハンドラーを提供する
+package slashcommand
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/report"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/slack-go/slack"
+)
+
+// maxBodyBytes はリクエストボディの最大サイズ
+const maxBodyBytes = 1 << 20
+
+// analysisTimeout はバックグラウンドで実行する分析の最大時間（response_url の有効期限は30分）
+const analysisTimeout = 25 * time.Minute
+
+// maxConcurrentAnalyses は同時に実行する分析の最大数（全員が1つのトークンのレート制限を共有するため）
+const maxConcurrentAnalyses = 3
+
+// busyMessage は実行中の分析が上限に達している場合の応答
+const busyMessage = "実行中の分析が多いため、受け付けられませんでした。しばらくしてから再度実行してください。"
+
+// ChannelRepository はスラッシュコマンドで使用するチャンネルのリポジトリ
+// トークンのユーザーの権限で分析するため、実行したユーザーが参加しているかを確認できる必要がある
+type ChannelRepository interface {
+	domain.ChannelRepository
+	IsMember(ctx context.Context, channelID, userID string) (bool, error)
+}
+
+// Handler はスラッシュコマンドのリクエストを検証し、分析結果を response_url に返信する
+type Handler struct {
+	signingSecret string
+	analyzer      *service.Analyzer
+	channelRepo   ChannelRepository
+	httpClient    *http.Client
+	log           io.Writer       // 返信の送信に失敗した場合のログの出力先
+	ctx           context.Context // バックグラウンドの分析に使用するコンテキスト
+	now           func() time.Time
+	semaphore     chan struct{} // 実行中の分析の数を制限する
+	wg            sync.WaitGroup
+}
+
+// NewHandler はスラッシュコマンドのハンドラーを作成する
+// 分析はリクエストとは独立して実行され、ctx がキャンセルされると中断される
+func NewHandler(ctx context.Context, signingSecret string, analyzer *service.Analyzer, channelRepo ChannelRepository) *Handler {
+	return &Handler{
+		signingSecret: signingSecret,
+		analyzer:      analyzer,
+		channelRepo:   channelRepo,
+		httpClient:    &http.Client{Timeout: 30 * time.Second},
+		log:           os.Stderr,
+		ctx:           ctx,
+		now:           time.Now,
+		semaphore:     make(chan struct{}, maxConcurrentAnalyses),
+	}
+}
+
+// SetLogOutput はログの出力先を設定する
+func (h *Handler) SetLogOutput(w io.Writer) {
+	h.log = w
+}
+
+// Wait は実行中の分析がすべて終了するまで待つ
+func (h *Handler) Wait() {
+	h.wg.Wait()
+}
+
+// ServeHTTP はリクエストの署名を検証し、分析をバックグラウンドで開始して即座に応答する
+// Slackは3秒以内の応答を求めるため、分析結果は response_url に送信する
+// 実行中の分析が上限に達している場合は、分析を開始せずに実行したユーザーにのみ伝える
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	if err != nil {
+		http.Error(w, "リクエストの読み込みに失敗しました", http.StatusBadRequest)
+		return
+	}
+	if err := h.verify(r.Header, body); err != nil {
+		http.Error(w, "署名の検証に失敗しました", http.StatusUnauthorized)
+		return
+	}
+
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	cmd, err := slack.SlashCommandParse(r)
+	if err != nil {
+		http.Error(w, "リクエストの解析に失敗しました", http.StatusBadRequest)
+		return
+	}
+
+	args, err := ParseArgs(cmd.Text, h.now())
+	if err != nil {
+		writeMessage(w, ephemeral(fmt.Sprintf("%v\n%s", err, Usage)))
+		return
+	}
+	if args.Help {
+		writeMessage(w, ephemeral(Usage))
+		return
+	}
+	if !args.HasChannel() && !args.HasUser() {
+		// 対象が指定されていない場合はコマンドを実行したチャンネルを分析する
+		args.ChannelID, args.ChannelName = cmd.ChannelID, cmd.ChannelName
+	}
+
+	select {
+	case h.semaphore <- struct{}{}:
+	default:
+		writeMessage(w, ephemeral(busyMessage))
+		return
+	}
+	writeMessage(w, ephemeral(fmt.Sprintf("%s の分析を開始しました（期間: %s）。結果はしばらくしてから表示されます。", targetLabel(args), report.FormatDateRange(args.DateRange))))
+
+	h.wg.Add(1)
+	go func() {
+		defer h.wg.Done()
+		defer func() { <-h.semaphore }()
+		h.respond(cmd, args)
+	}()
+}
+
+// verify はSlackの署名シークレットでリクエストの署名とタイムスタンプを検証する
+func (h *Handler) verify(header http.Header, body []byte) error {
+	verifier, err := slack.NewSecretsVerifier(header, h.signingSecret)
+	if err != nil {
+		return err
+	}
+	if _, err := verifier.Write(body); err != nil {
+		return err
+	}
+	return verifier.Ensure()
+}
+
+// respond は分析を実行し、結果またはエラーを response_url に送信する
+func (h *Handler) respond(cmd slack.SlashCommand, args *Args) {
+	ctx, cancel := context.WithTimeout(h.ctx, analysisTimeout)
+	defer cancel()
+
+	msg, err := h.analyze(ctx, cmd, args)
+	if err != nil {
+		msg = ephemeral(fmt.Sprintf("%s の分析に失敗しました: %v", targetLabel(args), err))
+	}
+	// 送信に失敗した場合はユーザーに伝える手段がないため、ログに記録する
+	if err := slack.PostWebhookCustomHTTPContext(ctx, cmd.ResponseURL, h.httpClient, msg); err != nil {
+		fmt.Fprintf(h.log, "スラッシュコマンド: %s の分析結果の返信に失敗しました: %v\n", targetLabel(args), err)
+	}
+}
+
+// analyze は引数に従って分析を実行し、返信するメッセージを作成する
+// チャンネルは、コマンドを実行したチャンネルか、実行したユーザーが参加しているチャンネルのみ分析する
+func (h *Handler) analyze(ctx context.Context, cmd slack.SlashCommand, args *Args) (*slack.WebhookMessage, error) {
+	var b strings.Builder
+	if args.HasUser() {
+		var result *service.UserAnalysisResult
+		var err error
+		if args.UserID != "" {
+			result, err = h.analyzer.AnalyzeUserByID(ctx, args.UserID, args.DateRange)
+		} else {
+			result, err = h.analyzer.AnalyzeUser(ctx, args.UserName, args.DateRange)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if err := report.WriteUserText(&b, result, report.DefaultUserLimits); err != nil {
+			return nil, err
+		}
+	} else {
+		channelID := args.ChannelID
+		if channelID == "" {
+			channel, err := h.channelRepo.FindByName(ctx, args.ChannelName)
+			if err != nil {
+				return nil, err
+			}
+			channelID = channel.ID
+		}
+		if channelID != cmd.ChannelID {
+			member, err := h.channelRepo.IsMember(ctx, channelID, cmd.UserID)
+			if err != nil {
+				return nil, err
+			}
+			if !member {
+				return nil, fmt.Errorf("参加していないチャンネルは分析できません")
+			}
+		}
+		result, err := h.analyzer.AnalyzeChannel(ctx, channelID, args.DateRange)
+		if err != nil {
+			return nil, err
+		}
+		if err := report.WriteChannelText(&b, result, report.DefaultChannelLimits); err != nil {
+			return nil, err
+		}
+	}
+
+	text := fmt.Sprintf("*%s の分析結果*（期間: %s）\n```\n%s```", targetLabel(args), report.FormatDateRange(args.DateRange), b.String())
+	msg := ephemeral(text)
+	if args.Public {
+		msg.ResponseType = slack.ResponseTypeInChannel
+	}
+	return msg, nil
+}
+
+// targetLabel は分析対象をSlackのメッセージで表示する形式で返す
+func targetLabel(args *Args) string {
+	switch {
+	case args.ChannelID != "":
+		return "<#" + args.ChannelID + ">"
+	case args.ChannelName != "":
+		return "#" + args.ChannelName
+	case args.UserID != "":
+		return "<@" + args.UserID + ">"
+	default:
+		return "@" + args.UserName
+	}
+}
+
+// ephemeral は実行したユーザーにのみ表示されるメッセージを作成する
+func ephemeral(text string) *slack.WebhookMessage {
+	return &slack.WebhookMessage{ResponseType: slack.ResponseTypeEphemeral, Text: text}
+}
+
+// writeMessage はスラッシュコマンドへの即時応答としてメッセージを返す
+func writeMessage(w http.ResponseWriter, msg *slack.WebhookMessage) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(msg)
+}
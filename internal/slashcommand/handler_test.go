@@ -0,0 +1,353 @@
+package slashcommand
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+	"github.com/slack-go/slack"
+)
+
+const testSigningSecret = "test-signing-secret"
+
+type fakeChannelRepository struct{}
+
+// fakeChannels はテスト用のチャンネルと、チャンネルに参加しているユーザー
+var fakeChannels = map[string]struct {
+	id      string
+	members []string
+}{
+	"general": {id: "C1", members: []string{"U1"}},
+	"dev":     {id: "C2", members: []string{"U1"}},
+	"secret":  {id: "C3"},
+}
+
+func (fakeChannelRepository) FindByName(ctx context.Context, name string) (*domain.Channel, error) {
+	if ch, ok := fakeChannels[name]; ok {
+		return &domain.Channel{ID: ch.id, Name: name}, nil
+	}
+	return nil, &domain.NotFoundError{Kind: "チャンネル", Name: name}
+}
+
+func (fakeChannelRepository) IsMember(ctx context.Context, channelID, userID string) (bool, error) {
+	for _, ch := range fakeChannels {
+		if ch.id == channelID {
+			return slices.Contains(ch.members, userID), nil
+		}
+	}
+	return false, nil
+}
+
+func (fakeChannelRepository) FindAll(ctx context.Context) ([]*domain.Channel, error) {
+	return nil, nil
+}
+
+type fakeMessageRepository struct {
+	messages []*domain.Message
+}
+
+func (r *fakeMessageRepository) FindByChannel(ctx context.Context, channelID string, dateRange *domain.DateRange) ([]*domain.Message, error) {
+	var messages []*domain.Message
+	for _, msg := range r.messages {
+		if msg.ChannelID == channelID {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+func (r *fakeMessageRepository) FindThreadReplies(ctx context.Context, channelID, threadTS string, dateRange *domain.DateRange) ([]*domain.Message, error) {
+	return nil, nil
+}
+
+func (r *fakeMessageRepository) FindThread(ctx context.Context, channelID, threadTS string, dateRange *domain.DateRange) (*domain.Message, []*domain.Message, error) {
+	return nil, nil, &domain.NotFoundError{Kind: "スレッドの親メッセージ", Name: threadTS}
+}
+
+func (r *fakeMessageRepository) FindByUser(ctx context.Context, userID string, dateRange *domain.DateRange) ([]*domain.Message, error) {
+	var messages []*domain.Message
+	for _, msg := range r.messages {
+		if msg.UserID == userID {
+			messages = append(messages, msg)
+		}
+	}
+	return messages, nil
+}
+
+func (r *fakeMessageRepository) FindBySearch(ctx context.Context, query string, dateRange *domain.DateRange, excludedChannels map[string]bool) ([]*domain.Message, []string, error) {
+	return nil, nil, nil
+}
+
+type fakeUserRepository struct {
+	users map[string]*domain.User
+}
+
+func (r *fakeUserRepository) FindByIDs(ctx context.Context, userIDs []string) (map[string]*domain.User, error) {
+	found := make(map[string]*domain.User)
+	for _, id := range userIDs {
+		if user, ok := r.users[id]; ok {
+			found[id] = user
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeUserRepository) FindAll(ctx context.Context) (map[string]*domain.User, error) {
+	return r.users, nil
+}
+
+func (r *fakeUserRepository) FindByName(ctx context.Context, name string) (*domain.User, error) {
+	for _, user := range r.users {
+		if user.Name == name {
+			return user, nil
+		}
+	}
+	return nil, &domain.NotFoundError{Kind: "ユーザー", Name: name}
+}
+
+// fakeSlack は response_url への送信を受け取るローカルのSlackエンドポイント
+type fakeSlack struct {
+	*httptest.Server
+	mu       sync.Mutex
+	messages []slack.WebhookMessage
+}
+
+func newFakeSlack(t *testing.T) *fakeSlack {
+	t.Helper()
+	f := &fakeSlack{}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg slack.WebhookMessage
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("invalid webhook payload: %v", err)
+		}
+		f.mu.Lock()
+		f.messages = append(f.messages, msg)
+		f.mu.Unlock()
+		w.Write([]byte("ok"))
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakeSlack) received() []slack.WebhookMessage {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]slack.WebhookMessage(nil), f.messages...)
+}
+
+func newTestHandler(t *testing.T) *Handler {
+	t.Helper()
+	messageRepo := &fakeMessageRepository{messages: []*domain.Message{
+		{ID: "1.000", ChannelID: "C1", UserID: "U1", Text: "こんにちは", Timestamp: time.Now(),
+			Reactions: []domain.Reaction{{Name: "tada", Count: 4}}},
+	}}
+	// ユーザー参照（<@U1>）は同名のユーザー（U2）と取り違えずにIDで分析する
+	userRepo := &fakeUserRepository{users: map[string]*domain.User{
+		"U1": {ID: "U1", Name: "taro", DisplayName: "田中太郎"},
+		"U2": {ID: "U2", Name: "taro", DisplayName: "佐藤太郎"},
+	}}
+	analyzer := service.NewAnalyzer(messageRepo, userRepo)
+	analyzer.SetProgressOutput(io.Discard)
+	return NewHandler(context.Background(), testSigningSecret, analyzer, fakeChannelRepository{})
+}
+
+// newSlashRequest はSlackと同じ方法で署名したスラッシュコマンドのリクエストを作成する
+func newSlashRequest(text, responseURL, secret string, timestamp time.Time) *http.Request {
+	body := url.Values{
+		"command":      {"/reactions"},
+		"text":         {text},
+		"channel_id":   {"C1"},
+		"channel_name": {"general"},
+		"user_id":      {"U1"},
+		"response_url": {responseURL},
+	}.Encode()
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + ts + ":" + body))
+
+	req := httptest.NewRequest(http.MethodPost, "/slack/commands", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", ts)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+func TestHandler_Verification(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp time.Time
+	}{
+		{name: "署名シークレットが異なる", secret: "wrong-secret", timestamp: time.Now()},
+		{name: "タイムスタンプが古い", secret: testSigningSecret, timestamp: time.Now().Add(-10 * time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeSlack(t)
+			h := newTestHandler(t)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, newSlashRequest("", fake.URL, tt.secret, tt.timestamp))
+			h.Wait()
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", rec.Code)
+			}
+			if got := fake.received(); len(got) != 0 {
+				t.Errorf("unverified request should not trigger a response, got %+v", got)
+			}
+		})
+	}
+}
+
+func TestHandler_RespondsViaResponseURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		text         string
+		wantContains []string
+		wantType     string
+	}{
+		{
+			name:         "実行したチャンネル",
+			text:         "last-7d",
+			wantContains: []string{"<#C1> の分析結果", ":tada: - 4回"},
+			wantType:     slack.ResponseTypeEphemeral,
+		},
+		{
+			name:         "チャンネル名を指定して公開",
+			text:         "#general public",
+			wantContains: []string{"#general の分析結果", "田中太郎 - 1投稿"},
+			wantType:     slack.ResponseTypeInChannel,
+		},
+		{
+			name:         "ユーザー参照",
+			text:         "<@U1|taro>",
+			wantContains: []string{"<@U1> の分析結果", "ユーザー分析結果: 田中太郎"},
+			wantType:     slack.ResponseTypeEphemeral,
+		},
+		{
+			name:         "参加しているチャンネル",
+			text:         "#dev",
+			wantContains: []string{"#dev の分析結果"},
+			wantType:     slack.ResponseTypeEphemeral,
+		},
+		{
+			name:         "参加していないチャンネル",
+			text:         "#secret public",
+			wantContains: []string{"分析に失敗しました", "参加していないチャンネルは分析できません"},
+			wantType:     slack.ResponseTypeEphemeral,
+		},
+		{
+			name:         "存在しないチャンネル",
+			text:         "#unknown",
+			wantContains: []string{"分析に失敗しました", "チャンネル 'unknown' が見つかりません"},
+			wantType:     slack.ResponseTypeEphemeral,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := newFakeSlack(t)
+			h := newTestHandler(t)
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, newSlashRequest(tt.text, fake.URL, testSigningSecret, time.Now()))
+
+			var ack slack.WebhookMessage
+			if err := json.Unmarshal(rec.Body.Bytes(), &ack); err != nil || rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, body = %s", rec.Code, rec.Body.String())
+			}
+			if !strings.Contains(ack.Text, "分析を開始しました") {
+				t.Errorf("acknowledgement = %q", ack.Text)
+			}
+
+			h.Wait()
+			got := fake.received()
+			if len(got) != 1 {
+				t.Fatalf("received %d messages, want 1", len(got))
+			}
+			for _, want := range tt.wantContains {
+				if !strings.Contains(got[0].Text, want) {
+					t.Errorf("response does not contain %q\n%s", want, got[0].Text)
+				}
+			}
+			if got[0].ResponseType != tt.wantType {
+				t.Errorf("ResponseType = %q, want %q", got[0].ResponseType, tt.wantType)
+			}
+		})
+	}
+}
+
+// TestHandler_LogsFailedResponse は response_url への送信に失敗した場合にログに記録することを確認する
+func TestHandler_LogsFailedResponse(t *testing.T) {
+	slackServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "expired_url", http.StatusNotFound)
+	}))
+	t.Cleanup(slackServer.Close)
+
+	h := newTestHandler(t)
+	var log strings.Builder
+	h.SetLogOutput(&log)
+	h.ServeHTTP(httptest.NewRecorder(), newSlashRequest("#general", slackServer.URL, testSigningSecret, time.Now()))
+	h.Wait()
+
+	if !strings.Contains(log.String(), "#general の分析結果の返信に失敗しました") {
+		t.Errorf("log = %q, want the failed response to be logged", log.String())
+	}
+}
+
+func TestHandler_InvalidArgs(t *testing.T) {
+	fake := newFakeSlack(t)
+	h := newTestHandler(t)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newSlashRequest("#general @taro", fake.URL, testSigningSecret, time.Now()))
+	h.Wait()
+
+	var msg slack.WebhookMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &msg); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if !strings.Contains(msg.Text, "使い方") {
+		t.Errorf("response should contain the usage, got %q", msg.Text)
+	}
+	if got := fake.received(); len(got) != 0 {
+		t.Errorf("invalid arguments should not start an analysis, got %+v", got)
+	}
+}
+
+// TestHandler_Busy は実行中の分析が上限に達している場合に、分析を開始せずに応答することを確認する
+func TestHandler_Busy(t *testing.T) {
+	fake := newFakeSlack(t)
+	h := newTestHandler(t)
+	for range maxConcurrentAnalyses {
+		h.semaphore <- struct{}{}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, newSlashRequest("last-7d", fake.URL, testSigningSecret, time.Now()))
+	h.Wait()
+
+	var msg slack.WebhookMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &msg); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if msg.Text != busyMessage || msg.ResponseType != slack.ResponseTypeEphemeral {
+		t.Errorf("response = %+v, want the ephemeral busy message", msg)
+	}
+	if got := fake.received(); len(got) != 0 {
+		t.Errorf("busy handler should not start an analysis, got %+v", got)
+	}
+}
@@ -0,0 +1,206 @@
+// Package store は分析に使用したデータ（チャンネル・ユーザー・メッセージ・リアクション・スレッド）をSQLiteのデータベースに書き出す
+// 同じファイルに繰り返し書き出した場合は、既存の行を更新して重複させない
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+
+	_ "modernc.org/sqlite" // cgoを使用しないSQLiteのドライバー
+)
+
+// SchemaVersion はデータベースのスキーマのバージョン（PRAGMA user_version に記録する）
+// 既存の列の削除や意味の変更など、互換性のない変更をした場合に上げる
+const SchemaVersion = 1
+
+// schema はテーブルの定義（docs/sqlite.md）
+// 日時はUTCのRFC 3339形式の文字列、真偽値は0/1で保存する
+const schema = `
+CREATE TABLE IF NOT EXISTS channels (
+	id   TEXT PRIMARY KEY,
+	name TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS users (
+	id           TEXT PRIMARY KEY,
+	name         TEXT NOT NULL,
+	display_name TEXT NOT NULL,
+	real_name    TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS messages (
+	channel_id     TEXT NOT NULL,
+	ts             TEXT NOT NULL,
+	user_id        TEXT NOT NULL,
+	text           TEXT NOT NULL,
+	posted_at      TEXT NOT NULL,
+	is_bot         INTEGER NOT NULL,
+	thread_ts      TEXT,
+	reaction_count INTEGER NOT NULL,
+	PRIMARY KEY (channel_id, ts)
+);
+CREATE INDEX IF NOT EXISTS messages_user_id ON messages (user_id);
+CREATE INDEX IF NOT EXISTS messages_thread_ts ON messages (channel_id, thread_ts);
+CREATE TABLE IF NOT EXISTS reactions (
+	channel_id TEXT NOT NULL,
+	ts         TEXT NOT NULL,
+	name       TEXT NOT NULL,
+	count      INTEGER NOT NULL,
+	PRIMARY KEY (channel_id, ts, name)
+);
+CREATE TABLE IF NOT EXISTS threads (
+	channel_id    TEXT NOT NULL,
+	thread_ts     TEXT NOT NULL,
+	user_id       TEXT,
+	reply_count   INTEGER NOT NULL,
+	last_reply_ts TEXT,
+	PRIMARY KEY (channel_id, thread_ts)
+);
+`
+
+// Dataset はデータベースに書き出すデータ
+type Dataset struct {
+	Channels []*domain.Channel
+	Users    []*domain.User
+	Messages []*domain.Message
+}
+
+// Summary は書き出した行数を表す
+type Summary struct {
+	Channels  int
+	Users     int
+	Messages  int
+	Reactions int
+	Threads   int
+}
+
+// WriteSQLite はデータセットを path のSQLiteのデータベースに書き出す（ファイルがない場合は作成する）
+// チャンネル・ユーザー・メッセージは主キーが同じ行を更新し、メッセージのリアクションは書き出したもので置き換える
+// スレッドの返信数はデータベースに保存されている全ての返信から数え直す
+func WriteSQLite(ctx context.Context, path string, data *Dataset) (*Summary, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("データベースを開けませんでした: %w", err)
+	}
+	defer db.Close()
+
+	if err := migrate(ctx, db); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("データベースの書き込みエラー: %w", err)
+	}
+	defer tx.Rollback()
+
+	summary, err := write(ctx, tx, data)
+	if err != nil {
+		return nil, fmt.Errorf("データベースの書き込みエラー: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("データベースの書き込みエラー: %w", err)
+	}
+	return summary, nil
+}
+
+// migrate はテーブルを作成し、スキーマのバージョンを記録する
+// より新しいバージョンで作成されたデータベースにはエラーを返す
+func migrate(ctx context.Context, db *sql.DB) error {
+	var version int
+	if err := db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("データベースを読み込めませんでした: %w", err)
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("データベースのスキーマ（バージョン%d）はこのバージョンのツールでは更新できません（対応: %d）", version, SchemaVersion)
+	}
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		return fmt.Errorf("テーブルの作成エラー: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", SchemaVersion)); err != nil {
+		return fmt.Errorf("テーブルの作成エラー: %w", err)
+	}
+	return nil
+}
+
+// write はトランザクション内でデータセットの各行を書き出す
+func write(ctx context.Context, tx *sql.Tx, data *Dataset) (*Summary, error) {
+	summary := &Summary{}
+	for _, channel := range data.Channels {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO channels (id, name) VALUES (?, ?)
+			ON CONFLICT (id) DO UPDATE SET name = excluded.name`,
+			channel.ID, channel.Name); err != nil {
+			return nil, err
+		}
+		summary.Channels++
+	}
+
+	for _, user := range data.Users {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO users (id, name, display_name, real_name) VALUES (?, ?, ?, ?)
+			ON CONFLICT (id) DO UPDATE SET name = excluded.name, display_name = excluded.display_name, real_name = excluded.real_name`,
+			user.ID, user.Name, user.DisplayName, user.RealName); err != nil {
+			return nil, err
+		}
+		summary.Users++
+	}
+
+	type threadKey struct{ channelID, threadTS string }
+	var threads []threadKey
+	seenThreads := make(map[threadKey]bool)
+	for _, msg := range data.Messages {
+		var threadTS any
+		if msg.ThreadTS != "" {
+			threadTS = msg.ThreadTS
+			key := threadKey{msg.ChannelID, msg.ThreadTS}
+			if !seenThreads[key] {
+				seenThreads[key] = true
+				threads = append(threads, key)
+			}
+		}
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO messages (channel_id, ts, user_id, text, posted_at, is_bot, thread_ts, reaction_count)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (channel_id, ts) DO UPDATE SET
+				user_id = excluded.user_id, text = excluded.text, posted_at = excluded.posted_at, is_bot = excluded.is_bot,
+				thread_ts = excluded.thread_ts, reaction_count = excluded.reaction_count`,
+			msg.ChannelID, msg.ID, msg.UserID, msg.Text, msg.PostedAt().UTC().Format(time.RFC3339Nano), msg.IsBot, threadTS, msg.TotalReactionCount()); err != nil {
+			return nil, err
+		}
+		summary.Messages++
+
+		// 前回の書き出しの後に外されたリアクションを残さないよう、メッセージのリアクションを置き換える
+		if _, err := tx.ExecContext(ctx, `DELETE FROM reactions WHERE channel_id = ? AND ts = ?`, msg.ChannelID, msg.ID); err != nil {
+			return nil, err
+		}
+		for _, reaction := range msg.Reactions {
+			if _, err := tx.ExecContext(ctx,
+				`INSERT INTO reactions (channel_id, ts, name, count) VALUES (?, ?, ?, ?)
+				ON CONFLICT (channel_id, ts, name) DO UPDATE SET count = excluded.count`,
+				msg.ChannelID, msg.ID, reaction.Name, reaction.Count); err != nil {
+				return nil, err
+			}
+			summary.Reactions++
+		}
+	}
+
+	// スレッドは今回の書き出しに含まれない返信も含めて集計する（期間を分けて書き出した場合も正しい返信数にする）
+	for _, key := range threads {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO threads (channel_id, thread_ts, user_id, reply_count, last_reply_ts)
+			SELECT ?1, ?2,
+				(SELECT user_id FROM messages WHERE channel_id = ?1 AND ts = ?2),
+				count(*), max(ts)
+			FROM messages WHERE channel_id = ?1 AND thread_ts = ?2 AND ts <> ?2
+			ON CONFLICT (channel_id, thread_ts) DO UPDATE SET
+				user_id = excluded.user_id, reply_count = excluded.reply_count, last_reply_ts = excluded.last_reply_ts`,
+			key.channelID, key.threadTS); err != nil {
+			return nil, err
+		}
+		summary.Threads++
+	}
+	return summary, nil
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+)
+
+func testDataset() *Dataset {
+	posted := time.Unix(1705280000, 0)
+	return &Dataset{
+		Channels: []*domain.Channel{{ID: "C1", Name: "general"}},
+		Users: []*domain.User{
+			{ID: "U1", Name: "taro", DisplayName: "太郎", RealName: "Taro Tanaka"},
+			{ID: "U2", Name: "hanako"},
+		},
+		Messages: []*domain.Message{
+			{ID: "1705280000.000100", Text: "リリースしました", UserID: "U1", ChannelID: "C1", Timestamp: posted, ThreadTS: "1705280000.000100",
+				Reactions: []domain.Reaction{{Name: "tada", Count: 3}, {Name: "+1::skin-tone-2", Count: 1}}},
+			{ID: "1705280100.000200", Text: "確認しました", UserID: "U2", ChannelID: "C1", Timestamp: posted, ThreadTS: "1705280000.000100"},
+			{ID: "1705280200.000300", Text: "別の話題", UserID: "U2", ChannelID: "C1", Timestamp: posted,
+				Reactions: []domain.Reaction{{Name: "eyes", Count: 1}}},
+		},
+	}
+}
+
+func openTestDB(t *testing.T, path string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+// queryInt は結果が1つの整数のクエリを実行する
+func queryInt(t *testing.T, db *sql.DB, query string, args ...any) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+		t.Fatalf("%s: %v", query, err)
+	}
+	return n
+}
+
+func TestWriteSQLite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analysis.db")
+	summary, err := WriteSQLite(context.Background(), path, testDataset())
+	if err != nil {
+		t.Fatalf("WriteSQLite() error = %v", err)
+	}
+	want := Summary{Channels: 1, Users: 2, Messages: 3, Reactions: 3, Threads: 1}
+	if *summary != want {
+		t.Errorf("WriteSQLite() = %+v, want %+v", *summary, want)
+	}
+
+	db := openTestDB(t, path)
+	if got := queryInt(t, db, "PRAGMA user_version"); got != SchemaVersion {
+		t.Errorf("user_version = %d, want %d", got, SchemaVersion)
+	}
+
+	var postedAt, displayName string
+	var threadTS sql.NullString
+	if err := db.QueryRow(`SELECT m.posted_at, m.thread_ts, u.display_name FROM messages m JOIN users u ON u.id = m.user_id WHERE m.ts = ?`,
+		"1705280000.000100").Scan(&postedAt, &threadTS, &displayName); err != nil {
+		t.Fatal(err)
+	}
+	if postedAt != "2024-01-15T00:53:20.0001Z" || threadTS.String != "1705280000.000100" || displayName != "太郎" {
+		t.Errorf("message = %s %v %s", postedAt, threadTS, displayName)
+	}
+	if got := queryInt(t, db, `SELECT count(*) FROM messages WHERE thread_ts IS NULL`); got != 1 {
+		t.Errorf("messages without thread_ts = %d, want 1", got)
+	}
+	if got := queryInt(t, db, `SELECT count FROM reactions WHERE ts = ? AND name = ?`, "1705280000.000100", "+1::skin-tone-2"); got != 1 {
+		t.Errorf("reaction count = %d, want 1", got)
+	}
+
+	var userID string
+	var replies int
+	var lastReply string
+	if err := db.QueryRow(`SELECT user_id, reply_count, last_reply_ts FROM threads WHERE channel_id = 'C1'`).Scan(&userID, &replies, &lastReply); err != nil {
+		t.Fatal(err)
+	}
+	if userID != "U1" || replies != 1 || lastReply != "1705280100.000200" {
+		t.Errorf("thread = %s %d %s, want U1 1 1705280100.000200", userID, replies, lastReply)
+	}
+}
+
+// TestWriteSQLite_Upsert は同じファイルに繰り返し書き出しても行が重複せず、最新の値に更新されることを確認する
+func TestWriteSQLite_Upsert(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analysis.db")
+	ctx := context.Background()
+	if _, err := WriteSQLite(ctx, path, testDataset()); err != nil {
+		t.Fatalf("WriteSQLite() error = %v", err)
+	}
+
+	// 2回目: チャンネル名の変更、リアクションの増減、別の期間のスレッドの返信
+	second := testDataset()
+	second.Channels[0].Name = "general-renamed"
+	second.Messages[0].Reactions = []domain.Reaction{{Name: "tada", Count: 5}}
+	second.Messages = append(second.Messages[:1], &domain.Message{
+		ID: "1705290000.000400", Text: "追記です", UserID: "U1", ChannelID: "C1", Timestamp: time.Unix(1705290000, 0), ThreadTS: "1705280000.000100",
+	})
+	if _, err := WriteSQLite(ctx, path, second); err != nil {
+		t.Fatalf("WriteSQLite() error = %v", err)
+	}
+
+	db := openTestDB(t, path)
+	for query, want := range map[string]int{
+		`SELECT count(*) FROM channels`:                                      1,
+		`SELECT count(*) FROM users`:                                         2,
+		`SELECT count(*) FROM messages`:                                      4,
+		`SELECT count(*) FROM reactions WHERE ts = '1705280000.000100'`:      1,
+		`SELECT count FROM reactions WHERE name = 'tada'`:                    5,
+		`SELECT reaction_count FROM messages WHERE ts = '1705280000.000100'`: 5,
+		`SELECT count(*) FROM reactions WHERE name = 'eyes'`:                 1,
+		`SELECT count(*) FROM threads`:                                       1,
+		`SELECT reply_count FROM threads`:                                    2,
+	} {
+		if got := queryInt(t, db, query); got != want {
+			t.Errorf("%s = %d, want %d", query, got, want)
+		}
+	}
+	var name string
+	if err := db.QueryRow(`SELECT name FROM channels WHERE id = 'C1'`).Scan(&name); err != nil || name != "general-renamed" {
+		t.Errorf("channel name = %q (%v), want general-renamed", name, err)
+	}
+}
+
+func TestWriteSQLite_NewerSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "analysis.db")
+	db := openTestDB(t, path)
+	if _, err := db.Exec("PRAGMA user_version = 99"); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	_, err := WriteSQLite(context.Background(), path, testDataset())
+	if err == nil || !strings.Contains(err.Error(), "バージョン99") {
+		t.Errorf("WriteSQLite() error = %v, want schema version error", err)
+	}
+}
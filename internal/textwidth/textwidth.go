@@ -0,0 +1,174 @@
+// Package textwidth は文字列を端末に表示したときの幅（桁数）を東アジアの文字幅に従って扱う
+package textwidth
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// wideRanges は端末で2桁の幅で表示される文字の範囲
+// UnicodeのEast Asian WidthがW（全角・CJK・絵文字など）とF（全角英数）の文字で、A（曖昧）は1桁として扱う
+var wideRanges = [][2]rune{
+	{0x1100, 0x115F}, {0x231A, 0x231B}, {0x2329, 0x232A}, {0x23E9, 0x23EC}, {0x23F0, 0x23F0}, {0x23F3, 0x23F3},
+	{0x25FD, 0x25FE}, {0x2614, 0x2615}, {0x2648, 0x2653}, {0x267F, 0x267F}, {0x2693, 0x2693}, {0x26A1, 0x26A1},
+	{0x26AA, 0x26AB}, {0x26BD, 0x26BE}, {0x26C4, 0x26C5}, {0x26CE, 0x26CE}, {0x26D4, 0x26D4}, {0x26EA, 0x26EA},
+	{0x26F2, 0x26F3}, {0x26F5, 0x26F5}, {0x26FA, 0x26FA}, {0x26FD, 0x26FD}, {0x2705, 0x2705}, {0x270A, 0x270B},
+	{0x2728, 0x2728}, {0x274C, 0x274C}, {0x274E, 0x274E}, {0x2753, 0x2755}, {0x2757, 0x2757}, {0x2795, 0x2797},
+	{0x27B0, 0x27B0}, {0x27BF, 0x27BF}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55}, {0x2E80, 0x303E},
+	{0x3041, 0x33FF}, {0x3400, 0x4DBF}, {0x4E00, 0x9FFF}, {0xA000, 0xA4CF}, {0xA960, 0xA97F}, {0xAC00, 0xD7A3},
+	{0xF900, 0xFAFF}, {0xFE10, 0xFE19}, {0xFE30, 0xFE6F}, {0xFF00, 0xFF60}, {0xFFE0, 0xFFE6},
+	{0x16FE0, 0x16FE4}, {0x17000, 0x18AFF}, {0x1B000, 0x1B2FF}, {0x1F004, 0x1F004}, {0x1F0CF, 0x1F0CF},
+	{0x1F18E, 0x1F18E}, {0x1F191, 0x1F19A}, {0x1F200, 0x1F202}, {0x1F210, 0x1F23B}, {0x1F240, 0x1F248},
+	{0x1F250, 0x1F251}, {0x1F260, 0x1F265}, {0x1F300, 0x1F320}, {0x1F32D, 0x1F335}, {0x1F337, 0x1F37C},
+	{0x1F37E, 0x1F393}, {0x1F3A0, 0x1F3CA}, {0x1F3CF, 0x1F3D3}, {0x1F3E0, 0x1F3F0}, {0x1F3F4, 0x1F3F4},
+	{0x1F3F8, 0x1F43E}, {0x1F440, 0x1F440}, {0x1F442, 0x1F4FC}, {0x1F4FF, 0x1F53D}, {0x1F54B, 0x1F54E},
+	{0x1F550, 0x1F567}, {0x1F57A, 0x1F57A}, {0x1F595, 0x1F596}, {0x1F5A4, 0x1F5A4}, {0x1F5FB, 0x1F64F},
+	{0x1F680, 0x1F6C5}, {0x1F6CC, 0x1F6CC}, {0x1F6D0, 0x1F6D2}, {0x1F6D5, 0x1F6D7}, {0x1F6DC, 0x1F6DF},
+	{0x1F6EB, 0x1F6EC}, {0x1F6F4, 0x1F6FC}, {0x1F7E0, 0x1F7EB}, {0x1F7F0, 0x1F7F0}, {0x1F90C, 0x1F93A},
+	{0x1F93C, 0x1F945}, {0x1F947, 0x1F9FF}, {0x1FA70, 0x1FAFF}, {0x20000, 0x2FFFD}, {0x30000, 0x3FFFD},
+}
+
+// 幅の計算で特別に扱う文字
+const (
+	zeroWidthJoiner   = '\u200d'     // 複数の絵文字を結合して1つの絵文字にする（家族の絵文字など）
+	variationSelector = '\ufe0f'     // 直前の文字を絵文字として表示する異体字セレクタ
+	regionalIndicator = '\U0001F1E6' // 国旗を表す地域指示子（2文字で1つの国旗）の先頭
+	skinToneModifier  = '\U0001F3FB' // 肌の色の修飾子（5種類）の先頭
+)
+
+// runeWidth は1文字の表示幅（0、1、2桁）を返す
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || (r >= 0x7F && r < 0xA0):
+		return 0
+	case r < 0x1100:
+		if unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+			return 0
+		}
+		return 1
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	i := sort.Search(len(wideRanges), func(i int) bool { return wideRanges[i][1] >= r })
+	if i < len(wideRanges) && wideRanges[i][0] <= r {
+		return 2
+	}
+	if isRegionalIndicator(r) {
+		return 2
+	}
+	return 1
+}
+
+// cluster は端末で1つの文字として表示される文字の並び（結合文字・絵文字の修飾を含む）
+type cluster struct {
+	text  string
+	width int
+}
+
+// clusters は文字列を表示上の文字に分割する
+// 結合文字・異体字セレクタ・肌の色の修飾子・ゼロ幅接合子で結合した絵文字・国旗は直前の文字と合わせて1つとして扱う
+func clusters(s string) []cluster {
+	var result []cluster
+	joined := false
+	for _, r := range s {
+		last := len(result) - 1
+		switch {
+		case last >= 0 && joined:
+			result[last].text += string(r)
+			joined = false
+		case last >= 0 && r == zeroWidthJoiner:
+			result[last].text += string(r)
+			joined = true
+		case last >= 0 && r == variationSelector:
+			result[last].text += string(r)
+			result[last].width = 2
+		case last >= 0 && r >= skinToneModifier && r <= skinToneModifier+4 && result[last].width == 2:
+			result[last].text += string(r)
+		case last >= 0 && isRegionalIndicator(r) && isSingleRegionalIndicator(result[last].text):
+			result[last].text += string(r)
+		case last >= 0 && runeWidth(r) == 0:
+			result[last].text += string(r)
+		default:
+			result = append(result, cluster{text: string(r), width: runeWidth(r)})
+		}
+	}
+	return result
+}
+
+// isRegionalIndicator は地域指示子かどうかを返す
+func isRegionalIndicator(r rune) bool {
+	return r >= regionalIndicator && r <= regionalIndicator+25
+}
+
+// isSingleRegionalIndicator は文字列が1つの地域指示子だけからなるかどうかを返す
+func isSingleRegionalIndicator(s string) bool {
+	runes := []rune(s)
+	return len(runes) == 1 && isRegionalIndicator(runes[0])
+}
+
+// Width は文字列を端末に表示したときの幅（桁数）を返す
+// 全角文字・絵文字は2桁、結合文字などは0桁として数える
+func Width(s string) int {
+	width := 0
+	for _, c := range clusters(s) {
+		width += c.width
+	}
+	return width
+}
+
+// Truncate は文字列を表示幅 width 桁以内に切り詰める（切り詰めた場合は末尾を … にする）
+// 全角文字や絵文字の途中では切らない
+func Truncate(s string, width int) string {
+	if Width(s) <= width {
+		return s
+	}
+	if width <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	used := 0
+	for _, c := range clusters(s) {
+		if used+c.width > width-1 {
+			break
+		}
+		b.WriteString(c.text)
+		used += c.width
+	}
+	return b.String() + "…"
+}
+
+// Wrap は1行の文字列を表示幅 width 桁ごとに折り返す
+// 全角文字や絵文字の途中では折り返さない（width が1桁で全角文字がある場合は、その行だけ width を超える）
+func Wrap(s string, width int) []string {
+	width = max(width, 1)
+	var lines []string
+	var b strings.Builder
+	used := 0
+	for _, c := range clusters(s) {
+		if used > 0 && used+c.width > width {
+			lines = append(lines, b.String())
+			b.Reset()
+			used = 0
+		}
+		b.WriteString(c.text)
+		used += c.width
+	}
+	return append(lines, b.String())
+}
+
+// Pad は文字列の表示幅が width 桁になるよう末尾を空白で埋める（超える場合はそのまま）
+func Pad(s string, width int) string {
+	if n := Width(s); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// PadLeft は文字列の表示幅が width 桁になるよう先頭を空白で埋める（右寄せ）
+func PadLeft(s string, width int) string {
+	if n := Width(s); n < width {
+		return strings.Repeat(" ", width-n) + s
+	}
+	return s
+}
@@ -0,0 +1,86 @@
+package textwidth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWidth(t *testing.T) {
+	tests := map[string]int{
+		"":             0,
+		"abc":          3,
+		"新機能":          6,
+		"ｱｲｳ":          3,
+		"ＡＢＣ":          6,
+		"🎉":            2,
+		"👍🏽":           2,
+		"\u2764\ufe0f": 2,
+		"\U0001F468\u200d\U0001F469\u200d\U0001F467": 2,
+		"🇯🇵":         2,
+		"e\u0301":    1,
+		"リリース🎉 done": 15,
+		"タブ\tと改行\n":  10,
+	}
+	for s, want := range tests {
+		if got := Width(s); got != want {
+			t.Errorf("Width(%q) = %d, want %d", s, got, want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  string
+	}{
+		{"新機能のリリース", 20, "新機能のリリース"},
+		{"新機能のリリース", 16, "新機能のリリース"},
+		{"新機能のリリース", 9, "新機能の…"},
+		{"新機能のリリース", 10, "新機能の…"},
+		{"release 🎉🎉", 10, "release …"},
+		{"🇯🇵🇺🇸", 3, "🇯🇵…"},
+		{"abc", 0, ""},
+	}
+	for _, tt := range tests {
+		got := Truncate(tt.s, tt.width)
+		if got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+		if Width(got) > tt.width {
+			t.Errorf("Width(Truncate(%q, %d)) = %d, exceeds width", tt.s, tt.width, Width(got))
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	if got, want := Pad("田中", 6), "田中  "; got != want {
+		t.Errorf("Pad() = %q, want %q", got, want)
+	}
+	if got, want := PadLeft("1位", 5), "  1位"; got != want {
+		t.Errorf("PadLeft() = %q, want %q", got, want)
+	}
+	if got, want := Pad("田中太郎", 4), "田中太郎"; got != want {
+		t.Errorf("Pad() = %q, want %q", got, want)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	tests := []struct {
+		s     string
+		width int
+		want  []string
+	}{
+		{"", 10, []string{""}},
+		{"abcdef", 4, []string{"abcd", "ef"}},
+		{"新機能のリリース", 6, []string{"新機能", "のリリ", "ース"}},
+		{"a新機能", 4, []string{"a新", "機能"}},
+		{"🇯🇵🇺🇸", 3, []string{"🇯🇵", "🇺🇸"}},
+	}
+	for _, tt := range tests {
+		got := Wrap(tt.s, tt.width)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Wrap(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,206 @@
+// Package tui は分析結果を対話的に閲覧する全画面のターミナルUIを提供する
+package tui
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+// Dataset はTUIで表示する分析結果を表す
+type Dataset struct {
+	Title     string            // 画面上部に表示する分析対象（例: #general）
+	Tabs      []Tab             // ランキングごとのタブ
+	UserNames map[string]string // ユーザーID -> 表示名
+}
+
+// Tab はランキング1つ分のタブを表す
+type Tab struct {
+	Name string
+	Unit string // 値の単位（例: 回、投稿）
+	Rows []Row
+}
+
+// Row はランキングの1行を表す
+type Row struct {
+	Label    string
+	Value    int
+	Messages []*domain.Message // 行を選択したときに表示するメッセージ
+}
+
+// FromAnalysisResult はチャンネル分析結果から表示用のデータを作成する
+func FromAnalysisResult(title string, result *service.AnalysisResult) *Dataset {
+	idx := newMessageIndex(result.Messages)
+	userNames := make(map[string]string, len(result.UserStats))
+	for _, stat := range result.UserStats {
+		userNames[stat.UserID] = stat.UserName
+	}
+
+	emojiTab := Tab{Name: "スタンプ", Unit: "回"}
+	for _, stat := range result.EmojiStats {
+		emojiTab.Rows = append(emojiTab.Rows, Row{
+			Label:    fmt.Sprintf(":%s:", stat.Emoji),
+			Value:    stat.Count,
+			Messages: idx.withReaction(stat.Emoji, ""),
+		})
+	}
+
+	messageTab := Tab{Name: "メッセージ", Unit: "リアクション"}
+	for _, stat := range result.MessageStats {
+		messageTab.Rows = append(messageTab.Rows, Row{
+			Label:    stat.Text,
+			Value:    stat.Reactions,
+			Messages: idx.thread(stat.ChannelID, stat.MessageID),
+		})
+	}
+
+	threadTab := Tab{Name: "スレッド", Unit: "コメント"}
+	for _, stat := range result.ThreadStats {
+		threadTab.Rows = append(threadTab.Rows, Row{
+			Label:    stat.Text,
+			Value:    stat.ReplyCount,
+			Messages: idx.thread(stat.ChannelID, stat.MessageID),
+		})
+	}
+
+	userTab := Tab{Name: "ユーザー", Unit: "投稿"}
+	for _, stat := range result.UserStats {
+		userTab.Rows = append(userTab.Rows, Row{
+			Label:    stat.UserName,
+			Value:    stat.Count,
+			Messages: idx.byUser(stat.UserID),
+		})
+	}
+
+	return &Dataset{
+		Title:     title,
+		Tabs:      []Tab{emojiTab, messageTab, threadTab, userTab},
+		UserNames: userNames,
+	}
+}
+
+// FromUserAnalysisResult はユーザー分析結果から表示用のデータを作成する
+func FromUserAnalysisResult(result *service.UserAnalysisResult) *Dataset {
+	idx := newMessageIndex(result.Messages)
+
+	emojiTab := Tab{Name: "スタンプ", Unit: "回"}
+	for _, stat := range result.ReactionRanking {
+		emojiTab.Rows = append(emojiTab.Rows, Row{
+			Label:    fmt.Sprintf(":%s:", stat.Emoji),
+			Value:    stat.Count,
+			Messages: idx.withReaction(stat.Emoji, result.UserID),
+		})
+	}
+
+	// 本人の投稿をリアクション数の多い順に並べる
+	messageTab := Tab{Name: "メッセージ", Unit: "リアクション"}
+	for _, msg := range idx.byUser(result.UserID) {
+		if total := msg.TotalReactionCount(); total > 0 {
+			messageTab.Rows = append(messageTab.Rows, Row{
+				Label:    msg.Text,
+				Value:    total,
+				Messages: idx.thread(msg.ChannelID, msg.ID),
+			})
+		}
+	}
+	sort.SliceStable(messageTab.Rows, func(i, j int) bool {
+		return messageTab.Rows[i].Value > messageTab.Rows[j].Value
+	})
+
+	threadTab := Tab{Name: "スレッド", Unit: "コメント"}
+	for _, stat := range result.ThreadStats {
+		threadTab.Rows = append(threadTab.Rows, Row{
+			Label:    stat.Text,
+			Value:    stat.ReplyCount,
+			Messages: idx.thread(stat.ChannelID, stat.MessageID),
+		})
+	}
+
+	return &Dataset{
+		Title:     result.UserName,
+		Tabs:      []Tab{emojiTab, messageTab, threadTab},
+		UserNames: map[string]string{result.UserID: result.UserName},
+	}
+}
+
+// messageIndex はドリルダウン用にメッセージを検索するためのインデックス
+type messageIndex struct {
+	messages []*domain.Message
+	byKey    map[string]*domain.Message   // チャンネルID/タイムスタンプ -> メッセージ
+	replies  map[string][]*domain.Message // チャンネルID/スレッドのタイムスタンプ -> 返信
+}
+
+// newMessageIndex はメッセージのインデックスを作成する
+func newMessageIndex(messages []*domain.Message) *messageIndex {
+	idx := &messageIndex{
+		messages: messages,
+		byKey:    make(map[string]*domain.Message, len(messages)),
+		replies:  make(map[string][]*domain.Message),
+	}
+	for _, msg := range messages {
+		idx.byKey[messageKey(msg.ChannelID, msg.ID)] = msg
+		if msg.IsThreadReply() {
+			key := messageKey(msg.ChannelID, msg.ThreadTS)
+			idx.replies[key] = append(idx.replies[key], msg)
+		}
+	}
+	return idx
+}
+
+// thread はメッセージとそのスレッド返信を時系列順で返す
+func (idx *messageIndex) thread(channelID, messageID string) []*domain.Message {
+	key := messageKey(channelID, messageID)
+	var messages []*domain.Message
+	if parent, ok := idx.byKey[key]; ok {
+		messages = append(messages, parent)
+	}
+	replies := append([]*domain.Message(nil), idx.replies[key]...)
+	sort.SliceStable(replies, func(i, j int) bool {
+		return replies[i].Timestamp.Before(replies[j].Timestamp)
+	})
+	return append(messages, replies...)
+}
+
+// withReaction は指定された絵文字のリアクションがついたメッセージを、その絵文字の数が多い順に返す
+// userID が指定された場合はそのユーザーの投稿に限定する
+func (idx *messageIndex) withReaction(emoji, userID string) []*domain.Message {
+	counts := make(map[*domain.Message]int)
+	var messages []*domain.Message
+	for _, msg := range idx.messages {
+		if userID != "" && msg.UserID != userID {
+			continue
+		}
+		for _, reaction := range msg.Reactions {
+			if reaction.Name == emoji {
+				counts[msg] = reaction.Count
+				messages = append(messages, msg)
+				break
+			}
+		}
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return counts[messages[i]] > counts[messages[j]]
+	})
+	return messages
+}
+
+// byUser は指定されたユーザーの投稿を新しい順に返す
+func (idx *messageIndex) byUser(userID string) []*domain.Message {
+	var messages []*domain.Message
+	for _, msg := range idx.messages {
+		if msg.UserID == userID {
+			messages = append(messages, msg)
+		}
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		return messages[i].Timestamp.After(messages[j].Timestamp)
+	})
+	return messages
+}
+
+// messageKey はチャンネルIDとタイムスタンプからメッセージを一意に識別するキーを作成する
+func messageKey(channelID, ts string) string {
+	return channelID + "/" + ts
+}
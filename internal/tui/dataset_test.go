@@ -0,0 +1,98 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/service"
+)
+
+func TestFromAnalysisResult(t *testing.T) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	parent := &domain.Message{
+		ID: "100.000", ChannelID: "C1", UserID: "U1", Text: "親メッセージ", Timestamp: base, ThreadTS: "100.000",
+		Reactions: []domain.Reaction{{Name: "+1", Count: 3}},
+	}
+	reply2 := &domain.Message{ID: "102.000", ChannelID: "C1", UserID: "U1", Text: "返信2", Timestamp: base.Add(2 * time.Minute), ThreadTS: "100.000"}
+	reply1 := &domain.Message{
+		ID: "101.000", ChannelID: "C1", UserID: "U2", Text: "返信1", Timestamp: base.Add(time.Minute), ThreadTS: "100.000",
+		Reactions: []domain.Reaction{{Name: "+1", Count: 5}},
+	}
+	other := &domain.Message{ID: "200.000", ChannelID: "C1", UserID: "U2", Text: "別の投稿", Timestamp: base.Add(time.Hour)}
+
+	result := &service.AnalysisResult{
+		EmojiStats:   []domain.EmojiCount{{Emoji: "+1", Count: 8}},
+		MessageStats: []domain.MessageReaction{{Text: "返信1", Reactions: 5, ChannelID: "C1", MessageID: "101.000"}},
+		ThreadStats:  []domain.ThreadStats{{Text: "親メッセージ", ReplyCount: 2, ChannelID: "C1", MessageID: "100.000"}},
+		UserStats: []domain.UserStats{
+			{UserID: "U2", UserName: "佐藤花子", Count: 2},
+			{UserID: "U1", UserName: "田中太郎", Count: 2},
+		},
+		Messages: []*domain.Message{parent, reply2, reply1, other},
+	}
+
+	data := FromAnalysisResult("#general", result)
+
+	if len(data.Tabs) != 4 {
+		t.Fatalf("len(Tabs) = %d, want 4", len(data.Tabs))
+	}
+	if got := data.UserNames["U2"]; got != "佐藤花子" {
+		t.Errorf("UserNames[U2] = %q, want 佐藤花子", got)
+	}
+
+	tests := []struct {
+		name string
+		tab  int
+		want []*domain.Message
+	}{
+		{name: "スタンプはその絵文字の数が多い順", tab: 0, want: []*domain.Message{reply1, parent}},
+		{name: "メッセージは単体（返信なし）", tab: 1, want: []*domain.Message{reply1}},
+		{name: "スレッドは親と返信を時系列順", tab: 2, want: []*domain.Message{parent, reply1, reply2}},
+		{name: "ユーザーは新しい順", tab: 3, want: []*domain.Message{other, reply1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := data.Tabs[tt.tab].Rows[0].Messages
+			if len(got) != len(tt.want) {
+				t.Fatalf("len(Messages) = %d, want %d", len(got), len(tt.want))
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("Messages[%d] = %q, want %q", i, got[i].Text, tt.want[i].Text)
+				}
+			}
+		})
+	}
+}
+
+func TestFromUserAnalysisResult(t *testing.T) {
+	base := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	low := &domain.Message{ID: "1.000", ChannelID: "C1", UserID: "U1", Text: "少ない", Timestamp: base,
+		Reactions: []domain.Reaction{{Name: "eyes", Count: 1}}}
+	high := &domain.Message{ID: "2.000", ChannelID: "C2", UserID: "U1", Text: "多い", Timestamp: base.Add(time.Hour),
+		Reactions: []domain.Reaction{{Name: "tada", Count: 4}}}
+	none := &domain.Message{ID: "3.000", ChannelID: "C2", UserID: "U1", Text: "なし", Timestamp: base.Add(2 * time.Hour)}
+	otherUser := &domain.Message{ID: "4.000", ChannelID: "C2", UserID: "U2", Text: "他人", Timestamp: base,
+		Reactions: []domain.Reaction{{Name: "tada", Count: 9}}}
+
+	result := &service.UserAnalysisResult{
+		UserID:          "U1",
+		UserName:        "田中太郎",
+		ReactionRanking: []domain.EmojiCount{{Emoji: "tada", Count: 4}},
+		Messages:        []*domain.Message{low, high, none, otherUser},
+	}
+
+	data := FromUserAnalysisResult(result)
+
+	if data.Title != "田中太郎" {
+		t.Errorf("Title = %q, want 田中太郎", data.Title)
+	}
+	if got := data.Tabs[0].Rows[0].Messages; len(got) != 1 || got[0] != high {
+		t.Errorf("emoji drill-down should contain only the user's messages, got %d messages", len(got))
+	}
+	rows := data.Tabs[1].Rows
+	if len(rows) != 2 || rows[0].Label != "多い" || rows[1].Label != "少ない" {
+		t.Errorf("message tab rows = %+v, want 多い, 少ない", rows)
+	}
+}
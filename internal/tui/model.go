@@ -0,0 +1,368 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/textwidth"
+)
+
+// ANSIエスケープシーケンス
+const (
+	styleReverse = "\x1b[7m"
+	styleBold    = "\x1b[1m"
+	styleDim     = "\x1b[2m"
+	styleReset   = "\x1b[0m"
+)
+
+// action はキー入力の結果として端末側で実行する処理
+type action int
+
+const (
+	actionNone  action = iota
+	actionQuit         // 終了
+	actionRerun        // model.rerunRange の期間で再分析
+)
+
+// inputStep は期間入力の段階
+type inputStep int
+
+const (
+	inputNone  inputStep = iota
+	inputStart           // 開始日の入力中
+	inputEnd             // 終了日の入力中
+)
+
+// model はTUIの表示状態を表す
+type model struct {
+	data      *Dataset
+	dateRange *domain.DateRange
+	tab       int
+	cursors   []int // タブごとの選択行
+
+	detail       *Row // 詳細表示中の行（nilの場合は一覧表示）
+	detailOffset int  // 詳細表示のスクロール位置（行）
+
+	input      inputStep
+	inputBuf   []rune
+	inputStart string // 入力済みの開始日
+
+	rerunRange *domain.DateRange
+	status     string // 画面下部に表示するメッセージ
+	height     int    // 直近の描画で使用した画面の高さ
+}
+
+// newModel は表示状態を初期化する
+func newModel(data *Dataset, dateRange *domain.DateRange) *model {
+	m := &model{height: 24}
+	m.setData(data, dateRange)
+	return m
+}
+
+// setData は表示するデータを差し替える（タブの位置は維持する）
+func (m *model) setData(data *Dataset, dateRange *domain.DateRange) {
+	m.data = data
+	m.dateRange = dateRange
+	m.cursors = make([]int, len(data.Tabs))
+	if m.tab >= len(data.Tabs) {
+		m.tab = 0
+	}
+	m.detail = nil
+	m.detailOffset = 0
+}
+
+// handleKey はキー入力に応じて状態を更新する
+func (m *model) handleKey(k key) action {
+	if k.code == keyCtrlC {
+		return actionQuit
+	}
+	if m.input != inputNone {
+		return m.handleInputKey(k)
+	}
+	m.status = ""
+	if m.detail != nil {
+		return m.handleDetailKey(k)
+	}
+	return m.handleListKey(k)
+}
+
+// handleListKey は一覧表示中のキー入力を処理する
+func (m *model) handleListKey(k key) action {
+	rows := m.currentRows()
+	switch {
+	case k.code == keyUp || k.is('k'):
+		m.moveCursor(-1)
+	case k.code == keyDown || k.is('j'):
+		m.moveCursor(1)
+	case k.code == keyPageUp:
+		m.moveCursor(-m.bodyHeight())
+	case k.code == keyPageDown:
+		m.moveCursor(m.bodyHeight())
+	case k.code == keyLeft || k.is('h'):
+		m.tab = (m.tab + len(m.data.Tabs) - 1) % len(m.data.Tabs)
+	case k.code == keyRight || k.code == keyTab || k.is('l'):
+		m.tab = (m.tab + 1) % len(m.data.Tabs)
+	case k.code == keyRune && k.r >= '1' && k.r <= '9':
+		if i := int(k.r - '1'); i < len(m.data.Tabs) {
+			m.tab = i
+		}
+	case k.code == keyEnter:
+		if len(rows) > 0 {
+			m.detail = &rows[m.cursors[m.tab]]
+			m.detailOffset = 0
+		}
+	case k.is('d'):
+		m.input = inputStart
+		m.inputBuf = []rune(formatDate(m.dateRange.Start))
+	case k.is('r'):
+		m.rerunRange = m.dateRange
+		return actionRerun
+	case k.is('q'):
+		return actionQuit
+	}
+	return actionNone
+}
+
+// handleDetailKey は詳細表示中のキー入力を処理する
+func (m *model) handleDetailKey(k key) action {
+	switch {
+	case k.code == keyUp || k.is('k'):
+		m.detailOffset = max(m.detailOffset-1, 0)
+	case k.code == keyDown || k.is('j'):
+		m.detailOffset++
+	case k.code == keyPageUp:
+		m.detailOffset = max(m.detailOffset-m.bodyHeight(), 0)
+	case k.code == keyPageDown:
+		m.detailOffset += m.bodyHeight()
+	case k.code == keyEsc || k.code == keyBackspace || k.code == keyLeft || k.is('h'):
+		m.detail = nil
+	case k.is('q'):
+		return actionQuit
+	}
+	return actionNone
+}
+
+// handleInputKey は期間入力中のキー入力を処理する
+func (m *model) handleInputKey(k key) action {
+	switch k.code {
+	case keyEsc:
+		m.input = inputNone
+		m.status = "期間の変更を取り消しました"
+	case keyBackspace:
+		if len(m.inputBuf) > 0 {
+			m.inputBuf = m.inputBuf[:len(m.inputBuf)-1]
+		}
+	case keyRune:
+		m.inputBuf = append(m.inputBuf, k.r)
+	case keyEnter:
+		value := strings.TrimSpace(string(m.inputBuf))
+		if m.input == inputStart {
+			m.inputStart = value
+			m.input = inputEnd
+			m.inputBuf = []rune(formatDate(m.dateRange.End))
+			return actionNone
+		}
+		m.input = inputNone
+		dateRange, err := domain.ParseDateRange(m.inputStart, value, time.Local)
+		if err != nil {
+			m.status = err.Error()
+			return actionNone
+		}
+		m.rerunRange = dateRange
+		return actionRerun
+	}
+	return actionNone
+}
+
+// currentRows は選択中のタブの行を返す
+func (m *model) currentRows() []Row {
+	return m.data.Tabs[m.tab].Rows
+}
+
+// moveCursor は選択行を移動する
+func (m *model) moveCursor(delta int) {
+	rows := m.currentRows()
+	if len(rows) == 0 {
+		return
+	}
+	m.cursors[m.tab] = min(max(m.cursors[m.tab]+delta, 0), len(rows)-1)
+}
+
+// bodyHeight は本文の表示行数を返す（ヘッダー3行とフッター2行を除く）
+func (m *model) bodyHeight() int {
+	return max(m.height-5, 1)
+}
+
+// render は画面全体を行のスライスとして描画する
+func (m *model) render(width, height int) []string {
+	m.height = height
+	lines := make([]string, 0, height)
+
+	lines = append(lines, styleBold+truncate(fmt.Sprintf(" slack-reaction  %s  期間: %s", m.data.Title, formatDateRange(m.dateRange)), width)+styleReset)
+	lines = append(lines, m.renderTabs(width))
+	lines = append(lines, strings.Repeat("─", width))
+
+	var body []string
+	if m.detail != nil {
+		body = m.renderDetail(width)
+	} else {
+		body = m.renderList(width)
+	}
+	for len(body) < m.bodyHeight() {
+		body = append(body, "")
+	}
+	lines = append(lines, body...)
+
+	lines = append(lines, strings.Repeat("─", width))
+	lines = append(lines, m.renderFooter(width))
+	return lines
+}
+
+// renderTabs はタブの見出しを描画する
+func (m *model) renderTabs(width int) string {
+	var b strings.Builder
+	for i, tab := range m.data.Tabs {
+		label := fmt.Sprintf(" %d:%s(%d) ", i+1, tab.Name, len(tab.Rows))
+		if i == m.tab {
+			b.WriteString(styleReverse + label + styleReset)
+		} else {
+			b.WriteString(label)
+		}
+		b.WriteString(" ")
+	}
+	return b.String()
+}
+
+// renderList はランキングの一覧を描画する
+func (m *model) renderList(width int) []string {
+	tab := m.data.Tabs[m.tab]
+	if len(tab.Rows) == 0 {
+		return []string{styleDim + " データがありません" + styleReset}
+	}
+
+	height := m.bodyHeight()
+	cursor := m.cursors[m.tab]
+	offset := 0
+	if cursor >= height {
+		offset = cursor - height + 1
+	}
+
+	lines := make([]string, 0, height)
+	for i := offset; i < len(tab.Rows) && len(lines) < height; i++ {
+		row := tab.Rows[i]
+		value := fmt.Sprintf("%d%s", row.Value, tab.Unit)
+		prefix := fmt.Sprintf(" %3d. ", i+1)
+		labelWidth := max(width-textwidth.Width(prefix)-textwidth.Width(value)-2, 1)
+		label := textwidth.Pad(textwidth.Truncate(oneLine(row.Label), labelWidth), labelWidth)
+		line := prefix + label + " " + value
+		if i == cursor {
+			line = styleReverse + line + styleReset
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// renderDetail は選択した行のメッセージを描画する
+func (m *model) renderDetail(width int) []string {
+	tab := m.data.Tabs[m.tab]
+	var lines []string
+	lines = append(lines, styleBold+truncate(fmt.Sprintf(" %s: %s（%d%s）- %d件のメッセージ", tab.Name, oneLine(m.detail.Label), m.detail.Value, tab.Unit, len(m.detail.Messages)), width)+styleReset)
+	for _, msg := range m.detail.Messages {
+		lines = append(lines, "")
+		header := fmt.Sprintf(" %s  %s", msg.Timestamp.Local().Format("2006-01-02 15:04"), m.userName(msg.UserID))
+		if msg.IsThreadReply() {
+			header += "  (返信)"
+		}
+		if reactions := formatReactions(msg.Reactions); reactions != "" {
+			header += "  " + reactions
+		}
+		lines = append(lines, styleDim+truncate(header, width)+styleReset)
+		for _, line := range wrap(msg.Text, width-3) {
+			lines = append(lines, "   "+line)
+		}
+	}
+
+	// スクロール位置を調整する（見出し行は常に表示）
+	height := m.bodyHeight()
+	maxOffset := max(len(lines)-height, 0)
+	m.detailOffset = min(m.detailOffset, maxOffset)
+	return append(lines[:1:1], lines[1+m.detailOffset:min(len(lines), m.detailOffset+height)]...)
+}
+
+// renderFooter は操作方法、入力欄またはステータスを描画する
+func (m *model) renderFooter(width int) string {
+	switch {
+	case m.input == inputStart:
+		return truncate(" 開始日（YYYY-MM-DD、空欄で無制限）: "+string(m.inputBuf), width) + "_"
+	case m.input == inputEnd:
+		return truncate(" 終了日（YYYY-MM-DD、空欄で無制限）: "+string(m.inputBuf), width) + "_"
+	case m.status != "":
+		return styleBold + truncate(" "+m.status, width) + styleReset
+	case m.detail != nil:
+		return styleDim + truncate(" ↑↓/jk:スクロール  Esc/←:戻る  q:終了", width) + styleReset
+	default:
+		return styleDim + truncate(" ↑↓/jk:移動  ←→/Tab/1-9:タブ  Enter:詳細  d:期間変更  r:再分析  q:終了", width) + styleReset
+	}
+}
+
+// userName はユーザーIDに対応する表示名を返す
+func (m *model) userName(userID string) string {
+	if name, ok := m.data.UserNames[userID]; ok {
+		return name
+	}
+	return userID
+}
+
+// formatReactions はリアクションを ":emoji:×数" 形式で連結する
+func formatReactions(reactions []domain.Reaction) string {
+	parts := make([]string, 0, len(reactions))
+	for _, r := range reactions {
+		parts = append(parts, fmt.Sprintf(":%s:×%d", r.Name, r.Count))
+	}
+	return strings.Join(parts, " ")
+}
+
+// formatDateRange は期間を表示用に整形する
+func formatDateRange(dateRange *domain.DateRange) string {
+	if dateRange == nil || (dateRange.Start.IsZero() && dateRange.End.IsZero()) {
+		return "全期間"
+	}
+	start, end := formatDate(dateRange.Start), formatDate(dateRange.End)
+	if start == "" {
+		start = "（指定なし）"
+	}
+	if end == "" {
+		end = "（指定なし）"
+	}
+	return start + " 〜 " + end
+}
+
+// formatDate は日付をYYYY-MM-DD形式に整形する（ゼロ値の場合は空文字列）
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+// oneLine は改行や連続する空白を1つの空白にまとめる
+func oneLine(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// truncate は文字列を端末の表示幅 width 桁以内に切り詰める（全角文字は2桁として数える）
+func truncate(s string, width int) string {
+	return textwidth.Truncate(s, width)
+}
+
+// wrap は本文を改行と端末の表示幅で折り返す
+func wrap(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		lines = append(lines, textwidth.Wrap(paragraph, width)...)
+	}
+	return lines
+}
@@ -0,0 +1,181 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"github.com/Tattsum/slack-reaction/internal/textwidth"
+)
+
+func testDataset() *Dataset {
+	msg := &domain.Message{ID: "1.000", ChannelID: "C1", UserID: "U1", Text: "こんにちは\n2行目", Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)}
+	return &Dataset{
+		Title: "#general",
+		Tabs: []Tab{
+			{Name: "スタンプ", Unit: "回", Rows: []Row{
+				{Label: ":+1:", Value: 3, Messages: []*domain.Message{msg}},
+				{Label: ":eyes:", Value: 2},
+				{Label: ":tada:", Value: 1},
+			}},
+			{Name: "ユーザー", Unit: "投稿", Rows: []Row{{Label: "田中太郎", Value: 1}}},
+		},
+		UserNames: map[string]string{"U1": "田中太郎"},
+	}
+}
+
+func runes(s string) []key {
+	return decodeKeys([]byte(s))
+}
+
+func TestDecodeKeys(t *testing.T) {
+	got := decodeKeys([]byte("j\x1b[A\x1b[6~\r\t\x1b\x7f\x03あ"))
+	want := []key{
+		{code: keyRune, r: 'j'},
+		{code: keyUp},
+		{code: keyPageDown},
+		{code: keyEnter},
+		{code: keyTab},
+		{code: keyEsc},
+		{code: keyBackspace},
+		{code: keyCtrlC},
+		{code: keyRune, r: 'あ'},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("decodeKeys() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("key[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestModel_Navigation(t *testing.T) {
+	m := newModel(testDataset(), &domain.DateRange{})
+
+	for _, k := range runes("jjj") {
+		m.handleKey(k)
+	}
+	if m.cursors[0] != 2 {
+		t.Errorf("cursor = %d, want 2 (clamped to last row)", m.cursors[0])
+	}
+
+	m.handleKey(key{code: keyTab})
+	if m.tab != 1 {
+		t.Errorf("tab = %d, want 1", m.tab)
+	}
+	m.handleKey(key{code: keyRight})
+	if m.tab != 0 {
+		t.Errorf("tab = %d, want 0 (wrap around)", m.tab)
+	}
+	if m.cursors[0] != 2 {
+		t.Errorf("cursor should be kept per tab, got %d", m.cursors[0])
+	}
+
+	m.handleKey(key{code: keyRune, r: '1'})
+	for _, k := range runes("kk") {
+		m.handleKey(k)
+	}
+	m.handleKey(key{code: keyEnter})
+	if m.detail == nil || m.detail.Label != ":+1:" {
+		t.Fatalf("detail = %+v, want :+1:", m.detail)
+	}
+	screen := strings.Join(m.render(80, 24), "\n")
+	for _, want := range []string{"2024-01-01", "田中太郎", "こんにちは", "2行目"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("detail screen does not contain %q\n%s", want, screen)
+		}
+	}
+
+	m.handleKey(key{code: keyEsc})
+	if m.detail != nil {
+		t.Error("Esc should return to the list")
+	}
+	if got := m.handleKey(key{code: keyRune, r: 'q'}); got != actionQuit {
+		t.Errorf("q action = %v, want actionQuit", got)
+	}
+}
+
+func TestModel_ChangeDateRange(t *testing.T) {
+	m := newModel(testDataset(), &domain.DateRange{})
+
+	m.handleKey(key{code: keyRune, r: 'd'})
+	for _, k := range runes("2024-01-01\r2024-01-31") {
+		if got := m.handleKey(k); got != actionNone {
+			t.Fatalf("unexpected action %v before confirming the end date", got)
+		}
+	}
+	if got := m.handleKey(key{code: keyEnter}); got != actionRerun {
+		t.Fatalf("action = %v, want actionRerun", got)
+	}
+	want, _ := domain.ParseDateRange("2024-01-01", "2024-01-31", time.Local)
+	if !m.rerunRange.Start.Equal(want.Start) || !m.rerunRange.End.Equal(want.End) {
+		t.Errorf("rerunRange = %+v, want %+v", m.rerunRange, want)
+	}
+
+	// 不正な日付はステータスにエラーを表示して再分析しない
+	m.handleKey(key{code: keyRune, r: 'd'})
+	for _, k := range runes("\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f\x7f2024-02-30\r\r") {
+		if got := m.handleKey(k); got == actionRerun {
+			t.Fatal("invalid date should not trigger a rerun")
+		}
+	}
+	if m.status == "" || m.input != inputNone {
+		t.Errorf("status = %q, input = %v; want error status and input closed", m.status, m.input)
+	}
+}
+
+func TestModel_RenderList(t *testing.T) {
+	m := newModel(testDataset(), &domain.DateRange{})
+	lines := m.render(40, 10)
+
+	if len(lines) != 10 {
+		t.Errorf("len(lines) = %d, want 10", len(lines))
+	}
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{"#general", "全期間", "1:スタンプ(3)", ":+1:", "3回"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("screen does not contain %q\n%s", want, screen)
+		}
+	}
+}
+
+// TestModel_RenderFullWidth は全角文字のラベルと本文を端末の表示幅で切り詰め・折り返すことを確認する
+func TestModel_RenderFullWidth(t *testing.T) {
+	msg := &domain.Message{ID: "1.000", ChannelID: "C1", UserID: "U1", Timestamp: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+		Text: "新機能のリリースについてのお知らせです。詳細はドキュメントを確認してください。"}
+	data := &Dataset{
+		Title: "#開発チーム",
+		Tabs: []Tab{
+			{Name: "メッセージ", Unit: "回", Rows: []Row{
+				{Label: msg.Text, Value: 12, Messages: []*domain.Message{msg}},
+				{Label: "短い", Value: 3},
+			}},
+		},
+	}
+	const width = 30
+	plain := strings.NewReplacer(styleReverse, "", styleBold, "", styleDim, "", styleReset, "")
+
+	m := newModel(data, &domain.DateRange{})
+	lines := m.render(width, 10)
+	for _, line := range lines {
+		if got := textwidth.Width(plain.Replace(line)); got > width {
+			t.Errorf("line %q is %d columns wide, want at most %d", plain.Replace(line), got, width)
+		}
+	}
+	// 値の列は行の末尾に揃える
+	for _, line := range lines[3:5] {
+		if got := textwidth.Width(plain.Replace(line)); got != width-1 {
+			t.Errorf("list row %q is %d columns wide, want %d", plain.Replace(line), got, width-1)
+		}
+	}
+
+	m.handleKey(key{code: keyEnter})
+	for _, line := range m.render(width, 10) {
+		if got := textwidth.Width(plain.Replace(line)); got > width {
+			t.Errorf("detail line %q is %d columns wide, want at most %d", plain.Replace(line), got, width)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Tattsum/slack-reaction/internal/domain"
+	"golang.org/x/term"
+)
+
+// ErrNotTerminal は入出力が端末でない場合のエラー
+var ErrNotTerminal = errors.New("TUIは端末上でのみ使用できます")
+
+// Source は指定された期間で分析をやり直し、表示用のデータを返す
+type Source func(ctx context.Context, dateRange *domain.DateRange) (*Dataset, error)
+
+// keyCode はキーの種類
+type keyCode int
+
+const (
+	keyRune keyCode = iota
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyPageUp
+	keyPageDown
+	keyEnter
+	keyTab
+	keyEsc
+	keyBackspace
+	keyCtrlC
+	keyUnknown
+)
+
+// key は入力されたキーを表す
+type key struct {
+	code keyCode
+	r    rune // code が keyRune の場合の文字
+}
+
+// is はキーが指定された文字かどうかを返す
+func (k key) is(r rune) bool {
+	return k.code == keyRune && k.r == r
+}
+
+// decodeKeys は端末から読み取ったバイト列をキーの列に変換する
+func decodeKeys(buf []byte) []key {
+	var keys []key
+	for len(buf) > 0 {
+		if buf[0] == 0x1b {
+			k, n := decodeEscape(buf)
+			keys = append(keys, k)
+			buf = buf[n:]
+			continue
+		}
+		switch buf[0] {
+		case '\r', '\n':
+			keys = append(keys, key{code: keyEnter})
+		case '\t':
+			keys = append(keys, key{code: keyTab})
+		case 0x7f, 0x08:
+			keys = append(keys, key{code: keyBackspace})
+		case 0x03:
+			keys = append(keys, key{code: keyCtrlC})
+		default:
+			r, n := utf8.DecodeRune(buf)
+			if r == utf8.RuneError || r < 0x20 {
+				keys = append(keys, key{code: keyUnknown})
+			} else {
+				keys = append(keys, key{code: keyRune, r: r})
+			}
+			buf = buf[n:]
+			continue
+		}
+		buf = buf[1:]
+	}
+	return keys
+}
+
+// decodeEscape はエスケープシーケンスを1つ変換し、消費したバイト数を返す
+func decodeEscape(buf []byte) (key, int) {
+	if len(buf) < 3 || (buf[1] != '[' && buf[1] != 'O') {
+		return key{code: keyEsc}, 1
+	}
+	switch buf[2] {
+	case 'A':
+		return key{code: keyUp}, 3
+	case 'B':
+		return key{code: keyDown}, 3
+	case 'C':
+		return key{code: keyRight}, 3
+	case 'D':
+		return key{code: keyLeft}, 3
+	}
+	if len(buf) >= 4 && buf[3] == '~' {
+		switch buf[2] {
+		case '5':
+			return key{code: keyPageUp}, 4
+		case '6':
+			return key{code: keyPageDown}, 4
+		}
+	}
+	// 未対応のシーケンスは終端文字まで読み飛ばす
+	for i := 2; i < len(buf); i++ {
+		if buf[i] >= 0x40 && buf[i] <= 0x7e {
+			return key{code: keyUnknown}, i + 1
+		}
+	}
+	return key{code: keyUnknown}, len(buf)
+}
+
+// CheckTerminal は入出力がどちらも端末であることを確認する
+func CheckTerminal(in, out *os.File) error {
+	if !term.IsTerminal(int(in.Fd())) || !term.IsTerminal(int(out.Fd())) {
+		return ErrNotTerminal
+	}
+	return nil
+}
+
+// Run は全画面のTUIを起動し、終了するまでブロックする
+// 期間の変更や再分析の際は source を呼び出してデータを取得し直す
+func Run(ctx context.Context, in, out *os.File, source Source, data *Dataset, dateRange *domain.DateRange) error {
+	if err := CheckTerminal(in, out); err != nil {
+		return err
+	}
+	state, err := term.MakeRaw(int(in.Fd()))
+	if err != nil {
+		return fmt.Errorf("端末の設定エラー: %w", err)
+	}
+	defer term.Restore(int(in.Fd()), state)
+
+	// 代替画面に切り替え、カーソルを非表示にする
+	fmt.Fprint(out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(out, "\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan []key)
+	go readKeys(in, keys)
+
+	m := newModel(data, dateRange)
+	draw := func() {
+		width, height, err := term.GetSize(int(out.Fd()))
+		if err != nil {
+			width, height = 80, 24
+		}
+		drawScreen(out, m.render(width, height))
+	}
+	draw()
+
+	// 端末サイズの変更に追従するため定期的にサイズを確認する
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	lastWidth, lastHeight, _ := term.GetSize(int(out.Fd()))
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			width, height, err := term.GetSize(int(out.Fd()))
+			if err == nil && (width != lastWidth || height != lastHeight) {
+				lastWidth, lastHeight = width, height
+				draw()
+			}
+		case batch, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, k := range batch {
+				switch m.handleKey(k) {
+				case actionQuit:
+					return nil
+				case actionRerun:
+					m.status = "分析中..."
+					draw()
+					newData, err := source(ctx, m.rerunRange)
+					if err != nil {
+						m.status = fmt.Sprintf("分析エラー: %v", err)
+						break
+					}
+					m.setData(newData, m.rerunRange)
+					m.status = "分析が完了しました"
+				}
+			}
+			draw()
+		}
+	}
+}
+
+// readKeys は端末からの入力を読み取り、キーの列として送信する
+func readKeys(in io.Reader, keys chan<- []key) {
+	defer close(keys)
+	buf := make([]byte, 256)
+	for {
+		n, err := in.Read(buf)
+		if n > 0 {
+			keys <- decodeKeys(buf[:n])
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// drawScreen は画面を消去して行を描画する（rawモードのため改行は \r\n）
+func drawScreen(out io.Writer, lines []string) {
+	var b strings.Builder
+	b.WriteString("\x1b[H\x1b[2J")
+	b.WriteString(strings.Join(lines, "\r\n"))
+	io.WriteString(out, b.String())
+}